@@ -0,0 +1,148 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/autobrr/go-qbittorrent"
+)
+
+// How long to wait for qBittorrent to list a newly added torrent
+var addTorrentTimeout = 30 * time.Second
+
+// AddTorrentRequest describes a .torrent to add to qBittorrent pre-matched to
+// files on disk. Renames and Unwanted use the torrent's original file names.
+type AddTorrentRequest struct {
+	TorrentPath string            `json:"torrentPath"`
+	SavePath    string            `json:"savePath"` // the scanned directory renames are relative to
+	Renames     []RenameOperation `json:"renames"`
+	Unwanted    []string          `json:"unwanted"` // files to set to "do not download"
+	Category    string            `json:"category"`
+	Tags        []string          `json:"tags"`
+	Recheck     bool              `json:"recheck"`
+	Resume      bool              `json:"resume"` // with Recheck, only resumed once every wanted file verified
+}
+
+// AddTorrentResult represents the outcome of adding a pre-matched torrent
+type AddTorrentResult struct {
+	Hash         string         `json:"hash"`
+	RenamedCount int            `json:"renamedCount"`
+	FailedCount  int            `json:"failedCount"`
+	SkippedCount int            `json:"skippedCount"`
+	Report       *RecheckReport `json:"report"`
+	Resumed      bool           `json:"resumed"`
+}
+
+// AddMatchedTorrent adds a .torrent paused with the given save path, applies
+// renames and unwanted-file priorities, then optionally rechecks and resumes it
+func (s *QBitService) AddMatchedTorrent(req AddTorrentRequest) (AddTorrentResult, error) {
+	if s.client == nil {
+		return AddTorrentResult{}, ErrNotConnected
+	}
+
+	data, err := os.ReadFile(req.TorrentPath)
+	if err != nil {
+		return AddTorrentResult{}, err
+	}
+	meta, err := ParseTorrent(data)
+	if err != nil {
+		return AddTorrentResult{}, err
+	}
+	result := AddTorrentResult{Hash: meta.InfoHash}
+
+	existing, err := s.client.GetTorrents(qbittorrent.TorrentFilterOptions{Hashes: []string{meta.InfoHash}})
+	if err != nil {
+		return result, err
+	}
+	if len(existing) > 0 {
+		return result, fmt.Errorf("torrent %s is already in qBittorrent, use match instead", meta.InfoHash)
+	}
+
+	addOptions := qbittorrent.TorrentAddOptions{
+		Paused:   true,
+		SavePath: req.SavePath,
+		Category: req.Category,
+		Tags:     strings.Join(req.Tags, ","),
+	}
+	options := addOptions.Prepare()
+	// Renames are computed against the torrent's own layout
+	options["contentLayout"] = string(qbittorrent.ContentLayoutOriginal)
+
+	if err := s.client.AddTorrentFromMemory(data, options); err != nil {
+		return result, fmt.Errorf("failed to add torrent: %w", err)
+	}
+
+	files, err := s.waitForTorrentFiles(meta.InfoHash)
+	if err != nil {
+		return result, err
+	}
+
+	// Map names to qBittorrent's indices, which skip files it hides (e.g. pad files)
+	indexByName := make(map[string]int, len(files))
+	for _, f := range files {
+		indexByName[f.Name] = f.Index
+	}
+
+	applied := s.ApplyRenames(meta.InfoHash, req.Renames)
+	result.RenamedCount = applied.RenamedCount
+	result.FailedCount = applied.FailedCount
+
+	var unwanted []string
+	for _, name := range req.Unwanted {
+		if index, ok := indexByName[name]; ok {
+			unwanted = append(unwanted, strconv.Itoa(index))
+		}
+	}
+	if len(unwanted) > 0 {
+		if err := s.SetFilePriority(meta.InfoHash, strings.Join(unwanted, ","), 0); err != nil {
+			return result, fmt.Errorf("failed to set priority: %w", err)
+		}
+		result.SkippedCount = len(unwanted)
+	}
+
+	if req.Recheck {
+		if err := s.RecheckTorrent(meta.InfoHash); err != nil {
+			return result, fmt.Errorf("failed to trigger recheck: %w", err)
+		}
+		if req.Resume {
+			report, err := s.WaitForRecheck(meta.InfoHash, 0)
+			if err != nil {
+				return result, err
+			}
+			result.Report = &report
+			if report.TimedOut || report.IncompleteCount > 0 {
+				return result, nil
+			}
+		}
+	}
+
+	if req.Resume {
+		if err := s.ResumeTorrent(meta.InfoHash); err != nil {
+			return result, fmt.Errorf("failed to resume torrent: %w", err)
+		}
+		result.Resumed = true
+	}
+
+	return result, nil
+}
+
+// waitForTorrentFiles polls until qBittorrent lists the torrent's files
+func (s *QBitService) waitForTorrentFiles(hash string) ([]TorrentFile, error) {
+	deadline := time.Now().Add(addTorrentTimeout)
+	for {
+		files, err := s.GetTorrentFiles(hash)
+		if err == nil && len(files) > 0 {
+			return files, nil
+		}
+		if time.Now().After(deadline) {
+			if err != nil {
+				return nil, fmt.Errorf("torrent %s did not appear in qBittorrent: %w", hash, err)
+			}
+			return nil, fmt.Errorf("torrent %s did not appear in qBittorrent", hash)
+		}
+		time.Sleep(recheckPollInterval)
+	}
+}
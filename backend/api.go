@@ -0,0 +1,471 @@
+package backend
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIServer exposes QBitService and MatcherService as a JSON HTTP API for
+// headless use. Every request must carry the token as a bearer token or in
+// the X-API-Token header.
+type APIServer struct {
+	qbit    *QBitService
+	matcher *MatcherService
+	jobs    *JobService
+	token   string
+}
+
+// APIPlanRequest is the body of a plan call: match a torrent against a directory
+type APIPlanRequest struct {
+	Hash                 string `json:"hash"`
+	Path                 string `json:"path"`
+	RequireSameExtension bool   `json:"requireSameExtension"`
+	SizeTolerance        int64  `json:"sizeTolerance"`
+	Archives             bool   `json:"archives"`     // also match files inside zip and tar archives
+	PartialFiles         bool   `json:"partialFiles"` // also offer smaller, partially downloaded files
+	SplitJoin            bool   `json:"splitJoin"`    // also rebuild files split into parts or concatenated
+	Fingerprints         bool   `json:"fingerprints"` // sample same-size files to collapse identical copies
+	// Reference is a directory holding copies of the torrent's files under
+	// their torrent paths; candidates are compared to their fingerprints
+	Reference string `json:"reference"`
+	// RulesProfile names the rename rules applied before size matching
+	RulesProfile string `json:"rulesProfile"`
+	// ReuseDuplicates selects, for files nothing was selected for, the
+	// complete copy of another torrent sharing the file, see ReuseDuplicates
+	ReuseDuplicates bool `json:"reuseDuplicates"`
+	// Link plans links into the torrent's save path instead of renames.
+	// SavePath overrides the save path qBittorrent reports, e.g. when its
+	// paths differ from this machine's.
+	Link     bool   `json:"link"`
+	SavePath string `json:"savePath"`
+}
+
+// APIPlan is the matching result and the renames that would be applied
+type APIPlan struct {
+	Matches      []MatchInfo       `json:"matches"`
+	Unmatched    []TorrentFileInfo `json:"unmatched"`
+	Skipped      []TorrentFileInfo `json:"skipped"`
+	TotalFiles   int               `json:"totalFiles"`
+	MatchedCount int               `json:"matchedCount"`
+	Outcome      string            `json:"outcome"`
+	Extractions  []Extraction      `json:"extractions"` // to do before the renames
+	Rebuilds     []Rebuild         `json:"rebuilds"`    // likewise
+	Renames      []RenameOperation `json:"renames"`
+	Links        []LinkOperation   `json:"links"` // instead of renames, when requested
+	// Pieces counts the pieces of each file that would pass, e.g. those a
+	// partial download salvages, when partial or rebuilt files were selected
+	Pieces     *PieceReport `json:"pieces,omitempty"`
+	RuleErrors []string     `json:"ruleErrors,omitempty"` // torrent files a rule failed to map
+}
+
+// NewAPIServer creates an API server for the given services
+func NewAPIServer(qbit *QBitService, matcher *MatcherService, jobs *JobService, token string) *APIServer {
+	return &APIServer{
+		qbit:    qbit,
+		matcher: matcher,
+		jobs:    jobs,
+		token:   token,
+	}
+}
+
+// GenerateAPIToken returns a random token for servers started without one
+func GenerateAPIToken() (string, error) {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Handler returns the API routes, all under /api/
+func (a *APIServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/status", a.handleStatus)
+	mux.HandleFunc("POST /api/connect", a.handleConnect)
+	mux.HandleFunc("POST /api/disconnect", a.handleDisconnect)
+	mux.HandleFunc("GET /api/torrents", a.handleTorrents)
+	mux.HandleFunc("GET /api/torrents/{hash}/files", a.handleTorrentFiles)
+	mux.HandleFunc("POST /api/torrents/{hash}/apply", a.handleApply)
+	mux.HandleFunc("POST /api/torrents/{hash}/undo", a.handleUndo)
+	mux.HandleFunc("POST /api/scan", a.handleScan)
+	mux.HandleFunc("POST /api/match", a.handleMatch)
+	mux.HandleFunc("POST /api/plan", a.handlePlan)
+	mux.HandleFunc("POST /api/jobs/scan", a.handleScanJob)
+	mux.HandleFunc("GET /api/jobs", a.handleJobs)
+	mux.HandleFunc("GET /api/jobs/{id}", a.handleJob)
+	mux.HandleFunc("POST /api/jobs/{id}/cancel", a.handleCancelJob)
+	mux.HandleFunc("DELETE /api/jobs/{id}", a.handleRemoveJob)
+	return a.authenticate(mux)
+}
+
+func (a *APIServer) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !validToken(requestToken(r), a.token) {
+			writeError(w, http.StatusUnauthorized, errors.New("invalid or missing API token"))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// webTokenCookie keeps the API token of a browser that opened the web UI
+// with ?token=
+const webTokenCookie = "qbt_api_token"
+
+// RequireWebToken protects the web UI, and the service bindings it calls
+// through the Wails runtime, with the API token. Browsers open the UI once
+// with ?token=<token>, which is then kept in a cookie; scripts can send the
+// token as for the API.
+func RequireWebToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if query := r.URL.Query().Get("token"); query != "" && validToken(query, token) {
+			http.SetCookie(w, &http.Cookie{
+				Name:     webTokenCookie,
+				Value:    query,
+				Path:     "/",
+				HttpOnly: true,
+				SameSite: http.SameSiteStrictMode,
+			})
+			// Keep the token out of the address bar and history
+			if r.Method == http.MethodGet {
+				clean := *r.URL
+				values := clean.Query()
+				values.Del("token")
+				clean.RawQuery = values.Encode()
+				http.Redirect(w, r, clean.RequestURI(), http.StatusSeeOther)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		presented := requestToken(r)
+		if cookie, err := r.Cookie(webTokenCookie); err == nil && presented == "" {
+			presented = cookie.Value
+		}
+		if !validToken(presented, token) {
+			http.Error(w, "Invalid or missing API token, open this page with ?token=<token>", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestToken returns the token sent as a bearer token or in the
+// X-API-Token header
+func requestToken(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		return strings.TrimPrefix(auth, "Bearer ")
+	}
+	return r.Header.Get("X-API-Token")
+}
+
+// validToken compares tokens in constant time; nothing is valid without an
+// expected token
+func validToken(token string, expected string) bool {
+	return expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+func (a *APIServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status := map[string]any{"connected": a.qbit.IsConnected()}
+	if version, err := a.qbit.GetVersion(); err == nil {
+		status["version"] = version
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+func (a *APIServer) handleConnect(w http.ResponseWriter, r *http.Request) {
+	var config ConnectionConfig
+	if !readJSON(w, r, &config) {
+		return
+	}
+	if err := a.qbit.Connect(config); err != nil {
+		writeError(w, http.StatusBadGateway, err)
+		return
+	}
+	version, _ := a.qbit.GetVersion()
+	writeJSON(w, http.StatusOK, map[string]any{"connected": true, "version": version})
+}
+
+func (a *APIServer) handleDisconnect(w http.ResponseWriter, r *http.Request) {
+	if err := a.qbit.Disconnect(); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"connected": false})
+}
+
+func (a *APIServer) handleTorrents(w http.ResponseWriter, r *http.Request) {
+	torrents, err := a.qbit.GetTorrents()
+	if err != nil {
+		writeQBitError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, torrents)
+}
+
+func (a *APIServer) handleTorrentFiles(w http.ResponseWriter, r *http.Request) {
+	files, err := a.qbit.GetTorrentFiles(r.PathValue("hash"))
+	if err != nil {
+		writeQBitError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, files)
+}
+
+func (a *APIServer) handleScan(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Path string `json:"path"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if !a.matcher.DirExists(req.Path) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("directory not found: %s", req.Path))
+		return
+	}
+	files, err := a.matcher.ScanDir(req.Path)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, files)
+}
+
+func (a *APIServer) handleMatch(w http.ResponseWriter, r *http.Request) {
+	var req MatchRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	response, err := a.matcher.FindMatches(req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+func (a *APIServer) handlePlan(w http.ResponseWriter, r *http.Request) {
+	var req APIPlanRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	if req.Hash == "" || req.Path == "" {
+		writeError(w, http.StatusBadRequest, errors.New("hash and path are required"))
+		return
+	}
+
+	files, err := a.qbit.GetTorrentFiles(req.Hash)
+	if err != nil {
+		writeQBitError(w, err)
+		return
+	}
+	torrentFiles := make([]TorrentFileInfo, len(files))
+	for i, f := range files {
+		torrentFiles[i] = TorrentFileInfo{Index: f.Index, Name: f.Name, Size: f.Size}
+	}
+
+	diskFiles, err := ScanDirectory(req.Path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("failed to scan directory: %w", err))
+		return
+	}
+	if req.Archives {
+		entries, err := ScanArchives(r.Context(), diskFiles)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		diskFiles = append(diskFiles, entries...)
+	}
+	if req.Fingerprints || req.Reference != "" {
+		if err := FingerprintCandidates(r.Context(), torrentFiles, diskFiles, req.Reference, DefaultFingerprintCache()); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	var rules *RuleSet
+	if req.RulesProfile != "" {
+		if rules, err = LoadRules("", req.RulesProfile); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+	}
+
+	result := FindMatchesWithOptions(torrentFiles, diskFiles, MatchOptions{
+		RequireSameExtension: req.RequireSameExtension,
+		SizeTolerance:        req.SizeTolerance,
+		PartialFiles:         req.PartialFiles,
+		SplitJoin:            req.SplitJoin,
+		Rules:                rules,
+	})
+	if req.ReuseDuplicates {
+		report, err := a.qbit.FindDuplicateFiles(true)
+		if err != nil {
+			writeQBitError(w, err)
+			return
+		}
+		ReuseDuplicates(&result, req.Hash, report.Groups)
+	}
+
+	// Partial downloads are only proposed when some of their pieces pass,
+	// and joins and splits when all of their pieces do
+	var pieces *PieceReport
+	if NeedsPieceVerification(result.Matches) {
+		layout, err := a.qbit.GetPieceLayout(req.Hash)
+		if err != nil {
+			writeQBitError(w, err)
+			return
+		}
+		report, err := VerifySources(r.Context(), layout, SelectedSources(result.Matches))
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		result.MatchedCount -= UnselectUnsalvaged(result.Matches, report)
+		result.MatchedCount -= UnselectBrokenReassemblies(result.Matches, report)
+		pieces = &report
+	}
+
+	renames := GenerateRenames(result.Matches, req.Path)
+	links := []LinkOperation{}
+	if req.Link {
+		savePath := req.SavePath
+		if savePath == "" {
+			torrent, err := a.qbit.GetTorrent(req.Hash)
+			if err != nil {
+				writeQBitError(w, err)
+				return
+			}
+			savePath = torrent.SavePath
+		}
+		links = GenerateLinks(result.Matches, savePath)
+		renames = []RenameOperation{}
+	}
+
+	plan := APIPlan{
+		Matches:      make([]MatchInfo, len(result.Matches)),
+		Unmatched:    result.Unmatched,
+		Skipped:      result.Skipped,
+		TotalFiles:   result.TotalFiles,
+		MatchedCount: result.MatchedCount,
+		Outcome:      ClassifyOutcome(result.Matches, len(result.Unmatched)),
+		Extractions:  Extractions(result.Matches),
+		Rebuilds:     Rebuilds(result.Matches),
+		Renames:      CollapseFolderRenames(renames, torrentFiles),
+		Links:        links,
+		Pieces:       pieces,
+		RuleErrors:   result.RuleErrors,
+	}
+	for i, m := range result.Matches {
+		plan.Matches[i] = MatchInfo(m)
+	}
+	writeJSON(w, http.StatusOK, plan)
+}
+
+// handleApply starts applying renames as a job, as waiting for a recheck can
+// take far longer than an HTTP request should
+func (a *APIServer) handleApply(w http.ResponseWriter, r *http.Request) {
+	var req ApplyJobRequest
+	if !readJSON(w, r, &req) {
+		return
+	}
+	req.Hash = r.PathValue("hash")
+	job, err := a.jobs.StartApply(req)
+	if err != nil {
+		writeQBitError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (a *APIServer) handleUndo(w http.ResponseWriter, r *http.Request) {
+	job, err := a.jobs.StartUndo(r.PathValue("hash"))
+	if err != nil {
+		writeQBitError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (a *APIServer) handleScanJob(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Path string `json:"path"`
+	}
+	if !readJSON(w, r, &req) {
+		return
+	}
+	job, err := a.jobs.StartScan(req.Path)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, job)
+}
+
+func (a *APIServer) handleJobs(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, a.jobs.ListJobs())
+}
+
+func (a *APIServer) handleJob(w http.ResponseWriter, r *http.Request) {
+	job, err := a.jobs.GetJob(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+func (a *APIServer) handleCancelJob(w http.ResponseWriter, r *http.Request) {
+	if err := a.jobs.CancelJob(r.PathValue("id")); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]bool{"cancelled": true})
+}
+
+func (a *APIServer) handleRemoveJob(w http.ResponseWriter, r *http.Request) {
+	if err := a.jobs.RemoveJob(r.PathValue("id")); err != nil {
+		writeError(w, http.StatusConflict, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func readJSON(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeQBitError reports qBittorrent failures, distinguishing a missing
+// connection and nothing to undo
+func writeQBitError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	switch {
+	case errors.Is(err, ErrNotConnected):
+		status = http.StatusConflict
+	case errors.Is(err, ErrNoAppliedRenames):
+		status = http.StatusNotFound
+	}
+	writeError(w, status, err)
+}
@@ -0,0 +1,247 @@
+package backend
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func apiRequest(t *testing.T, handler http.Handler, method, path, token, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func newTestAPIServer(t *testing.T, token string) *APIServer {
+	t.Helper()
+	jobs, err := NewJobManager("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	qbit := &QBitService{}
+	return NewAPIServer(qbit, &MatcherService{}, &JobService{Jobs: jobs, QBit: qbit}, token)
+}
+
+func TestAPIServer_RequiresToken(t *testing.T) {
+	handler := newTestAPIServer(t, "secret").Handler()
+
+	if rec := apiRequest(t, handler, "GET", "/api/status", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", rec.Code)
+	}
+	if rec := apiRequest(t, handler, "GET", "/api/status", "wrong", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 with wrong token, got %d", rec.Code)
+	}
+	if rec := apiRequest(t, handler, "GET", "/api/status", "secret", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected 200 with token, got %d", rec.Code)
+	}
+}
+
+func TestAPIServer_EmptyTokenRejectsAll(t *testing.T) {
+	handler := newTestAPIServer(t, "").Handler()
+
+	if rec := apiRequest(t, handler, "GET", "/api/status", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 when no token is configured, got %d", rec.Code)
+	}
+}
+
+func TestAPIServer_Scan(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "video.mkv"), []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	handler := newTestAPIServer(t, "secret").Handler()
+
+	body, _ := json.Marshal(map[string]string{"path": dir})
+	rec := apiRequest(t, handler, "POST", "/api/scan", "secret", string(body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var files []DiskFileInfo
+	if err := json.NewDecoder(rec.Body).Decode(&files); err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0].Name != "video.mkv" || files[0].Size != 4 {
+		t.Errorf("Unexpected scan result: %+v", files)
+	}
+
+	rec = apiRequest(t, handler, "POST", "/api/scan", "secret", `{"path":"/does/not/exist"}`)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for missing directory, got %d", rec.Code)
+	}
+}
+
+func TestAPIServer_NotConnected(t *testing.T) {
+	handler := newTestAPIServer(t, "secret").Handler()
+
+	if rec := apiRequest(t, handler, "GET", "/api/torrents", "secret", ""); rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for torrents when not connected, got %d", rec.Code)
+	}
+	if rec := apiRequest(t, handler, "POST", "/api/torrents/abc/apply", "secret", `{"renames":[]}`); rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for apply when not connected, got %d", rec.Code)
+	}
+	if rec := apiRequest(t, handler, "POST", "/api/torrents/abc/undo", "secret", ""); rec.Code != http.StatusConflict {
+		t.Errorf("Expected 409 for undo when not connected, got %d", rec.Code)
+	}
+}
+
+func TestAPIServer_ScanJob(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "video.mkv"), []byte("data"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	server := newTestAPIServer(t, "secret")
+	handler := server.Handler()
+
+	body, _ := json.Marshal(map[string]string{"path": dir})
+	rec := apiRequest(t, handler, "POST", "/api/jobs/scan", "secret", string(body))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d: %s", rec.Code, rec.Body.String())
+	}
+	var job Job
+	if err := json.NewDecoder(rec.Body).Decode(&job); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := server.jobs.Jobs.Wait(context.Background(), job.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	rec = apiRequest(t, handler, "GET", "/api/jobs/"+job.ID, "secret", "")
+	if err := json.NewDecoder(rec.Body).Decode(&job); err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != JobCompleted || !strings.Contains(string(job.Result), "video.mkv") {
+		t.Errorf("Expected completed job with scan result, got %s: %s", job.Status, job.Result)
+	}
+}
+
+func TestUndoRenames(t *testing.T) {
+	applied := []RenameOperation{
+		{OldPath: "a/1.mkv", NewPath: "b/1.mkv"},
+		{OldPath: "old", NewPath: "new", IsFolder: true, FileCount: 3},
+	}
+
+	undo := UndoRenames(applied)
+
+	if len(undo) != 2 {
+		t.Fatalf("Expected 2 operations, got %d", len(undo))
+	}
+	if undo[0].OldPath != "new" || undo[0].NewPath != "old" || !undo[0].IsFolder {
+		t.Errorf("Expected folder rename undone first, got %+v", undo[0])
+	}
+	if undo[1].OldPath != "b/1.mkv" || undo[1].NewPath != "a/1.mkv" {
+		t.Errorf("Unexpected file undo: %+v", undo[1])
+	}
+}
+
+func TestAPIServer_PlanVerifiesPartialFiles(t *testing.T) {
+	// Torrent contents, with the pieces hashed from the complete files
+	var hashes []string
+	for _, piece := range []string{"0123", "4567", "89AB", "abcd", "efgh", "ijkl"} {
+		sum := sha1.Sum([]byte(piece))
+		hashes = append(hashes, hex.EncodeToString(sum[:]))
+	}
+	qbit := newFakeQBitService(t, fakeTorrent{
+		State: "pausedUP",
+		Files: []TorrentFile{
+			{Index: 0, Name: "Show/Show.S01E01.mkv", Size: 12, Priority: 1},
+			{Index: 1, Name: "Show/Show.S01E02.mkv", Size: 12, Priority: 1},
+		},
+		PieceLength: 4,
+		PieceHashes: hashes,
+	})
+	handler := NewAPIServer(qbit, &MatcherService{}, &JobService{QBit: qbit}, "secret").Handler()
+
+	// Aborted downloads, of which only the first episode has pieces worth keeping
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Show.S01E01.mkv"), []byte("01234567"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "Show.S01E02.mkv"), []byte("zzzzzzzz"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	body, _ := json.Marshal(APIPlanRequest{Hash: "abc", Path: dir, PartialFiles: true})
+	rec := apiRequest(t, handler, "POST", "/api/plan", "secret", string(body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var plan APIPlan
+	if err := json.NewDecoder(rec.Body).Decode(&plan); err != nil {
+		t.Fatal(err)
+	}
+	if plan.MatchedCount != 1 || len(plan.Renames) != 1 || plan.Renames[0].OldPath != "Show/Show.S01E01.mkv" {
+		t.Errorf("Expected only the first episode to be renamed, got %d matched, renames %+v", plan.MatchedCount, plan.Renames)
+	}
+	if plan.Pieces == nil || len(plan.Pieces.Files) != 2 || plan.Pieces.Files[0].Passed != 2 || plan.Pieces.Files[1].Passed != 0 {
+		t.Errorf("Expected the salvaged pieces of each file, got %+v", plan.Pieces)
+	}
+}
+
+func TestRequireWebToken(t *testing.T) {
+	handler := RequireWebToken("secret", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ui"))
+	}))
+
+	// Runtime calls to the service bindings need the token too
+	if rec := apiRequest(t, handler, "POST", "/wails/runtime", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", rec.Code)
+	}
+	if rec := apiRequest(t, handler, "GET", "/?token=wrong", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 for a wrong token, got %d", rec.Code)
+	}
+	if rec := apiRequest(t, handler, "POST", "/wails/runtime", "secret", ""); rec.Code != http.StatusOK {
+		t.Errorf("Expected a bearer token to be accepted, got %d", rec.Code)
+	}
+
+	// Opening the UI with the token keeps it in a cookie
+	rec := apiRequest(t, handler, "GET", "/?token=secret&tab=jobs", "", "")
+	if rec.Code != http.StatusSeeOther || rec.Header().Get("Location") != "/?tab=jobs" {
+		t.Fatalf("Expected a redirect without the token, got %d to %q", rec.Code, rec.Header().Get("Location"))
+	}
+	cookies := rec.Result().Cookies()
+	if len(cookies) != 1 || !cookies[0].HttpOnly {
+		t.Fatalf("Expected an HTTP-only token cookie, got %+v", cookies)
+	}
+	req := httptest.NewRequest("POST", "/wails/runtime", nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected the cookie to be accepted, got %d", rec.Code)
+	}
+}
+
+func TestWriteQBitError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{fmt.Errorf("failed to get torrents: %w", ErrNotConnected), http.StatusConflict},
+		{fmt.Errorf("%w for abc", ErrNoAppliedRenames), http.StatusNotFound},
+		{errors.New("connection refused"), http.StatusBadGateway},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		writeQBitError(rec, tt.err)
+		if rec.Code != tt.expected {
+			t.Errorf("Expected %d for %q, got %d", tt.expected, tt.err, rec.Code)
+		}
+	}
+}
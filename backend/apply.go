@@ -0,0 +1,128 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// ApplyResult represents the outcome of applying rename operations
+type ApplyResult struct {
+	RenamedCount int               `json:"renamedCount"` // files moved, counting every file of a folder rename
+	FailedCount  int               `json:"failedCount"`
+	Applied      []RenameOperation `json:"applied"` // operations that succeeded, in order
+	Errors       []string          `json:"errors"`
+	JournalID    string            `json:"journalId,omitempty"` // journal of the applied renames, when kept
+}
+
+// ApplyRenames renames files and folders of a torrent in qBittorrent. A
+// failed operation is recorded and the remaining ones are still applied.
+// With a JournalDir, applied renames are journaled so they can be reverted
+// later.
+func (s *QBitService) ApplyRenames(hash string, renames []RenameOperation) ApplyResult {
+	result, _ := s.applyRenames(context.Background(), hash, renames, true, nil)
+	return result
+}
+
+// applyRenames is ApplyRenames with cancellation between operations.
+// journaled is false for renames that undo a journal, so undoing does not
+// leave a journal of its own. progress, when set, is called after each
+// operation.
+func (s *QBitService) applyRenames(ctx context.Context, hash string, renames []RenameOperation, journaled bool, progress func(done, total int)) (result ApplyResult, err error) {
+	result = ApplyResult{Applied: []RenameOperation{}, Errors: []string{}}
+	var journal *MoveJournal
+	if journaled && s.JournalDir != "" && len(renames) > 0 {
+		if journal, err = newMoveJournal(s.JournalDir, hash); err != nil {
+			journal = nil
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to create journal, renames can't be reverted later: %v", err))
+		} else {
+			result.JournalID = journal.ID
+			defer func() {
+				// Nothing to revert
+				if len(journal.Renames) == 0 {
+					os.Remove(journal.path)
+					result.JournalID = ""
+				}
+			}()
+		}
+	}
+
+	for i, r := range renames {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		var err error
+		fileCount := 1
+		if r.IsFolder {
+			err = s.RenameFolder(hash, r.OldPath, r.NewPath)
+			fileCount = r.FileCount
+		} else {
+			err = s.RenameFile(hash, r.OldPath, r.NewPath)
+		}
+		if err != nil {
+			result.FailedCount += fileCount
+			metricRenames.Add(float64(fileCount), "failed")
+			result.Errors = append(result.Errors, fmt.Sprintf("failed to rename %s: %v", r.OldPath, err))
+		} else {
+			result.RenamedCount += fileCount
+			metricRenames.Add(float64(fileCount), "applied")
+			result.Applied = append(result.Applied, r)
+			if journal != nil {
+				if err := journal.recordRename(r); err != nil {
+					result.Errors = append(result.Errors, fmt.Sprintf("failed to journal rename of %s: %v", r.OldPath, err))
+				}
+			}
+		}
+
+		if progress != nil {
+			progress(i+1, len(renames))
+		}
+	}
+
+	return result, nil
+}
+
+// UndoRenameJournal renames a journal's renames back, newest first. Renames
+// that fail to undo stay in the journal so undoing again retries them; the
+// journal is marked undone once all of them were.
+func (s *QBitService) UndoRenameJournal(journal *MoveJournal) (ApplyResult, error) {
+	if journal.Undone {
+		return ApplyResult{}, fmt.Errorf("journal %s was already undone", journal.ID)
+	}
+	if !journal.IsRenames() {
+		return ApplyResult{}, fmt.Errorf("journal %s holds no renames", journal.ID)
+	}
+	result, err := s.applyRenames(context.Background(), journal.Hash, UndoRenames(journal.Renames), false, nil)
+	if err != nil {
+		return result, err
+	}
+	if result.FailedCount == 0 {
+		journal.Undone = true
+		return result, journal.save()
+	}
+	// Keep what is left to undo
+	undone := UndoRenames(result.Applied)
+	journal.Renames = slices.DeleteFunc(journal.Renames, func(r RenameOperation) bool {
+		i := slices.IndexFunc(undone, func(u RenameOperation) bool { return u.OldPath == r.OldPath && u.NewPath == r.NewPath })
+		if i >= 0 {
+			undone = slices.Delete(undone, i, i+1)
+			return true
+		}
+		return false
+	})
+	return result, journal.save()
+}
+
+// UndoRenames returns the operations that move applied renames back to their
+// original paths, in reverse order so chained renames unwind correctly
+func UndoRenames(applied []RenameOperation) []RenameOperation {
+	undo := make([]RenameOperation, 0, len(applied))
+	for i := len(applied) - 1; i >= 0; i-- {
+		r := applied[i]
+		r.OldPath, r.NewPath = r.NewPath, r.OldPath
+		undo = append(undo, r)
+	}
+	return undo
+}
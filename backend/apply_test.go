@@ -0,0 +1,134 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+)
+
+// fakeTorrent is torrent "abc" as a fake qBittorrent reports it
+type fakeTorrent struct {
+	State       string
+	Files       []TorrentFile
+	PieceLength int64
+	PieceHashes []string
+}
+
+// newFakeQBitService starts a qBittorrent answering logins, file renames,
+// which fail for "bad.mkv", and lookups of torrent "abc"
+func newFakeQBitService(t *testing.T, torrent fakeTorrent) *QBitService {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v2/auth/login":
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "test"})
+			_, _ = w.Write([]byte("Ok."))
+		case "/api/v2/torrents/renameFile":
+			if r.FormValue("oldPath") == "bad.mkv" {
+				http.Error(w, "conflict", http.StatusConflict)
+			}
+		case "/api/v2/torrents/info":
+			_ = json.NewEncoder(w).Encode([]map[string]any{{"hash": "abc", "name": "Show", "state": torrent.State}})
+		case "/api/v2/torrents/files":
+			_ = json.NewEncoder(w).Encode(torrent.Files)
+		case "/api/v2/torrents/properties":
+			_ = json.NewEncoder(w).Encode(map[string]any{"piece_size": torrent.PieceLength})
+		case "/api/v2/torrents/pieceHashes":
+			_ = json.NewEncoder(w).Encode(torrent.PieceHashes)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	service := &QBitService{JournalDir: t.TempDir()}
+	if err := service.Connect(ConnectionConfig{URL: server.URL}); err != nil {
+		t.Fatal(err)
+	}
+	return service
+}
+
+func TestApplyRenames_Journal(t *testing.T) {
+	service := newFakeQBitService(t, fakeTorrent{State: "pausedUP", Files: []TorrentFile{
+		{Index: 0, Name: "Show/E01.mkv", Size: 9, Progress: 0.5, Priority: 1},
+		{Index: 1, Name: "bad.mkv", Size: 9, Progress: 1, Priority: 1},
+	}})
+
+	result := service.ApplyRenames("abc", []RenameOperation{
+		{OldPath: "E01.mkv", NewPath: "Show/E01.mkv"},
+		{OldPath: "bad.mkv", NewPath: "Show/bad.mkv"},
+	})
+	if result.RenamedCount != 1 || result.FailedCount != 1 || result.JournalID == "" {
+		t.Fatalf("Expected 1 journaled rename and 1 failure, got %+v", result)
+	}
+
+	journal, err := LoadJournal(service.JournalDir, result.JournalID)
+	if err != nil || !journal.IsRenames() || len(journal.Renames) != 1 || journal.Hash != "abc" {
+		t.Fatalf("Expected a journal of the applied rename, got %+v (%v)", journal, err)
+	}
+	if _, _, err := UndoMoves(context.Background(), journal); err == nil {
+		t.Error("Expected error undoing renames as moves")
+	}
+
+	// Reverted from the journal alone, e.g. after a restart
+	reverts, report, err := service.JournalReverts(journal)
+	if err != nil || report.IncompleteCount != 1 {
+		t.Fatalf("Expected 1 incomplete file, got %+v (%v)", report, err)
+	}
+	if len(reverts) != 1 || reverts[0].OldPath != "Show/E01.mkv" || reverts[0].NewPath != "E01.mkv" {
+		t.Errorf("Expected Show/E01.mkv to be reverted, got %+v", reverts)
+	}
+
+	undo, err := service.UndoRenameJournal(journal)
+	if err != nil || undo.RenamedCount != 1 {
+		t.Fatalf("Expected 1 rename undone, got %+v (%v)", undo, err)
+	}
+	if reloaded, _ := LoadJournal(service.JournalDir, journal.ID); !reloaded.Undone {
+		t.Error("Expected journal to be marked undone")
+	}
+}
+
+func TestApplyRenames_NoJournalWithoutRenames(t *testing.T) {
+	service := newFakeQBitService(t, fakeTorrent{State: "pausedUP"})
+
+	result := service.ApplyRenames("abc", []RenameOperation{{OldPath: "bad.mkv", NewPath: "Show/bad.mkv"}})
+	if result.FailedCount != 1 || result.JournalID != "" {
+		t.Errorf("Expected no journal when every rename fails, got %+v", result)
+	}
+	if entries, _ := os.ReadDir(service.JournalDir); len(entries) != 0 {
+		t.Errorf("Expected the empty journal to be removed, found %d files", len(entries))
+	}
+}
+
+func TestUndoRenameJournal_KeepsFailedRenames(t *testing.T) {
+	service := newFakeQBitService(t, fakeTorrent{State: "pausedUP"})
+
+	// Renaming bad.mkv back fails
+	result := service.ApplyRenames("abc", []RenameOperation{
+		{OldPath: "E01.mkv", NewPath: "Show/E01.mkv"},
+		{OldPath: "E02.mkv", NewPath: "bad.mkv"},
+	})
+	journal, err := LoadJournal(service.JournalDir, result.JournalID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	undo, err := service.UndoRenameJournal(journal)
+	if err != nil || undo.RenamedCount != 1 || undo.FailedCount != 1 {
+		t.Fatalf("Expected 1 rename undone and 1 failure, got %+v (%v)", undo, err)
+	}
+	reloaded, err := LoadJournal(service.JournalDir, journal.ID)
+	if err != nil || reloaded.Undone || len(reloaded.Renames) != 1 || reloaded.Renames[0].NewPath != "bad.mkv" {
+		t.Fatalf("Expected the failed rename to be left to undo, got %+v (%v)", reloaded, err)
+	}
+	if entries, _ := os.ReadDir(service.JournalDir); len(entries) != 1 {
+		t.Errorf("Expected undoing not to journal its own renames, found %d journals", len(entries))
+	}
+
+	if _, err := service.UndoRenameJournal(reloaded); err != nil {
+		t.Errorf("Expected a partly undone journal to be retried, got %v", err)
+	}
+}
@@ -0,0 +1,309 @@
+package backend
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// IsArchiveEntry reports whether the file only exists inside an archive and
+// has to be extracted before qBittorrent can use it
+func (f DiskFile) IsArchiveEntry() bool {
+	return f.Archive != ""
+}
+
+// archiveKind returns "zip", "tar" or "tar.gz" for supported archives
+func archiveKind(name string) string {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip"
+	case strings.HasSuffix(lower, ".tar"):
+		return "tar"
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tar.gz"
+	default:
+		return ""
+	}
+}
+
+// ScanArchives lists the files inside the zip and tar archives among files
+// as virtual disk files. Each entry's Path is where it would be extracted:
+// next to its archive, keeping the folders inside the archive. Entries that
+// are already extracted there, and zip entries repeating the size and CRC of
+// an entry listed before, are left out. Unreadable archives are skipped.
+func ScanArchives(ctx context.Context, files []DiskFile) ([]DiskFile, error) {
+	existing := make(map[string]int64, len(files))
+	for _, f := range files {
+		existing[f.Path] = f.Size
+	}
+
+	type zipKey struct {
+		size int64
+		crc  uint32
+	}
+	seen := make(map[zipKey]bool)
+
+	var entries []DiskFile
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		kind := archiveKind(f.Name)
+		if kind == "" {
+			continue
+		}
+
+		err := listArchive(f.Path, kind, func(name string, size int64, crc uint32) {
+			if size == 0 || !filepath.IsLocal(filepath.FromSlash(name)) {
+				return
+			}
+			target := filepath.Join(filepath.Dir(f.Path), filepath.FromSlash(name))
+			if extractedSize, ok := existing[target]; ok && extractedSize == size {
+				return
+			}
+			if kind == "zip" {
+				key := zipKey{size, crc}
+				if seen[key] {
+					return
+				}
+				seen[key] = true
+			}
+			entries = append(entries, DiskFile{
+				Path:    target,
+				Name:    path.Base(name),
+				Size:    size,
+				Archive: f.Path,
+				Entry:   name,
+			})
+		})
+		if err != nil {
+			log.Printf("Skipping unreadable archive: %s (%v)", f.Path, err)
+		}
+	}
+
+	return entries, nil
+}
+
+// listArchive calls fn for every regular file in an archive
+func listArchive(archivePath string, kind string, fn func(name string, size int64, crc uint32)) error {
+	if kind == "zip" {
+		r, err := zip.OpenReader(archivePath)
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+		for _, f := range r.File {
+			if f.Mode().IsRegular() {
+				fn(f.Name, int64(f.UncompressedSize64), f.CRC32)
+			}
+		}
+		return nil
+	}
+
+	return walkTar(archivePath, kind, func(h *tar.Header, _ io.Reader) (bool, error) {
+		if h.Typeflag == tar.TypeReg {
+			fn(path.Clean(h.Name), h.Size, 0)
+		}
+		return false, nil
+	})
+}
+
+// walkTar calls fn for every header of a tar archive until fn returns true
+func walkTar(archivePath string, kind string, fn func(h *tar.Header, r io.Reader) (bool, error)) error {
+	file, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var reader io.Reader = file
+	if kind == "tar.gz" {
+		gz, err := gzip.NewReader(file)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		reader = gz
+	}
+
+	tr := tar.NewReader(reader)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if done, err := fn(h, tr); done || err != nil {
+			return err
+		}
+	}
+}
+
+// Extraction extracts a file from an archive to where the renames expect it
+type Extraction struct {
+	Archive string `json:"archive"`
+	Entry   string `json:"entry"`
+	Target  string `json:"target"`
+	Size    int64  `json:"size"`
+}
+
+// Extractions returns the extractions needed for the selected archive entries
+func Extractions(matches []Match) []Extraction {
+	extractions := []Extraction{}
+	for _, m := range matches {
+		if m.Selected != nil && m.Selected.IsArchiveEntry() {
+			extractions = append(extractions, Extraction{
+				Archive: m.Selected.Archive,
+				Entry:   m.Selected.Entry,
+				Target:  m.Selected.Path,
+				Size:    m.Selected.Size,
+			})
+		}
+	}
+	return extractions
+}
+
+// UnselectExtractions clears the selection of matches whose archive entry
+// could not be extracted and returns how many were cleared
+func UnselectExtractions(matches []Match, failed []Extraction) int {
+	targets := make(map[string]bool, len(failed))
+	for _, e := range failed {
+		targets[e.Target] = true
+	}
+	count := 0
+	for i := range matches {
+		if s := matches[i].Selected; s != nil && s.IsArchiveEntry() && targets[s.Path] {
+			matches[i].Selected = nil
+			matches[i].AutoMatched = false
+			count++
+		}
+	}
+	return count
+}
+
+// ExtractResult summarises a batch of extractions
+type ExtractResult struct {
+	ExtractedCount int          `json:"extractedCount"`
+	FailedCount    int          `json:"failedCount"`
+	Failed         []Extraction `json:"failed"`
+	Errors         []string     `json:"errors"`
+}
+
+// ExtractAll extracts every entry. A failed extraction is recorded and the
+// remaining ones are still extracted.
+func ExtractAll(ctx context.Context, extractions []Extraction) ExtractResult {
+	result := ExtractResult{Failed: []Extraction{}, Errors: []string{}}
+	for _, e := range extractions {
+		if err := ExtractEntry(ctx, e); err != nil {
+			result.FailedCount++
+			result.Failed = append(result.Failed, e)
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", e.Target, err))
+			continue
+		}
+		result.ExtractedCount++
+	}
+	return result
+}
+
+// ExtractEntry extracts a single archive entry to its target. Existing files
+// are never overwritten. The entry is written to a temporary file first, so
+// a failed or cancelled extraction leaves nothing behind.
+func ExtractEntry(ctx context.Context, e Extraction) error {
+	if !filepath.IsLocal(filepath.FromSlash(e.Entry)) {
+		return fmt.Errorf("unsafe entry name %q", e.Entry)
+	}
+	if _, err := os.Lstat(e.Target); err == nil {
+		return fmt.Errorf("target already exists")
+	}
+	if err := os.MkdirAll(filepath.Dir(e.Target), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(e.Target), "."+filepath.Base(e.Target)+".*.part")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := copyEntry(ctx, tmp, e)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if written != e.Size {
+		return fmt.Errorf("extracted %d bytes, expected %d", written, e.Size)
+	}
+	return os.Rename(tmp.Name(), e.Target)
+}
+
+// copyEntry writes the contents of an archive entry to w. Zip entries are
+// checked against their CRC while reading.
+func copyEntry(ctx context.Context, w io.Writer, e Extraction) (int64, error) {
+	kind := archiveKind(e.Archive)
+	switch kind {
+	case "zip":
+		r, err := zip.OpenReader(e.Archive)
+		if err != nil {
+			return 0, err
+		}
+		defer r.Close()
+		for _, f := range r.File {
+			if f.Name != e.Entry {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return 0, err
+			}
+			defer rc.Close()
+			return io.Copy(w, contextReader{ctx, rc})
+		}
+
+	case "tar", "tar.gz":
+		var written int64
+		found := false
+		err := walkTar(e.Archive, kind, func(h *tar.Header, r io.Reader) (bool, error) {
+			if h.Typeflag != tar.TypeReg || path.Clean(h.Name) != e.Entry {
+				return false, nil
+			}
+			found = true
+			var err error
+			written, err = io.Copy(w, contextReader{ctx, r})
+			return true, err
+		})
+		if found || err != nil {
+			return written, err
+		}
+
+	default:
+		return 0, fmt.Errorf("unsupported archive %s", e.Archive)
+	}
+
+	return 0, fmt.Errorf("%s not found in %s", e.Entry, e.Archive)
+}
+
+// contextReader stops a copy when its context is cancelled
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
@@ -0,0 +1,154 @@
+package backend
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeZip(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	w := zip.NewWriter(f)
+	for name, content := range entries {
+		entry, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		entry.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func writeTarGz(t *testing.T, path string, entries map[string]string) {
+	t.Helper()
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	w := tar.NewWriter(gz)
+	for name, content := range entries {
+		w.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(content)), Typeflag: tar.TypeReg})
+		w.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestScanArchives(t *testing.T) {
+	dir := t.TempDir()
+	writeZip(t, filepath.Join(dir, "pack.zip"), map[string]string{
+		"Show/E01.mkv":  "episode one",
+		"../escape.mkv": "outside",
+	})
+	writeTarGz(t, filepath.Join(dir, "more.tar.gz"), map[string]string{
+		"E02.mkv": "episode two!",
+	})
+
+	files, err := ScanDirectory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := ScanArchives(context.Background(), files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 archive entries, got %+v", entries)
+	}
+	byName := map[string]DiskFile{}
+	for _, e := range entries {
+		byName[e.Name] = e
+	}
+	e01 := byName["E01.mkv"]
+	if e01.Path != filepath.Join(dir, "Show", "E01.mkv") || e01.Size != 11 || e01.Entry != "Show/E01.mkv" || !e01.IsArchiveEntry() {
+		t.Errorf("Unexpected zip entry: %+v", e01)
+	}
+	if byName["E02.mkv"].Archive != filepath.Join(dir, "more.tar.gz") {
+		t.Errorf("Unexpected tar entry: %+v", byName["E02.mkv"])
+	}
+}
+
+func TestScanArchives_SkipsExtractedAndDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	writeZip(t, filepath.Join(dir, "a.zip"), map[string]string{"E01.mkv": "episode one", "E02.mkv": "episode two"})
+	writeZip(t, filepath.Join(dir, "b.zip"), map[string]string{"copy/E01.mkv": "episode one"})
+	if err := os.WriteFile(filepath.Join(dir, "E02.mkv"), []byte("episode two"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, _ := ScanDirectory(dir)
+	entries, err := ScanArchives(context.Background(), files)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// E02.mkv is already extracted; the copy in b.zip repeats a.zip's E01.mkv
+	if len(entries) != 1 || entries[0].Entry != "E01.mkv" {
+		t.Errorf("Expected only E01.mkv from a.zip, got %+v", entries)
+	}
+}
+
+func TestExtractEntry(t *testing.T) {
+	dir := t.TempDir()
+	writeZip(t, filepath.Join(dir, "pack.zip"), map[string]string{"Show/E01.mkv": "episode one"})
+	writeTarGz(t, filepath.Join(dir, "more.tgz"), map[string]string{"E02.mkv": "episode two!"})
+
+	files, _ := ScanDirectory(dir)
+	entries, _ := ScanArchives(context.Background(), files)
+	matches := make([]Match, len(entries))
+	for i := range entries {
+		matches[i] = Match{Selected: &entries[i]}
+	}
+
+	result := ExtractAll(context.Background(), Extractions(matches))
+	if result.ExtractedCount != 2 || result.FailedCount != 0 {
+		t.Fatalf("Expected 2 extractions, got %+v", result)
+	}
+	for path, expected := range map[string]string{
+		filepath.Join(dir, "Show", "E01.mkv"): "episode one",
+		filepath.Join(dir, "E02.mkv"):         "episode two!",
+	} {
+		if data, err := os.ReadFile(path); err != nil || string(data) != expected {
+			t.Errorf("Expected %s to contain %q, got %q (%v)", path, expected, data, err)
+		}
+	}
+
+	// Extracting again must not overwrite
+	again := ExtractAll(context.Background(), Extractions(matches))
+	if again.FailedCount != 2 {
+		t.Errorf("Expected existing targets to fail, got %+v", again)
+	}
+	if UnselectExtractions(matches, again.Failed) != 2 || matches[0].Selected != nil {
+		t.Errorf("Expected failed extractions to be unselected, got %+v", matches)
+	}
+}
+
+func TestExtractEntry_Unsafe(t *testing.T) {
+	dir := t.TempDir()
+	err := ExtractEntry(context.Background(), Extraction{
+		Archive: filepath.Join(dir, "pack.zip"),
+		Entry:   "../escape.mkv",
+		Target:  filepath.Join(dir, "escape.mkv"),
+	})
+	if err == nil {
+		t.Error("Expected error for an entry outside the archive folder")
+	}
+}
@@ -0,0 +1,89 @@
+package backend
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/autobrr/go-qbittorrent"
+)
+
+// newHTTPClient builds an HTTP client honouring the connection's TLS and
+// header settings, recording metrics for every request
+func newHTTPClient(config ConnectionConfig) (*http.Client, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+
+	if config.CACertFile != "" {
+		pem, err := os.ReadFile(config.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA bundle %s", config.CACertFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if config.ClientCertFile != "" {
+		// The key may be stored in the same PEM file as the certificate
+		keyFile := config.ClientKeyFile
+		if keyFile == "" {
+			keyFile = config.ClientCertFile
+		}
+		cert, err := tls.LoadX509KeyPair(config.ClientCertFile, keyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	var roundTripper http.RoundTripper = transport
+	if len(config.Headers) > 0 {
+		roundTripper = &headerTransport{base: transport, headers: config.Headers}
+	}
+
+	return &http.Client{
+		Transport: &metricsTransport{base: roundTripper},
+		Timeout:   qbittorrent.DefaultTimeout,
+	}, nil
+}
+
+// headerTransport adds extra headers (e.g. Cloudflare Access tokens) to every request
+type headerTransport struct {
+	base    http.RoundTripper
+	headers map[string]string
+}
+
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		if strings.EqualFold(name, "Host") {
+			req.Host = value
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// ParseHeader parses a "Name: value" header line
+func ParseHeader(line string) (string, string, error) {
+	name, value, ok := strings.Cut(line, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" {
+		return "", "", fmt.Errorf("invalid header %q, expected \"Name: value\"", line)
+	}
+	return name, strings.TrimSpace(value), nil
+}
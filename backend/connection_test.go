@@ -0,0 +1,97 @@
+package backend
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// newFakeQBittorrent starts a TLS server answering qBittorrent logins and
+// records the last login request
+func newFakeQBittorrent(t *testing.T) (*httptest.Server, *http.Request) {
+	t.Helper()
+	last := &http.Request{}
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v2/auth/login" {
+			*last = *r.Clone(r.Context())
+			http.SetCookie(w, &http.Cookie{Name: "SID", Value: "test"})
+			_, _ = w.Write([]byte("Ok."))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, last
+}
+
+func writeServerCA(t *testing.T, server *httptest.Server) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestQBitService_Connect_CustomCA(t *testing.T) {
+	server, last := newFakeQBittorrent(t)
+
+	service := &QBitService{}
+	err := service.Connect(ConnectionConfig{
+		URL:           server.URL,
+		Username:      "admin",
+		Password:      "secret",
+		CACertFile:    writeServerCA(t, server),
+		BasicUsername: "proxy",
+		BasicPassword: "proxypass",
+		Headers:       map[string]string{"CF-Access-Client-Id": "client-id"},
+	})
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	if !service.IsConnected() {
+		t.Error("Expected IsConnected to return true")
+	}
+
+	if got := last.Header.Get("CF-Access-Client-Id"); got != "client-id" {
+		t.Errorf("Expected custom header to be sent, got %q", got)
+	}
+	if user, pass, ok := last.BasicAuth(); !ok || user != "proxy" || pass != "proxypass" {
+		t.Errorf("Expected basic auth proxy/proxypass, got %q/%q", user, pass)
+	}
+}
+
+func TestQBitService_Connect_UntrustedCertificate(t *testing.T) {
+	server, _ := newFakeQBittorrent(t)
+
+	service := &QBitService{}
+	err := service.Connect(ConnectionConfig{URL: server.URL, Username: "admin", Password: "secret"})
+	if err == nil {
+		t.Fatal("Expected self-signed certificate to be rejected")
+	}
+	if service.IsConnected() {
+		t.Error("Expected IsConnected to return false after failed connect")
+	}
+
+	err = service.Connect(ConnectionConfig{URL: server.URL, Username: "admin", Password: "secret", InsecureSkipVerify: true})
+	if err != nil {
+		t.Errorf("Expected insecure connection to succeed, got: %v", err)
+	}
+}
+
+func TestParseHeader(t *testing.T) {
+	name, value, err := ParseHeader("CF-Access-Client-Secret:  abc:def ")
+	if err != nil || name != "CF-Access-Client-Secret" || value != "abc:def" {
+		t.Errorf("ParseHeader returned %q, %q, %v", name, value, err)
+	}
+
+	if _, _, err := ParseHeader("no-colon"); err == nil {
+		t.Error("Expected error for header without colon")
+	}
+}
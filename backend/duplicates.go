@@ -0,0 +1,316 @@
+package backend
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// DuplicateFile is a torrent file that other torrents share, e.g. when the
+// same release is cross-seeded from several trackers
+type DuplicateFile struct {
+	Hash        string  `json:"hash"`
+	TorrentName string  `json:"torrentName"`
+	SavePath    string  `json:"savePath"`
+	Index       int     `json:"index"`
+	Name        string  `json:"name"`
+	Size        int64   `json:"size"`
+	Progress    float64 `json:"progress"` // 1 once qBittorrent verified the file on disk
+}
+
+// Complete reports whether qBittorrent has the whole file verified on disk
+func (f DuplicateFile) Complete() bool {
+	return f.Progress >= 1
+}
+
+// DiskPath returns where qBittorrent keeps the file
+func (f DuplicateFile) DiskPath() string {
+	return filepath.Join(f.SavePath, filepath.FromSlash(f.Name))
+}
+
+// DuplicateGroup is a set of files of at least two torrents with the same
+// content
+type DuplicateGroup struct {
+	Size  int64           `json:"size"`
+	Files []DuplicateFile `json:"files"`
+	// Verified is set when every file is linked to another by identical
+	// piece hashes, not only by name and size
+	Verified bool `json:"verified"`
+}
+
+// DuplicateReport lists the files torrents share
+type DuplicateReport struct {
+	Torrents int              `json:"torrents"` // torrents scanned
+	Groups   []DuplicateGroup `json:"groups"`
+}
+
+// TorrentContents is a torrent with its file list and, to compare files by
+// pieces, its piece layout
+type TorrentContents struct {
+	Torrent TorrentInfo
+	Files   []TorrentFile
+	Layout  *PieceLayout
+}
+
+// FindDuplicates groups the files of different torrents that have the same
+// size and either the same file name or, for torrents with a piece layout,
+// the same hashes for the pieces that lie entirely within the file. Groups
+// are sorted by size, largest first.
+func FindDuplicates(torrents []TorrentContents) []DuplicateGroup {
+	type node struct {
+		torrent int
+		file    DuplicateFile
+		pieces  string // key of the pieces within the file, empty when unknown
+	}
+	var nodes []node
+	bySize := make(map[int64][]int)
+	for ti, t := range torrents {
+		var offsets map[int]int64
+		if t.Layout != nil {
+			offsets = fileOffsets(*t.Layout)
+		}
+		for _, f := range t.Files {
+			if !(TorrentFileInfo{Name: f.Name, Size: f.Size}).NeedsDiskFile() {
+				continue
+			}
+			n := node{torrent: ti, file: DuplicateFile{
+				Hash:        t.Torrent.Hash,
+				TorrentName: t.Torrent.Name,
+				SavePath:    t.Torrent.SavePath,
+				Index:       f.Index,
+				Name:        f.Name,
+				Size:        f.Size,
+				Progress:    f.Progress,
+			}}
+			if offset, ok := offsets[f.Index]; ok {
+				n.pieces = innerPiecesKey(*t.Layout, offset, f.Size)
+			}
+			bySize[f.Size] = append(bySize[f.Size], len(nodes))
+			nodes = append(nodes, n)
+		}
+	}
+
+	parent := make([]int, len(nodes))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) { parent[find(a)] = find(b) }
+
+	verified := make([]bool, len(nodes))
+	for _, ids := range bySize {
+		if len(ids) < 2 {
+			continue
+		}
+		byName := make(map[string]int)
+		byPieces := make(map[string]int)
+		for _, id := range ids {
+			name := strings.ToLower(path.Base(nodes[id].file.Name))
+			if first, ok := byName[name]; ok {
+				union(id, first)
+			} else {
+				byName[name] = id
+			}
+			if key := nodes[id].pieces; key != "" {
+				if first, ok := byPieces[key]; ok {
+					union(id, first)
+					verified[id], verified[first] = true, true
+				} else {
+					byPieces[key] = id
+				}
+			}
+		}
+	}
+
+	members := make(map[int][]int)
+	for id := range nodes {
+		root := find(id)
+		members[root] = append(members[root], id)
+	}
+	var groups []DuplicateGroup
+	for _, ids := range members {
+		torrentSet := make(map[int]bool)
+		for _, id := range ids {
+			torrentSet[nodes[id].torrent] = true
+		}
+		if len(torrentSet) < 2 {
+			continue
+		}
+		group := DuplicateGroup{Size: nodes[ids[0]].file.Size, Verified: true}
+		for _, id := range ids {
+			group.Files = append(group.Files, nodes[id].file)
+			group.Verified = group.Verified && verified[id]
+		}
+		slices.SortFunc(group.Files, func(a, b DuplicateFile) int {
+			return cmp.Or(cmp.Compare(a.TorrentName, b.TorrentName), cmp.Compare(a.Index, b.Index))
+		})
+		groups = append(groups, group)
+	}
+	slices.SortFunc(groups, func(a, b DuplicateGroup) int {
+		return cmp.Or(cmp.Compare(b.Size, a.Size), cmp.Compare(a.Files[0].Name, b.Files[0].Name))
+	})
+	return groups
+}
+
+// fileOffsets returns where each file starts in a torrent's data, by index
+func fileOffsets(layout PieceLayout) map[int]int64 {
+	offsets := make(map[int]int64, len(layout.Files))
+	var offset int64
+	for _, f := range layout.Files {
+		if f.Index >= 0 {
+			offsets[f.Index] = offset
+		}
+		offset += f.Size
+	}
+	return offsets
+}
+
+// innerPiecesKey identifies a file's content by the hashes of the pieces
+// that lie entirely within it. Files of two torrents only compare when the
+// pieces cover the same ranges of both, so the piece length and where the
+// file starts within a piece are part of the key. Files shorter than a piece
+// have no key.
+func innerPiecesKey(layout PieceLayout, offset int64, size int64) string {
+	if layout.PieceLength <= 0 {
+		return ""
+	}
+	first := (offset + layout.PieceLength - 1) / layout.PieceLength
+	end := (offset + size) / layout.PieceLength
+	if first >= end || end > int64(len(layout.PieceHashes)) {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d/%s", layout.PieceLength, offset%layout.PieceLength,
+		strings.Join(layout.PieceHashes[first:end], ""))
+}
+
+// FindDuplicateFiles reports the files torrents in qBittorrent share. With
+// verifyPieces, the piece hashes of torrents sharing a file size are fetched
+// so files are also grouped, and verified, by content.
+func (s *QBitService) FindDuplicateFiles(verifyPieces bool) (DuplicateReport, error) {
+	torrents, err := s.GetTorrents()
+	if err != nil {
+		return DuplicateReport{}, err
+	}
+
+	contents := make([]TorrentContents, len(torrents))
+	sizeTorrents := make(map[int64]map[int]bool)
+	for i, t := range torrents {
+		files, err := s.GetTorrentFiles(t.Hash)
+		if err != nil {
+			return DuplicateReport{}, fmt.Errorf("failed to get files of %s: %w", t.Name, err)
+		}
+		contents[i] = TorrentContents{Torrent: t, Files: files}
+		for _, f := range files {
+			if sizeTorrents[f.Size] == nil {
+				sizeTorrents[f.Size] = make(map[int]bool)
+			}
+			sizeTorrents[f.Size][i] = true
+		}
+	}
+
+	if verifyPieces {
+		// Only torrents sharing a file size with another torrent can match
+		shared := make(map[int]bool)
+		for size, set := range sizeTorrents {
+			if size > 0 && len(set) > 1 {
+				for i := range set {
+					shared[i] = true
+				}
+			}
+		}
+		for i := range shared {
+			layout, err := s.GetPieceLayout(torrents[i].Hash)
+			if err != nil {
+				return DuplicateReport{}, fmt.Errorf("failed to get pieces of %s: %w", torrents[i].Name, err)
+			}
+			contents[i].Layout = &layout
+		}
+	}
+
+	return DuplicateReport{Torrents: len(torrents), Groups: FindDuplicates(contents)}, nil
+}
+
+// ReuseDuplicates selects, for files of the torrent with the given hash
+// that have no selected disk file, the copy another torrent of the same
+// verified duplicate group has complete on disk. Groups only sharing a name
+// and size are skipped, as their copies may differ. The copy is added to the
+// candidates when the scan did not find it, and unmatched files become
+// matches. It returns how many files were selected this way.
+func ReuseDuplicates(result *MatchResult, hash string, groups []DuplicateGroup) int {
+	sources := make(map[int]DiskFile)
+	for _, g := range groups {
+		if !g.Verified {
+			continue
+		}
+		var mine []int
+		var source *DiskFile
+		for _, f := range g.Files {
+			if strings.EqualFold(f.Hash, hash) {
+				mine = append(mine, f.Index)
+				continue
+			}
+			if source != nil || !f.Complete() {
+				continue
+			}
+			diskPath := f.DiskPath()
+			if info, err := os.Stat(diskPath); err == nil && info.Mode().IsRegular() && info.Size() == f.Size {
+				source = &DiskFile{Path: diskPath, Name: filepath.Base(diskPath), Size: f.Size}
+			}
+		}
+		if source == nil {
+			continue
+		}
+		for _, index := range mine {
+			sources[index] = *source
+		}
+	}
+	if len(sources) == 0 {
+		return 0
+	}
+
+	reused := 0
+	for i := range result.Matches {
+		m := &result.Matches[i]
+		source, ok := sources[m.TorrentFile.Index]
+		if !ok || m.Selected != nil {
+			continue
+		}
+		j := slices.IndexFunc(m.DiskFiles, func(c DiskFile) bool { return samePath(c.Path, source.Path) })
+		if j < 0 {
+			m.DiskFiles = append([]DiskFile{source}, m.DiskFiles...)
+			j = 0
+		}
+		m.Selected = &m.DiskFiles[j]
+		m.AutoMatched, m.Exact = true, true
+		m.SizeMismatch, m.Partial = false, false
+		result.MatchedCount++
+		reused++
+	}
+
+	unmatched := []TorrentFileInfo{}
+	for _, tf := range result.Unmatched {
+		source, ok := sources[tf.Index]
+		if !ok {
+			unmatched = append(unmatched, tf)
+			continue
+		}
+		match := Match{TorrentFile: tf, DiskFiles: []DiskFile{source}, AutoMatched: true, Exact: true, Score: MatchScore(tf.Name, source.Path)}
+		match.Selected = &match.DiskFiles[0]
+		result.Matches = append(result.Matches, match)
+		result.MatchedCount++
+		reused++
+	}
+	result.Unmatched = unmatched
+	slices.SortFunc(result.Matches, func(a, b Match) int { return cmp.Compare(a.TorrentFile.Index, b.TorrentFile.Index) })
+	return reused
+}
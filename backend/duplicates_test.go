@@ -0,0 +1,114 @@
+package backend
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestFindDuplicates(t *testing.T) {
+	torrents := []TorrentContents{
+		{
+			Torrent: TorrentInfo{Hash: "a", Name: "Movie.2019.TrackerA"},
+			Files: []TorrentFile{
+				{Index: 0, Name: "Movie.2019/Movie.2019.mkv", Size: 100, Progress: 1},
+				{Index: 1, Name: "Movie.2019/Movie.2019.nfo", Size: 7, Progress: 1},
+			},
+		},
+		{
+			Torrent: TorrentInfo{Hash: "b", Name: "Movie.2019.TrackerB"},
+			Files: []TorrentFile{
+				{Index: 0, Name: "Movie 2019/movie.2019.MKV", Size: 100},
+				{Index: 1, Name: "Movie 2019/Movie.2019.nfo", Size: 9},
+			},
+		},
+		{
+			Torrent: TorrentInfo{Hash: "c", Name: "Other"},
+			Files:   []TorrentFile{{Index: 0, Name: "Other.mkv", Size: 100}},
+		},
+	}
+
+	groups := FindDuplicates(torrents)
+	if len(groups) != 1 {
+		t.Fatalf("Expected 1 group, got %+v", groups)
+	}
+	g := groups[0]
+	if g.Size != 100 || len(g.Files) != 2 || g.Verified {
+		t.Errorf("Expected the unverified mkv of torrents a and b, got %+v", g)
+	}
+}
+
+func TestFindDuplicates_ByPieces(t *testing.T) {
+	hashes := []string{"h0", "h1", "h2"}
+	torrents := []TorrentContents{
+		{
+			Torrent: TorrentInfo{Hash: "a", Name: "A"},
+			Files:   []TorrentFile{{Index: 0, Name: "A/release.mkv", Size: 12}},
+			Layout: &PieceLayout{PieceLength: 4, PieceHashes: hashes, Files: []TorrentFileInfo{
+				{Index: 0, Name: "A/release.mkv", Size: 12},
+			}},
+		},
+		{
+			// Renamed, after a piece-aligned sample, so the same pieces
+			Torrent: TorrentInfo{Hash: "b", Name: "B"},
+			Files: []TorrentFile{
+				{Index: 0, Name: "B/sample.mkv", Size: 4},
+				{Index: 1, Name: "B/Renamed.mkv", Size: 12},
+			},
+			Layout: &PieceLayout{PieceLength: 4, PieceHashes: append([]string{"s0"}, hashes...), Files: []TorrentFileInfo{
+				{Index: 0, Name: "B/sample.mkv", Size: 4},
+				{Index: 1, Name: "B/Renamed.mkv", Size: 12},
+			}},
+		},
+	}
+
+	groups := FindDuplicates(torrents)
+	if len(groups) != 1 || !groups[0].Verified || len(groups[0].Files) != 2 {
+		t.Fatalf("Expected one verified group, got %+v", groups)
+	}
+
+	// A file starting elsewhere within a piece cannot be compared
+	torrents[1].Files[0].Size = 3
+	torrents[1].Layout.Files[0].Size = 3
+	if groups := FindDuplicates(torrents); len(groups) != 0 {
+		t.Errorf("Expected no group for unaligned pieces, got %+v", groups)
+	}
+}
+
+func TestReuseDuplicates(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"seeding/A/Movie.mkv": "0123456789"})
+	groups := []DuplicateGroup{{Size: 10, Verified: true, Files: []DuplicateFile{
+		{Hash: "a", SavePath: filepath.Join(dir, "seeding"), Index: 0, Name: "A/Movie.mkv", Size: 10, Progress: 1},
+		{Hash: "b", SavePath: filepath.Join(dir, "missing"), Index: 1, Name: "B/Movie.mkv", Size: 10},
+	}}}
+	result := MatchResult{
+		Matches:   []Match{{TorrentFile: TorrentFileInfo{Index: 0, Name: "B/info.nfo", Size: 3}}},
+		Unmatched: []TorrentFileInfo{{Index: 1, Name: "B/Movie.mkv", Size: 10}},
+	}
+
+	if reused := ReuseDuplicates(&result, "B", groups); reused != 1 {
+		t.Fatalf("Expected 1 reused file, got %d", reused)
+	}
+	if len(result.Unmatched) != 0 || len(result.Matches) != 2 || result.MatchedCount != 1 {
+		t.Fatalf("Expected the unmatched file to become a match, got %+v", result)
+	}
+	selected := result.Matches[1].Selected
+	if selected == nil || selected.Path != filepath.Join(dir, "seeding", "A", "Movie.mkv") || !result.Matches[1].AutoMatched || !result.Matches[1].Exact {
+		t.Errorf("Expected the complete copy to be selected, got %+v", result.Matches[1])
+	}
+
+	// Copies only sharing a name and size are not reused
+	groups[0].Verified = false
+	result = MatchResult{Unmatched: []TorrentFileInfo{{Index: 1, Name: "B/Movie.mkv", Size: 10}}}
+	if reused := ReuseDuplicates(&result, "b", groups); reused != 0 {
+		t.Errorf("Expected an unverified copy not to be reused, got %+v", result)
+	}
+
+	// Incomplete copies are not reused
+	groups[0].Verified = true
+	groups[0].Files[0].Progress = 0.5
+	result = MatchResult{Unmatched: []TorrentFileInfo{{Index: 1, Name: "B/Movie.mkv", Size: 10}}}
+	if reused := ReuseDuplicates(&result, "b", groups); reused != 0 {
+		t.Errorf("Expected an incomplete copy not to be reused, got %+v", result)
+	}
+}
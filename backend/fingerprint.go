@@ -0,0 +1,238 @@
+package backend
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// fingerprintSampleSize is how much of the start, middle and end of a file a
+// fingerprint hashes. Smaller files are hashed whole.
+const fingerprintSampleSize = 64 << 10
+
+// Fingerprint returns a quick content fingerprint of a file: the SHA-1 of
+// its size and of samples from its start, middle and end. Files with the
+// same fingerprint are almost certainly identical copies.
+func Fingerprint(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	size := info.Size()
+
+	h := sha1.New()
+	binary.Write(h, binary.LittleEndian, size)
+	if size <= 3*fingerprintSampleSize {
+		if _, err := io.Copy(h, f); err != nil {
+			return "", err
+		}
+	} else {
+		buf := make([]byte, fingerprintSampleSize)
+		for _, offset := range []int64{0, size/2 - fingerprintSampleSize/2, size - fingerprintSampleSize} {
+			if _, err := f.ReadAt(buf, offset); err != nil {
+				return "", err
+			}
+			h.Write(buf)
+		}
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// fingerprintEntry is a cached fingerprint, valid while the file keeps its
+// size and modification time
+type fingerprintEntry struct {
+	Size        int64  `json:"size"`
+	ModTime     int64  `json:"modTime"` // Unix nanoseconds
+	Fingerprint string `json:"fingerprint"`
+}
+
+// FingerprintCache keeps fingerprints between runs, so files are only
+// sampled again after they change
+type FingerprintCache struct {
+	mu      sync.Mutex
+	path    string
+	entries map[string]fingerprintEntry
+	dirty   bool
+}
+
+// DefaultFingerprintCachePath returns where the fingerprint cache is kept
+func DefaultFingerprintCachePath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "qbt-file-matcher", "fingerprints.json"), nil
+}
+
+// DefaultFingerprintCache loads the cache from its default path, or keeps
+// it in memory when there is no config directory
+func DefaultFingerprintCache() *FingerprintCache {
+	path, err := DefaultFingerprintCachePath()
+	if err != nil {
+		path = ""
+	}
+	return LoadFingerprintCache(path)
+}
+
+// LoadFingerprintCache reads a fingerprint cache. A missing or unreadable
+// cache starts empty. An empty path keeps the cache in memory only.
+func LoadFingerprintCache(path string) *FingerprintCache {
+	c := &FingerprintCache{path: path, entries: make(map[string]fingerprintEntry)}
+	if path == "" {
+		return c
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		json.Unmarshal(data, &c.entries)
+	}
+	return c
+}
+
+// Fingerprint returns the fingerprint of a file from the cache, computing
+// it when the file is new or has changed
+func (c *FingerprintCache) Fingerprint(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	c.mu.Lock()
+	entry, ok := c.entries[path]
+	c.mu.Unlock()
+	if ok && entry.Size == info.Size() && entry.ModTime == info.ModTime().UnixNano() {
+		return entry.Fingerprint, nil
+	}
+
+	fingerprint, err := Fingerprint(path)
+	if err != nil {
+		return "", err
+	}
+	c.mu.Lock()
+	c.entries[path] = fingerprintEntry{Size: info.Size(), ModTime: info.ModTime().UnixNano(), Fingerprint: fingerprint}
+	c.dirty = true
+	c.mu.Unlock()
+	return fingerprint, nil
+}
+
+// Save writes the cache if it changed, through a temporary file so it is
+// never partial
+func (c *FingerprintCache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.path == "" || !c.dirty {
+		return nil
+	}
+	data, err := json.Marshal(c.entries)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		return err
+	}
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		return err
+	}
+	c.dirty = false
+	return nil
+}
+
+// collapseCopies drops candidates that are identical copies of a candidate
+// before them, by fingerprint, recording their paths in that candidate's
+// Copies. scores are kept in line with the candidates.
+func collapseCopies(candidates []DiskFile, scores []float64) ([]DiskFile, []float64) {
+	kept := make(map[string]int)
+	var collapsed []DiskFile
+	var keptScores []float64
+	for i, c := range candidates {
+		if c.Fingerprint != "" {
+			if j, ok := kept[c.Fingerprint]; ok {
+				collapsed[j].Copies = append(collapsed[j].Copies, c.Path)
+				continue
+			}
+			kept[c.Fingerprint] = len(collapsed)
+		}
+		collapsed = append(collapsed, c)
+		keptScores = append(keptScores, scores[i])
+	}
+	return collapsed, keptScores
+}
+
+// withFingerprint drops the candidates whose fingerprint differs from the
+// torrent file's reference fingerprint. Candidates without one are kept.
+func withFingerprint(candidates []DiskFile, fingerprint string) []DiskFile {
+	if fingerprint == "" {
+		return candidates
+	}
+	kept := []DiskFile{}
+	for _, c := range candidates {
+		if c.Fingerprint == "" || c.Fingerprint == fingerprint {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// ReferenceFingerprints sets the fingerprint of every torrent file that has
+// a copy of the same size at its torrent path under dir, e.g. another
+// library or a mirror of the torrent, so candidates can be compared to it
+func ReferenceFingerprints(ctx context.Context, torrentFiles []TorrentFileInfo, dir string, cache *FingerprintCache) (int, error) {
+	found := 0
+	for i := range torrentFiles {
+		if err := ctx.Err(); err != nil {
+			return found, err
+		}
+		tf := &torrentFiles[i]
+		if !tf.NeedsDiskFile() {
+			continue
+		}
+		if !filepath.IsLocal(filepath.FromSlash(tf.Name)) {
+			continue
+		}
+		reference := filepath.Join(dir, filepath.FromSlash(tf.Name))
+		info, err := os.Stat(reference)
+		if err != nil || info.Size() != tf.Size {
+			continue
+		}
+		fingerprint, err := cache.Fingerprint(reference)
+		if err != nil {
+			return found, err
+		}
+		tf.Fingerprint = fingerprint
+		found++
+	}
+	return found, cache.Save()
+}
+
+// FingerprintCandidates fingerprints the disk files that share a size and,
+// with a reference directory, the torrent files' reference copies and the
+// disk files of their sizes
+func FingerprintCandidates(ctx context.Context, torrentFiles []TorrentFileInfo, diskFiles []DiskFile, reference string, cache *FingerprintCache) error {
+	sizes := DuplicateSizes(diskFiles)
+	if reference != "" {
+		if _, err := ReferenceFingerprints(ctx, torrentFiles, reference, cache); err != nil {
+			return err
+		}
+		for _, tf := range torrentFiles {
+			if tf.Fingerprint != "" {
+				sizes[tf.Size] = true
+			}
+		}
+	}
+	_, err := FingerprintFiles(ctx, diskFiles, sizes, cache)
+	return err
+}
@@ -0,0 +1,128 @@
+package backend
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	large := bytes.Repeat([]byte("0123456789abcdef"), fingerprintSampleSize)
+	changedMiddle := bytes.Clone(large)
+	changedMiddle[len(large)/2] = 'X'
+	changedUnsampled := bytes.Clone(large)
+	changedUnsampled[len(large)/4] = 'X'
+	for name, data := range map[string][]byte{"a": large, "b": large, "middle": changedMiddle, "unsampled": changedUnsampled} {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fingerprint := func(name string) string {
+		fp, err := Fingerprint(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return fp
+	}
+
+	if fingerprint("a") != fingerprint("b") {
+		t.Error("Expected identical files to share a fingerprint")
+	}
+	if fingerprint("a") == fingerprint("middle") {
+		t.Error("Expected a change in the middle sample to change the fingerprint")
+	}
+	// Only the samples are hashed
+	if fingerprint("a") != fingerprint("unsampled") {
+		t.Error("Expected a change outside the samples to keep the fingerprint")
+	}
+}
+
+func TestFingerprintCache(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a.mkv")
+	cachePath := filepath.Join(dir, "cache", "fingerprints.json")
+	os.WriteFile(file, []byte("original"), 0644)
+
+	cache := LoadFingerprintCache(cachePath)
+	first, err := cache.Fingerprint(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := LoadFingerprintCache(cachePath)
+	if cached, _ := reloaded.Fingerprint(file); cached != first {
+		t.Errorf("Expected the cached fingerprint %s, got %s", first, cached)
+	}
+
+	// A changed file is sampled again
+	os.WriteFile(file, []byte("modified"), 0644)
+	os.Chtimes(file, time.Now().Add(time.Hour), time.Now().Add(time.Hour))
+	if changed, _ := reloaded.Fingerprint(file); changed == first {
+		t.Error("Expected a new fingerprint after the file changed")
+	}
+}
+
+func TestFindMatches_CollapsesIdenticalCopies(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"library/Movie.mkv": "same content",
+		"backup/Movie.mkv":  "same content",
+		"other/Clip.mkv":    "other stuff!",
+	})
+	diskFiles, err := ScanDirectory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	torrentFiles := []TorrentFileInfo{{Index: 0, Name: "Film/Film.mkv", Size: 12}}
+
+	result := FindMatches(torrentFiles, diskFiles, true)
+	if len(result.Matches[0].DiskFiles) != 3 || result.Matches[0].Selected != nil {
+		t.Fatalf("Expected 3 ambiguous candidates without fingerprints, got %+v", result.Matches[0])
+	}
+
+	sizes := DuplicateSizes(diskFiles)
+	if count, err := FingerprintFiles(context.Background(), diskFiles, sizes, LoadFingerprintCache("")); err != nil || count != 3 {
+		t.Fatalf("Expected 3 fingerprinted files, got %d (%v)", count, err)
+	}
+	result = FindMatches(torrentFiles, diskFiles, true)
+	match := result.Matches[0]
+	if len(match.DiskFiles) != 2 {
+		t.Fatalf("Expected the copies to collapse into 2 candidates, got %+v", match.DiskFiles)
+	}
+	for _, c := range match.DiskFiles {
+		if c.Name == "Movie.mkv" && len(c.Copies) != 1 {
+			t.Errorf("Expected Movie.mkv to list its copy, got %+v", c)
+		}
+	}
+}
+
+func TestReferenceFingerprints(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"reference/Film/Film.mkv": "right content",
+		"disk/a.mkv":              "right content",
+		"disk/b.mkv":              "wrong content",
+	})
+	torrentFiles := []TorrentFileInfo{{Index: 0, Name: "Film/Film.mkv", Size: 13}}
+	diskFiles, _ := ScanDirectory(filepath.Join(dir, "disk"))
+
+	if err := FingerprintCandidates(context.Background(), torrentFiles, diskFiles, filepath.Join(dir, "reference"), LoadFingerprintCache("")); err != nil {
+		t.Fatal(err)
+	}
+	if torrentFiles[0].Fingerprint == "" {
+		t.Fatal("Expected the reference copy to be fingerprinted")
+	}
+
+	result := FindMatches(torrentFiles, diskFiles, true)
+	selected := result.Matches[0].Selected
+	if len(result.Matches[0].DiskFiles) != 1 || selected == nil || selected.Name != "a.mkv" {
+		t.Errorf("Expected only the file matching the reference, got %+v", result.Matches[0])
+	}
+}
@@ -0,0 +1,363 @@
+package backend
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// JobService runs scans, matches and applies as background jobs
+type JobService struct {
+	Jobs *JobManager
+	QBit *QBitService
+
+	// Notifier, when set, is told about completed applies and failed rechecks
+	Notifier Notifier
+}
+
+// ApplyJobRequest describes renames to apply to a torrent as a job.
+// Extractions and rebuilds are done before any rename; if one fails nothing
+// is renamed. Links put disk files into the torrent's layout instead of renaming.
+type ApplyJobRequest struct {
+	Hash        string          `json:"hash"`
+	Extractions []Extraction    `json:"extractions"`
+	Rebuilds    []Rebuild       `json:"rebuilds"`
+	Renames     []RenameOp      `json:"renames"`
+	Links       []LinkOperation `json:"links"`
+	LinkMethods []string        `json:"linkMethods"` // DefaultLinkMethods when empty
+	Recheck     bool            `json:"recheck"`
+	Wait        bool            `json:"wait"` // wait for the recheck and include its report
+}
+
+// ApplyJobResult is the result of an apply or undo job
+type ApplyJobResult struct {
+	ApplyResult
+	Links  *LinkResult    `json:"links,omitempty"`
+	Report *RecheckReport `json:"report"`
+}
+
+// StartScan scans a directory as a job; the result is []DiskFileInfo
+func (s *JobService) StartScan(path string) (Job, error) {
+	// Expand ~ to home directory, as ScanDir does
+	if len(path) > 0 && path[0] == '~' {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return Job{}, err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	if info, err := os.Stat(path); err != nil || !info.IsDir() {
+		return Job{}, fmt.Errorf("directory not found: %s", path)
+	}
+
+	return s.Jobs.Start("scan", path, "Scan "+path, func(job *JobContext) (any, error) {
+		job.Logf("Scanning %s", path)
+		files, err := ScanDirectoryContext(job, path, func(found int) {
+			job.Progress(0, 0, fmt.Sprintf("Found %d files", found))
+		})
+		if err != nil {
+			return nil, err
+		}
+		job.Logf("Found %d files", len(files))
+
+		result := make([]DiskFileInfo, len(files))
+		for i, f := range files {
+			result[i] = DiskFileInfo(f)
+		}
+		return result, nil
+	}), nil
+}
+
+// OrphansRequest describes roots to search for files no torrent points at
+type OrphansRequest struct {
+	Roots []string `json:"roots"`
+	Trash string   `json:"trash"` // left out of the report
+}
+
+// StartOrphans reports the files under roots that no torrent points at as
+// a job; the result is an OrphanReport
+func (s *JobService) StartOrphans(req OrphansRequest) (Job, error) {
+	if !s.QBit.IsConnected() {
+		return Job{}, ErrNotConnected
+	}
+	if len(req.Roots) == 0 {
+		return Job{}, fmt.Errorf("no roots to search")
+	}
+	for _, root := range req.Roots {
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			return Job{}, fmt.Errorf("directory not found: %s", root)
+		}
+	}
+
+	description := "Find orphans in " + strings.Join(req.Roots, ", ")
+	return s.Jobs.Start("orphans", "", description, func(job *JobContext) (any, error) {
+		job.Logf("Comparing %d roots with every torrent's files", len(req.Roots))
+		report, err := s.QBit.FindOrphanFiles(job, req.Roots, req.Trash)
+		if err != nil {
+			return nil, err
+		}
+		job.Logf("Found %d orphans in %d folders, %d files referenced", report.FileCount, len(report.Dirs), report.Referenced)
+		return report, nil
+	}), nil
+}
+
+// TrashOrphansRequest describes orphans to move to a trash directory
+type TrashOrphansRequest struct {
+	Files []DiskFile `json:"files"`
+	Roots []string   `json:"roots"`
+	Trash string     `json:"trash"`
+}
+
+// StartTrashOrphans moves orphans to the trash as a job, journaled so the
+// CLI's "moves undo" can put them back; the result is a MoveResult
+func (s *JobService) StartTrashOrphans(req TrashOrphansRequest) (Job, error) {
+	journalDir, err := DefaultJournalDir()
+	if err != nil {
+		return Job{}, err
+	}
+
+	description := fmt.Sprintf("Move %d orphans to %s", len(req.Files), req.Trash)
+	return s.Jobs.Start("trash", "", description, func(job *JobContext) (any, error) {
+		result, err := TrashOrphans(job, req.Files, req.Roots, req.Trash, journalDir)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range result.Errors {
+			job.Logf("%s", e)
+		}
+		job.Logf("Moved %d files, %d skipped, %d failed (journal %s)", result.MovedCount, result.SkippedCount, result.FailedCount, result.JournalID)
+		return result, nil
+	}), nil
+}
+
+// StartMatch matches torrent files against disk files as a job; the result
+// is a MatchResponse
+func (s *JobService) StartMatch(req MatchRequest) Job {
+	description := fmt.Sprintf("Match %d torrent files against %d disk files", len(req.TorrentFiles), len(req.DiskFiles))
+	return s.Jobs.Start("match", "", description, func(job *JobContext) (any, error) {
+		response, err := (&MatcherService{}).FindMatches(req)
+		if err != nil {
+			return nil, err
+		}
+		job.Logf("Matched %d of %d files", response.MatchedCount, response.TotalFiles)
+		return response, nil
+	})
+}
+
+// StartApply applies renames to a torrent as a job, optionally rechecking
+// and waiting for the result; the result is an ApplyJobResult
+func (s *JobService) StartApply(req ApplyJobRequest) (Job, error) {
+	if !s.QBit.IsConnected() {
+		return Job{}, ErrNotConnected
+	}
+
+	renames := make([]RenameOperation, len(req.Renames))
+	for i, r := range req.Renames {
+		renames[i] = RenameOperation(r)
+	}
+
+	description := fmt.Sprintf("Apply %d renames to %s", len(renames), req.Hash)
+	if len(req.Links) > 0 {
+		description = fmt.Sprintf("Link %d files for %s", len(req.Links), req.Hash)
+	}
+	return s.Jobs.Start("apply", req.Hash, description, func(job *JobContext) (any, error) {
+		if len(req.Extractions) > 0 {
+			if err := runExtractions(job, req.Extractions); err != nil {
+				return ApplyJobResult{}, err
+			}
+		}
+		if len(req.Rebuilds) > 0 {
+			if err := runRebuilds(job, req.Rebuilds); err != nil {
+				return ApplyJobResult{}, err
+			}
+		}
+		result, err := s.runApply(job, req.Hash, renames, req.Links, req.LinkMethods, req.Recheck, req.Wait)
+		if err == nil {
+			s.notifyApply(job, req.Hash, result)
+		}
+		return result, err
+	}), nil
+}
+
+// ErrNoAppliedRenames is returned by StartUndo when a torrent has no apply
+// left to undo
+var ErrNoAppliedRenames = errors.New("no applied renames to undo")
+
+// StartUndo reverses the renames of the latest apply job for a torrent, if
+// it has not been undone already. Renames applied before a failed or
+// cancelled apply stopped are undone too. The result is an ApplyJobResult.
+func (s *JobService) StartUndo(hash string) (Job, error) {
+	if !s.QBit.IsConnected() {
+		return Job{}, ErrNotConnected
+	}
+
+	var last *Job
+	for _, job := range s.Jobs.List() {
+		if job.Target == hash && (job.Kind == "apply" || job.Kind == "undo") && job.Done() {
+			last = &job
+			break
+		}
+	}
+	if last == nil || last.Kind == "undo" {
+		return Job{}, fmt.Errorf("%w for %s", ErrNoAppliedRenames, hash)
+	}
+
+	var applied ApplyJobResult
+	if err := s.Jobs.Result(last.ID, &applied); err != nil {
+		return Job{}, err
+	}
+	undo := UndoRenames(applied.Applied)
+
+	description := fmt.Sprintf("Undo %d renames of job %s", len(undo), last.ID)
+	return s.Jobs.Start("undo", hash, description, func(job *JobContext) (any, error) {
+		return s.runApply(job, hash, undo, nil, nil, false, false)
+	}), nil
+}
+
+func (s *JobService) runApply(job *JobContext, hash string, renames []RenameOperation, links []LinkOperation, linkMethods []string, recheck, wait bool) (ApplyJobResult, error) {
+	var result ApplyJobResult
+	var err error
+
+	linked := 0
+	if len(links) > 0 {
+		job.Progress(0, len(links), "Linking files")
+		linkResult := LinkFiles(job, links, linkMethods)
+		for _, e := range linkResult.Errors {
+			job.Logf("%s", e)
+		}
+		job.Logf("Linked %d files, %d failed", linkResult.LinkedCount, linkResult.FailedCount)
+		result.Links = &linkResult
+		linked = linkResult.LinkedCount
+	}
+
+	result.ApplyResult, err = s.QBit.applyRenames(job, hash, renames, true, func(done, total int) {
+		job.Progress(done, total, fmt.Sprintf("Renamed %d of %d", done, total))
+	})
+	for _, e := range result.Errors {
+		job.Logf("%s", e)
+	}
+	job.Logf("Renamed %d files, %d failed", result.RenamedCount, result.FailedCount)
+	if err != nil {
+		// Keep what was applied so it can still be undone
+		return result, err
+	}
+
+	if recheck && (len(result.Applied) > 0 || linked > 0) {
+		if err := s.QBit.RecheckTorrent(hash); err != nil {
+			return result, fmt.Errorf("failed to trigger recheck: %w", err)
+		}
+		job.Logf("Recheck started")
+
+		if wait {
+			job.Progress(0, 0, "Waiting for recheck")
+			report, err := s.QBit.waitForRecheck(job, hash, 0)
+			if err != nil {
+				return result, err
+			}
+			result.Report = &report
+			job.Logf("Verified: %d complete, %d incomplete", report.CompleteCount, report.IncompleteCount)
+		}
+	}
+
+	return result, nil
+}
+
+func runExtractions(job *JobContext, extractions []Extraction) error {
+	job.Progress(0, len(extractions), "Extracting from archives")
+	result := ExtractAll(job, extractions)
+	for _, e := range result.Errors {
+		job.Logf("%s", e)
+	}
+	job.Logf("Extracted %d files, %d failed", result.ExtractedCount, result.FailedCount)
+	if result.FailedCount > 0 {
+		return fmt.Errorf("%d extractions failed, no renames applied", result.FailedCount)
+	}
+	return nil
+}
+
+func runRebuilds(job *JobContext, rebuilds []Rebuild) error {
+	job.Progress(0, len(rebuilds), "Joining and splitting files")
+	result := RebuildAll(job, rebuilds)
+	for _, e := range result.Errors {
+		job.Logf("%s", e)
+	}
+	job.Logf("Rebuilt %d files, %d failed", result.RebuiltCount, result.FailedCount)
+	if result.FailedCount > 0 {
+		return fmt.Errorf("%d rebuilds failed, no renames applied", result.FailedCount)
+	}
+	return nil
+}
+
+func (s *JobService) notifyApply(job *JobContext, hash string, result ApplyJobResult) {
+	n := Notification{
+		Kind:         NotifyApplied,
+		Hash:         hash,
+		RenamedCount: result.RenamedCount,
+		FailedCount:  result.FailedCount,
+		Message:      strings.Join(result.Errors, "\n"),
+	}
+	if result.Report != nil && (result.Report.TimedOut || result.Report.IncompleteCount > 0) {
+		n.Kind = NotifyRecheckFailed
+		n.Message = recheckSummary(*result.Report)
+	}
+	if err := SendNotification(s.Notifier, n); err != nil {
+		job.Logf("Notification failed: %v", err)
+	}
+}
+
+// ListJobs returns all jobs without their results, newest first
+func (s *JobService) ListJobs() []Job {
+	return s.Jobs.List()
+}
+
+// GetJob returns a job including its result
+func (s *JobService) GetJob(id string) (Job, error) {
+	return s.Jobs.Get(id)
+}
+
+// CancelJob stops a running job
+func (s *JobService) CancelJob(id string) error {
+	return s.Jobs.Cancel(id)
+}
+
+// RemoveJob deletes a finished job
+func (s *JobService) RemoveJob(id string) error {
+	return s.Jobs.Remove(id)
+}
+
+// GetScanResult returns the files found by a completed scan job
+func (s *JobService) GetScanResult(id string) ([]DiskFileInfo, error) {
+	var files []DiskFileInfo
+	err := s.Jobs.Result(id, &files)
+	return files, err
+}
+
+// GetMatchResult returns the matches of a completed match job
+func (s *JobService) GetMatchResult(id string) (MatchResponse, error) {
+	var response MatchResponse
+	err := s.Jobs.Result(id, &response)
+	return response, err
+}
+
+// GetApplyResult returns the outcome of a completed apply or undo job
+func (s *JobService) GetApplyResult(id string) (ApplyJobResult, error) {
+	var result ApplyJobResult
+	err := s.Jobs.Result(id, &result)
+	return result, err
+}
+
+// GetOrphanResult returns the report of a completed orphans job
+func (s *JobService) GetOrphanResult(id string) (OrphanReport, error) {
+	var report OrphanReport
+	err := s.Jobs.Result(id, &report)
+	return report, err
+}
+
+// GetMoveResult returns the outcome of a completed trash job
+func (s *JobService) GetMoveResult(id string) (MoveResult, error) {
+	var result MoveResult
+	err := s.Jobs.Result(id, &result)
+	return result, err
+}
@@ -0,0 +1,458 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Job statuses
+const (
+	JobRunning     = "running"
+	JobCompleted   = "completed"
+	JobFailed      = "failed"
+	JobCancelled   = "cancelled"
+	JobInterrupted = "interrupted" // the process running it exited before it finished
+)
+
+// How often running jobs are persisted and checked for cancel requests from
+// other processes, how long a running job may go without a heartbeat before
+// it is considered interrupted, and how many log lines a job keeps
+var (
+	jobHeartbeatInterval = time.Second
+	jobStaleAfter        = 30 * time.Second
+)
+
+const maxJobLogLines = 500
+
+// Job represents a long-running scan, match or apply and its persisted result
+type Job struct {
+	ID          string          `json:"id"`
+	Kind        string          `json:"kind"`   // scan, match, apply or undo
+	Target      string          `json:"target"` // torrent hash or scanned path
+	Description string          `json:"description"`
+	Status      string          `json:"status"`
+	Progress    float64         `json:"progress"` // 0 to 1, or -1 when the total is unknown
+	Message     string          `json:"message"`
+	Logs        []string        `json:"logs"`
+	Error       string          `json:"error,omitempty"`
+	Created     time.Time       `json:"created"`
+	Updated     time.Time       `json:"updated"`
+	Finished    *time.Time      `json:"finished,omitempty"`
+	Result      json.RawMessage `json:"result,omitempty"`
+	PID         int             `json:"pid"`
+}
+
+// Done reports whether the job is no longer running
+func (j Job) Done() bool {
+	return j.Status != JobRunning
+}
+
+// JobFunc runs a job. The returned result is stored as JSON.
+type JobFunc func(job *JobContext) (any, error)
+
+// JobContext is passed to a running job for cancellation and reporting
+type JobContext struct {
+	context.Context
+	manager *JobManager
+	id      string
+}
+
+// Progress sets the job's progress; a total of 0 means unknown
+func (c *JobContext) Progress(done, total int, message string) {
+	c.manager.update(c.id, func(j *Job) {
+		j.Progress = -1
+		if total > 0 {
+			j.Progress = float64(done) / float64(total)
+		}
+		j.Message = message
+	})
+}
+
+// Logf appends a line to the job's log
+func (c *JobContext) Logf(format string, args ...any) {
+	line := time.Now().Format("15:04:05") + " " + fmt.Sprintf(format, args...)
+	c.manager.update(c.id, func(j *Job) {
+		j.Logs = append(j.Logs, line)
+		if len(j.Logs) > maxJobLogLines {
+			j.Logs = j.Logs[len(j.Logs)-maxJobLogLines:]
+		}
+	})
+}
+
+// JobManager runs jobs in the background and persists them, one JSON file
+// per job, so they can be listed and cancelled after a GUI reload or from
+// another process
+type JobManager struct {
+	dir       string // empty keeps jobs in memory only
+	heartbeat time.Duration
+
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	cancels map[string]context.CancelFunc
+
+	// OnChange, when set, is called with a copy of a job after every change
+	OnChange func(Job)
+}
+
+// DefaultJobDir returns the directory jobs are persisted in
+func DefaultJobDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "qbt-file-matcher", "jobs"), nil
+}
+
+// NewJobManager creates a job manager persisting to dir, loading jobs
+// already there. An empty dir keeps jobs in memory only.
+func NewJobManager(dir string) (*JobManager, error) {
+	m := &JobManager{
+		dir:       dir,
+		heartbeat: jobHeartbeatInterval,
+		jobs:      make(map[string]*Job),
+		cancels:   make(map[string]context.CancelFunc),
+	}
+	if dir == "" {
+		return m, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	m.reload()
+	return m, nil
+}
+
+// Start runs fn in the background as a new job and returns it
+func (m *JobManager) Start(kind, target, description string, fn JobFunc) Job {
+	ctx, cancel := context.WithCancel(context.Background())
+	now := time.Now()
+	job := &Job{
+		ID:          strconv.FormatInt(now.UnixNano(), 36),
+		Kind:        kind,
+		Target:      target,
+		Description: description,
+		Status:      JobRunning,
+		Progress:    -1,
+		Logs:        []string{},
+		Created:     now,
+		Updated:     now,
+		PID:         os.Getpid(),
+	}
+
+	m.mu.Lock()
+	m.jobs[job.ID] = job
+	m.cancels[job.ID] = cancel
+	snapshot := m.snapshot(job)
+	m.mu.Unlock()
+	m.persist(job.ID)
+	m.notify(snapshot)
+
+	jc := &JobContext{Context: ctx, manager: m, id: job.ID}
+	stop, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		m.beat(job.ID, cancel, stop)
+	}()
+	go func() {
+		result, err := fn(jc)
+		// No heartbeat may persist the job once it is finished
+		close(stop)
+		<-stopped
+		m.finish(ctx, job.ID, result, err)
+	}()
+
+	return snapshot
+}
+
+// Get returns a job by ID, including its result
+func (m *JobManager) Get(id string) (Job, error) {
+	m.mu.Lock()
+	_, own := m.cancels[id]
+	m.mu.Unlock()
+	if !own {
+		m.reload()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return Job{}, fmt.Errorf("job %s not found", id)
+	}
+	return m.snapshot(job), nil
+}
+
+// List returns all jobs without their results, newest first
+func (m *JobManager) List() []Job {
+	m.reload()
+	m.mu.Lock()
+	jobs := make([]Job, 0, len(m.jobs))
+	for _, job := range m.jobs {
+		c := m.snapshot(job)
+		c.Result = nil
+		jobs = append(jobs, c)
+	}
+	m.mu.Unlock()
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].Created.After(jobs[j].Created)
+	})
+	return jobs
+}
+
+// Cancel stops a running job. Jobs run by another process are asked to stop
+// through a marker file next to the job.
+func (m *JobManager) Cancel(id string) error {
+	job, err := m.Get(id)
+	if err != nil {
+		return err
+	}
+	if job.Done() {
+		return fmt.Errorf("job %s is already %s", id, job.Status)
+	}
+
+	m.mu.Lock()
+	cancel, ok := m.cancels[id]
+	m.mu.Unlock()
+	if ok {
+		cancel()
+		return nil
+	}
+	if m.dir == "" {
+		return fmt.Errorf("job %s is not running in this process", id)
+	}
+	return os.WriteFile(m.cancelPath(id), nil, 0o644)
+}
+
+// Remove deletes a finished job and its result
+func (m *JobManager) Remove(id string) error {
+	job, err := m.Get(id)
+	if err != nil {
+		return err
+	}
+	if !job.Done() {
+		return fmt.Errorf("job %s is still running", id)
+	}
+
+	m.mu.Lock()
+	delete(m.jobs, id)
+	m.mu.Unlock()
+	if m.dir != "" {
+		if err := os.Remove(m.jobPath(id)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		_ = os.Remove(m.cancelPath(id))
+	}
+	return nil
+}
+
+// Result decodes a finished job's result into v. Failed and cancelled jobs
+// may have a partial result.
+func (m *JobManager) Result(id string, v any) error {
+	job, err := m.Get(id)
+	if err != nil {
+		return err
+	}
+	if !job.Done() {
+		return fmt.Errorf("job %s is still running", id)
+	}
+	if len(job.Result) == 0 {
+		return fmt.Errorf("job %s is %s without a result", id, job.Status)
+	}
+	return json.Unmarshal(job.Result, v)
+}
+
+// Wait blocks until a job finishes or ctx is cancelled
+func (m *JobManager) Wait(ctx context.Context, id string) (Job, error) {
+	for {
+		job, err := m.Get(id)
+		if err != nil || job.Done() {
+			return job, err
+		}
+		select {
+		case <-ctx.Done():
+			return job, ctx.Err()
+		case <-time.After(m.heartbeat / 4):
+		}
+	}
+}
+
+func (m *JobManager) update(id string, fn func(*Job)) {
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	fn(job)
+	job.Updated = time.Now()
+	snapshot := m.snapshot(job)
+	m.mu.Unlock()
+	m.notify(snapshot)
+}
+
+func (m *JobManager) finish(ctx context.Context, id string, result any, err error) {
+	var data json.RawMessage
+	if result != nil {
+		if encoded, encodeErr := json.Marshal(result); encodeErr == nil {
+			data = encoded
+		} else if err == nil {
+			err = encodeErr
+		}
+	}
+
+	m.update(id, func(j *Job) {
+		now := time.Now()
+		j.Finished = &now
+		j.Result = data
+		switch {
+		case ctx.Err() != nil && (err == nil || errors.Is(err, context.Canceled)):
+			j.Status = JobCancelled
+		case err != nil:
+			j.Status = JobFailed
+			j.Error = err.Error()
+		default:
+			j.Status = JobCompleted
+			j.Progress = 1
+		}
+	})
+
+	// Persist before giving up the job, as jobs not run by this process
+	// are reloaded from their files
+	m.persist(id)
+	if m.dir != "" {
+		_ = os.Remove(m.cancelPath(id))
+	}
+	m.mu.Lock()
+	m.cancels[id]()
+	delete(m.cancels, id)
+	m.mu.Unlock()
+}
+
+// beat persists a running job's changes and picks up cancel requests made by
+// other processes
+func (m *JobManager) beat(id string, cancel context.CancelFunc, stop <-chan struct{}) {
+	ticker := time.NewTicker(m.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if m.dir == "" {
+				continue
+			}
+			if _, err := os.Stat(m.cancelPath(id)); err == nil {
+				cancel()
+			}
+			m.mu.Lock()
+			if job, ok := m.jobs[id]; ok {
+				job.Updated = time.Now()
+			}
+			m.mu.Unlock()
+			m.persist(id)
+		}
+	}
+}
+
+// reload picks up jobs persisted by other processes. Jobs run by this
+// process are kept as they are in memory.
+func (m *JobManager) reload() {
+	if m.dir == "" {
+		return
+	}
+	entries, err := os.ReadDir(m.dir)
+	if err != nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	seen := make(map[string]bool)
+	for _, entry := range entries {
+		id, ok := strings.CutSuffix(entry.Name(), ".json")
+		if !ok {
+			continue
+		}
+		seen[id] = true
+		if _, own := m.cancels[id]; own {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(m.dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		var job Job
+		if err := json.Unmarshal(data, &job); err != nil {
+			continue
+		}
+		if !job.Done() && time.Since(job.Updated) > jobStaleAfter {
+			job.Status = JobInterrupted
+		}
+		m.jobs[id] = &job
+	}
+
+	// Drop jobs removed by another process
+	for id := range m.jobs {
+		if _, own := m.cancels[id]; !own && !seen[id] {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+func (m *JobManager) persist(id string) {
+	if m.dir == "" {
+		return
+	}
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	if !ok {
+		m.mu.Unlock()
+		return
+	}
+	data, err := json.MarshalIndent(job, "", "  ")
+	m.mu.Unlock()
+	if err != nil {
+		return
+	}
+
+	// Write through a temporary file so readers never see a partial job
+	tmp := m.jobPath(id) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return
+	}
+	_ = os.Rename(tmp, m.jobPath(id))
+}
+
+func (m *JobManager) snapshot(job *Job) Job {
+	c := *job
+	c.Logs = slices.Clone(job.Logs)
+	return c
+}
+
+func (m *JobManager) notify(job Job) {
+	if m.OnChange != nil {
+		// Results can be large, listeners fetch them with Get
+		job.Result = nil
+		m.OnChange(job)
+	}
+}
+
+func (m *JobManager) jobPath(id string) string {
+	return filepath.Join(m.dir, id+".json")
+}
+
+func (m *JobManager) cancelPath(id string) string {
+	return filepath.Join(m.dir, id+".cancel")
+}
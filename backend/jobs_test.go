@@ -0,0 +1,157 @@
+package backend
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func waitForJob(t *testing.T, m *JobManager, id string) Job {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	job, err := m.Wait(ctx, id)
+	if err != nil {
+		t.Fatalf("Waiting for job %s: %v", id, err)
+	}
+	return job
+}
+
+func TestJobManager_CompletedJobPersists(t *testing.T) {
+	dir := t.TempDir()
+	m, err := NewJobManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	started := m.Start("scan", "/data", "Scan /data", func(job *JobContext) (any, error) {
+		job.Logf("working")
+		job.Progress(1, 2, "halfway")
+		return []string{"a", "b"}, nil
+	})
+	if started.Status != JobRunning {
+		t.Errorf("Expected running job, got %s", started.Status)
+	}
+
+	job := waitForJob(t, m, started.ID)
+	if job.Status != JobCompleted || job.Progress != 1 {
+		t.Errorf("Expected completed job at progress 1, got %s at %v", job.Status, job.Progress)
+	}
+	if len(job.Logs) != 1 {
+		t.Errorf("Expected 1 log line, got %d", len(job.Logs))
+	}
+
+	// A new manager, e.g. after a GUI reload, sees the job and its result
+	reloaded, err := NewJobManager(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var result []string
+	if err := reloaded.Result(started.ID, &result); err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 || result[1] != "b" {
+		t.Errorf("Unexpected persisted result: %v", result)
+	}
+	if jobs := reloaded.List(); len(jobs) != 1 || jobs[0].Result != nil {
+		t.Errorf("Expected 1 listed job without result, got %+v", jobs)
+	}
+}
+
+func TestJobManager_FailedJob(t *testing.T) {
+	m, _ := NewJobManager("")
+	started := m.Start("apply", "abc", "Apply", func(job *JobContext) (any, error) {
+		return nil, errors.New("boom")
+	})
+
+	job := waitForJob(t, m, started.ID)
+	if job.Status != JobFailed || job.Error != "boom" {
+		t.Errorf("Expected failed job with error, got %s %q", job.Status, job.Error)
+	}
+	if err := m.Result(started.ID, &struct{}{}); err == nil {
+		t.Error("Expected error reading the result of a failed job without one")
+	}
+}
+
+func TestJobManager_Cancel(t *testing.T) {
+	m, _ := NewJobManager("")
+	started := m.Start("scan", "", "Scan", func(job *JobContext) (any, error) {
+		<-job.Done()
+		return nil, job.Err()
+	})
+
+	if err := m.Remove(started.ID); err == nil {
+		t.Error("Expected error removing a running job")
+	}
+	if err := m.Cancel(started.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	job := waitForJob(t, m, started.ID)
+	if job.Status != JobCancelled {
+		t.Errorf("Expected cancelled job, got %s", job.Status)
+	}
+	if err := m.Cancel(started.ID); err == nil {
+		t.Error("Expected error cancelling a finished job")
+	}
+	if err := m.Remove(started.ID); err != nil {
+		t.Error(err)
+	}
+	if len(m.List()) != 0 {
+		t.Error("Expected no jobs after removal")
+	}
+}
+
+func TestJobManager_CancelFromOtherProcess(t *testing.T) {
+	oldInterval := jobHeartbeatInterval
+	jobHeartbeatInterval = 10 * time.Millisecond
+	defer func() { jobHeartbeatInterval = oldInterval }()
+
+	dir := t.TempDir()
+	runner, _ := NewJobManager(dir)
+	started := runner.Start("scan", "", "Scan", func(job *JobContext) (any, error) {
+		<-job.Done()
+		return nil, job.Err()
+	})
+
+	// A second manager stands in for the CLI
+	other, _ := NewJobManager(dir)
+	if err := other.Cancel(started.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if job := waitForJob(t, runner, started.ID); job.Status != JobCancelled {
+		t.Errorf("Expected cancelled job, got %s", job.Status)
+	}
+	if job := waitForJob(t, other, started.ID); job.Status != JobCancelled {
+		t.Errorf("Expected other manager to see cancelled job, got %s", job.Status)
+	}
+}
+
+func TestJobManager_StaleJobInterrupted(t *testing.T) {
+	dir := t.TempDir()
+	stale := Job{
+		ID:      "stale",
+		Kind:    "scan",
+		Status:  JobRunning,
+		Created: time.Now().Add(-time.Hour),
+		Updated: time.Now().Add(-time.Hour),
+	}
+	data, _ := json.Marshal(stale)
+	if err := os.WriteFile(filepath.Join(dir, "stale.json"), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	m, _ := NewJobManager(dir)
+	job, err := m.Get("stale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if job.Status != JobInterrupted {
+		t.Errorf("Expected interrupted job, got %s", job.Status)
+	}
+}
@@ -0,0 +1,207 @@
+package backend
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Link methods, tried in DefaultLinkMethods order until one works
+const (
+	LinkHardlink = "hardlink"
+	LinkReflink  = "reflink" // copy-on-write clone, Linux only
+	LinkSymlink  = "symlink"
+	LinkCopy     = "copy"
+	LinkExisting = "existing" // the target already was the source file
+)
+
+// DefaultLinkMethods prefers links that need no extra space. Hardlinks only
+// work within a filesystem, so reflinks and symlinks are tried next, and a
+// plain copy is the last resort.
+var DefaultLinkMethods = []string{LinkHardlink, LinkReflink, LinkSymlink, LinkCopy}
+
+// ParseLinkMethods parses a comma-separated list of link methods
+func ParseLinkMethods(value string) ([]string, error) {
+	var methods []string
+	for _, m := range strings.Split(value, ",") {
+		m = strings.TrimSpace(m)
+		switch m {
+		case LinkHardlink, LinkReflink, LinkSymlink, LinkCopy:
+			methods = append(methods, m)
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown link method %q", m)
+		}
+	}
+	if len(methods) == 0 {
+		return nil, fmt.Errorf("no link methods given")
+	}
+	return methods, nil
+}
+
+// LinkOperation places a matched disk file where the torrent expects it,
+// leaving both the disk file and the torrent's file names untouched
+type LinkOperation struct {
+	Source      string          `json:"source"` // the matched disk file
+	Target      string          `json:"target"` // save path joined with the torrent file name
+	TorrentFile TorrentFileInfo `json:"torrentFile"`
+	Method      string          `json:"method,omitempty"` // how it was linked, once done
+}
+
+// GenerateLinks returns the links that put every selected disk file at the
+// torrent file's path under savePath. Files already in place are left out.
+// savePath is the torrent's save path as seen from this machine.
+func GenerateLinks(matches []Match, savePath string) []LinkOperation {
+	links := []LinkOperation{}
+	for _, m := range matches {
+		if m.Selected == nil {
+			continue
+		}
+		target := filepath.Join(savePath, filepath.FromSlash(m.TorrentFile.Name))
+		if samePath(target, m.Selected.Path) {
+			continue
+		}
+		links = append(links, LinkOperation{
+			Source:      m.Selected.Path,
+			Target:      target,
+			TorrentFile: m.TorrentFile,
+		})
+	}
+	return links
+}
+
+// LinkResult summarises a batch of links
+type LinkResult struct {
+	LinkedCount int             `json:"linkedCount"`
+	FailedCount int             `json:"failedCount"`
+	Linked      []LinkOperation `json:"linked"` // with the method used
+	Errors      []string        `json:"errors"`
+}
+
+// LinkFiles creates every link with the first of methods that works,
+// DefaultLinkMethods when empty. Existing files are never replaced; a target
+// that already is the source file counts as linked. A failed link is
+// recorded and the remaining ones are still created.
+func LinkFiles(ctx context.Context, links []LinkOperation, methods []string) LinkResult {
+	if len(methods) == 0 {
+		methods = DefaultLinkMethods
+	}
+
+	result := LinkResult{Linked: []LinkOperation{}, Errors: []string{}}
+	for _, l := range links {
+		if err := ctx.Err(); err != nil {
+			result.Errors = append(result.Errors, err.Error())
+			break
+		}
+
+		method, err := linkFile(l.Source, l.Target, methods)
+		if err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", l.Target, err))
+			continue
+		}
+		l.Method = method
+		result.LinkedCount++
+		result.Linked = append(result.Linked, l)
+	}
+	metricLinks.Add(float64(result.LinkedCount), "ok")
+	metricLinks.Add(float64(result.FailedCount), "failed")
+	return result
+}
+
+// linkFile links source to target with the first method that works and
+// returns that method
+func linkFile(source, target string, methods []string) (string, error) {
+	sourceInfo, err := os.Stat(source)
+	if err != nil {
+		return "", err
+	}
+	if targetInfo, err := os.Stat(target); err == nil {
+		if os.SameFile(sourceInfo, targetInfo) {
+			return LinkExisting, nil
+		}
+		return "", fmt.Errorf("target already exists")
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", err
+	}
+
+	var errs []error
+	for _, method := range methods {
+		var err error
+		switch method {
+		case LinkHardlink:
+			err = os.Link(source, target)
+		case LinkReflink:
+			err = reflinkFile(source, target)
+		case LinkSymlink:
+			var absolute string
+			if absolute, err = filepath.Abs(source); err == nil {
+				err = os.Symlink(absolute, target)
+			}
+		case LinkCopy:
+			err = copyFile(source, target)
+		default:
+			err = fmt.Errorf("unknown link method")
+		}
+		if err == nil {
+			return method, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", method, err))
+	}
+	return "", errors.Join(errs...)
+}
+
+// copyFile copies source to a new file at target, removing the partial
+// file on failure
+func copyFile(source, target string) (err error) {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(target)
+		}
+	}()
+
+	_, err = io.Copy(out, in)
+	return err
+}
+
+// RemoveFailedLinks removes the links created for files that failed
+// verification, leaving the disk files they point at alone, and returns the
+// removed links
+func RemoveFailedLinks(linked []LinkOperation, report RecheckReport) ([]LinkOperation, []error) {
+	failed := make(map[int]bool)
+	for _, f := range report.Failed() {
+		failed[f.Index] = true
+	}
+
+	var removed []LinkOperation
+	var errs []error
+	for _, l := range linked {
+		if !failed[l.TorrentFile.Index] || l.Method == LinkExisting {
+			continue
+		}
+		if err := os.Remove(l.Target); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, l)
+	}
+	return removed, errs
+}
@@ -0,0 +1,37 @@
+package backend
+
+import (
+	"os"
+	"syscall"
+)
+
+// FICLONE from linux/fs.h
+const ficlone = 0x40049409
+
+// reflinkFile clones source to target without copying its data, on
+// filesystems with copy-on-write support such as Btrfs and XFS
+func reflinkFile(source, target string) (err error) {
+	in, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := out.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(target)
+		}
+	}()
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, out.Fd(), ficlone, in.Fd()); errno != 0 {
+		return errno
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package backend
+
+import "errors"
+
+// reflinkFile is only implemented on Linux
+func reflinkFile(source, target string) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,124 @@
+package backend
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGenerateLinks(t *testing.T) {
+	matches := []Match{
+		{TorrentFile: TorrentFileInfo{Index: 0, Name: "Show.S01/E01.mkv"}, Selected: &DiskFile{Path: "/library/Show/Season 1/E01.mkv"}},
+		{TorrentFile: TorrentFileInfo{Index: 1, Name: "Show.S01/E02.mkv"}, Selected: &DiskFile{Path: "/downloads/Show.S01/E02.mkv"}},
+		{TorrentFile: TorrentFileInfo{Index: 2, Name: "Show.S01/E03.mkv"}},
+	}
+
+	links := GenerateLinks(matches, "/downloads")
+
+	// E02 is already in place and E03 has no selection
+	if len(links) != 1 {
+		t.Fatalf("Expected 1 link, got %+v", links)
+	}
+	if links[0].Source != "/library/Show/Season 1/E01.mkv" || links[0].Target != filepath.Join("/downloads", "Show.S01", "E01.mkv") {
+		t.Errorf("Unexpected link: %+v", links[0])
+	}
+}
+
+func TestLinkFiles(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "library", "E01.mkv")
+	os.MkdirAll(filepath.Dir(source), 0755)
+	if err := os.WriteFile(source, []byte("episode"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(dir, "downloads", "Show.S01", "E01.mkv")
+	links := []LinkOperation{{Source: source, Target: target, TorrentFile: TorrentFileInfo{Index: 0}}}
+
+	result := LinkFiles(context.Background(), links, nil)
+	if result.LinkedCount != 1 || result.Linked[0].Method != LinkHardlink {
+		t.Fatalf("Expected a hardlink, got %+v", result)
+	}
+	sourceInfo, _ := os.Stat(source)
+	targetInfo, err := os.Stat(target)
+	if err != nil || !os.SameFile(sourceInfo, targetInfo) {
+		t.Errorf("Expected target to be a hardlink of the source (%v)", err)
+	}
+
+	// Linking again finds the link in place
+	again := LinkFiles(context.Background(), links, nil)
+	if again.LinkedCount != 1 || again.Linked[0].Method != LinkExisting {
+		t.Errorf("Expected the existing link to be reused, got %+v", again)
+	}
+}
+
+func TestLinkFiles_NeverReplaces(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "a.mkv")
+	target := filepath.Join(dir, "b.mkv")
+	os.WriteFile(source, []byte("new"), 0644)
+	os.WriteFile(target, []byte("old"), 0644)
+
+	result := LinkFiles(context.Background(), []LinkOperation{{Source: source, Target: target}}, nil)
+
+	if result.FailedCount != 1 {
+		t.Errorf("Expected existing target to fail, got %+v", result)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "old" {
+		t.Errorf("Expected target to be left alone, got %q", data)
+	}
+}
+
+func TestLinkFiles_Fallback(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "a.mkv")
+	os.WriteFile(source, []byte("episode"), 0644)
+
+	for _, method := range []string{LinkSymlink, LinkCopy} {
+		target := filepath.Join(dir, method, "a.mkv")
+		result := LinkFiles(context.Background(), []LinkOperation{{Source: source, Target: target}}, []string{method})
+		if result.LinkedCount != 1 || result.Linked[0].Method != method {
+			t.Errorf("Expected %s, got %+v", method, result)
+			continue
+		}
+		if data, err := os.ReadFile(target); err != nil || string(data) != "episode" {
+			t.Errorf("Expected %s target to read as the source, got %q (%v)", method, data, err)
+		}
+	}
+}
+
+func TestRemoveFailedLinks(t *testing.T) {
+	dir := t.TempDir()
+	source := filepath.Join(dir, "a.mkv")
+	os.WriteFile(source, []byte("episode"), 0644)
+	linked := []LinkOperation{
+		{Source: source, Target: filepath.Join(dir, "t0.mkv"), TorrentFile: TorrentFileInfo{Index: 0}, Method: LinkHardlink},
+		{Source: source, Target: filepath.Join(dir, "t1.mkv"), TorrentFile: TorrentFileInfo{Index: 1}, Method: LinkHardlink},
+	}
+	for _, l := range linked {
+		os.Link(l.Source, l.Target)
+	}
+	report := RecheckReport{Files: []RecheckFile{
+		{Index: 0, Complete: true, Progress: 1},
+		{Index: 1, Progress: 0.5},
+	}}
+
+	removed, errs := RemoveFailedLinks(linked, report)
+
+	if len(errs) != 0 || len(removed) != 1 || removed[0].TorrentFile.Index != 1 {
+		t.Errorf("Expected only the failed link to be removed, got %+v %v", removed, errs)
+	}
+	if _, err := os.Stat(source); err != nil {
+		t.Errorf("Expected source to be kept: %v", err)
+	}
+}
+
+func TestParseLinkMethods(t *testing.T) {
+	methods, err := ParseLinkMethods("hardlink, copy")
+	if err != nil || len(methods) != 2 || methods[1] != LinkCopy {
+		t.Errorf("Unexpected methods %v (%v)", methods, err)
+	}
+	if _, err := ParseLinkMethods("move"); err == nil {
+		t.Error("Expected error for unknown method")
+	}
+}
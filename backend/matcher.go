@@ -1,7 +1,11 @@
 package backend
 
 import (
+	"cmp"
+	"path"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 )
 
@@ -10,6 +14,29 @@ type TorrentFileInfo struct {
 	Index int    `json:"index"`
 	Name  string `json:"name"`
 	Size  int64  `json:"size"`
+	Pad   bool   `json:"pad,omitempty"` // BEP 47 pad file, see IsPad
+	// Fingerprint of a reference copy, see ReferenceFingerprints. Candidates
+	// with another fingerprint are dropped.
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// IsPad reports whether the file only pads the previous file to a piece
+// boundary. Pad files carry the BEP 47 "p" attribute and are named
+// ".pad/<size>", or "_____padding_file_..." by older clients; they never
+// exist on disk.
+func (f TorrentFileInfo) IsPad() bool {
+	return f.Pad || IsPadPath(f.Name)
+}
+
+// NeedsDiskFile reports whether the file has to be matched to a disk file.
+// Pad files never exist and empty files are created by qBittorrent itself.
+func (f TorrentFileInfo) NeedsDiskFile() bool {
+	return f.Size > 0 && !f.IsPad()
+}
+
+// IsPadPath reports whether a torrent file path names a pad file
+func IsPadPath(name string) bool {
+	return strings.Contains("/"+name, "/.pad/") || strings.HasPrefix(path.Base(name), "_____padding_file_")
 }
 
 // Match represents a potential match between a torrent file and a disk file
@@ -18,45 +45,119 @@ type Match struct {
 	DiskFiles   []DiskFile      `json:"diskFiles"`
 	Selected    *DiskFile       `json:"selected,omitempty"`
 	AutoMatched bool            `json:"autoMatched"`
+	Score       float64         `json:"score"` // name similarity of the best candidate, 0 to 1
+	// SizeMismatch is set when no file had the exact size and the candidates
+	// are only within the size tolerance; these are never auto-selected
+	SizeMismatch bool `json:"sizeMismatch"`
+	// Partial is set when the candidates are smaller files with a similar
+	// name, e.g. from an aborted download. The pieces they fully contain
+	// verify and qBittorrent downloads the rest.
+	Partial bool `json:"partial"`
+	// Rule names the rename rule that mapped the torrent file to its
+	// candidates, see RuleSet
+	Rule string `json:"rule,omitempty"`
+	// Exact is set when the selection rests on more than a similar name: a
+	// file of the same name and size, a rule, a matching fingerprint or a
+	// copy verified by piece hashes
+	Exact bool `json:"exact"`
 }
 
 // MatchResult represents the result of matching a torrent with disk files
 type MatchResult struct {
 	Matches      []Match           `json:"matches"`
 	Unmatched    []TorrentFileInfo `json:"unmatched"`
+	Skipped      []TorrentFileInfo `json:"skipped"` // pad and empty files, not counted in TotalFiles
 	TotalFiles   int               `json:"totalFiles"`
 	MatchedCount int               `json:"matchedCount"`
+	// RuleErrors lists the torrent files a rename rule failed to map, e.g.
+	// because its template uses a field the file name lacks; they are
+	// matched by size instead
+	RuleErrors []string `json:"ruleErrors,omitempty"`
+}
+
+// MatchOptions controls how candidates are found
+type MatchOptions struct {
+	RequireSameExtension bool
+	// SizeTolerance, when above 0, proposes files whose size differs by at
+	// most this many bytes when no file has the exact size, e.g. files whose
+	// tags were rewritten in place
+	SizeTolerance int64
+	// Partial proposes files smaller than the torrent file, with the same
+	// extension and a similar name, when nothing else matched
+	PartialFiles bool
+	// SplitJoin rebuilds unmatched torrent files from numbered parts on
+	// disk, or cuts them out of a disk file that holds several of them
+	// back to back, see matchReassemblies
+	SplitJoin bool
+	// Rules, when set, map torrent paths to expected disk paths before
+	// size matching. A file of the right size at the mapped path is
+	// selected without ranking.
+	Rules *RuleSet
 }
 
 // FindMatches finds potential matches between torrent files and disk files
 func FindMatches(torrentFiles []TorrentFileInfo, diskFiles []DiskFile, requireSameExtension bool) MatchResult {
+	return FindMatchesWithOptions(torrentFiles, diskFiles, MatchOptions{RequireSameExtension: requireSameExtension})
+}
+
+// FindMatchesWithOptions is FindMatches with a size tolerance, partial files
+// and split or joined files
+func FindMatchesWithOptions(torrentFiles []TorrentFileInfo, diskFiles []DiskFile, opts MatchOptions) MatchResult {
 	result := MatchResult{
-		Matches:    []Match{},
-		Unmatched:  []TorrentFileInfo{},
-		TotalFiles: len(torrentFiles),
+		Matches:   []Match{},
+		Unmatched: []TorrentFileInfo{},
+		Skipped:   []TorrentFileInfo{},
 	}
 
 	// Group disk files by size for O(1) lookup
 	sizeMap := GroupFilesBySize(diskFiles)
 
-	for _, tf := range torrentFiles {
-		candidates := sizeMap[tf.Size]
+	var bySize []DiskFile
+	if opts.SizeTolerance > 0 || opts.PartialFiles {
+		bySize = slices.Clone(diskFiles)
+		slices.SortFunc(bySize, func(a, b DiskFile) int { return cmp.Compare(a.Size, b.Size) })
+	}
 
-		if len(candidates) == 0 {
-			result.Unmatched = append(result.Unmatched, tf)
+	for _, tf := range torrentFiles {
+		if !tf.NeedsDiskFile() {
+			result.Skipped = append(result.Skipped, tf)
 			continue
 		}
+		result.TotalFiles++
 
-		// Filter by extension if required
-		if requireSameExtension {
-			tfExt := strings.ToLower(filepath.Ext(tf.Name))
-			filtered := []DiskFile{}
-			for _, c := range candidates {
-				if strings.ToLower(filepath.Ext(c.Name)) == tfExt {
-					filtered = append(filtered, c)
+		if opts.Rules != nil {
+			ruled, rule, err := opts.Rules.ruleCandidates(tf, sizeMap[tf.Size])
+			if err != nil {
+				result.RuleErrors = append(result.RuleErrors, err.Error())
+			}
+			if len(ruled) > 0 {
+				match := Match{TorrentFile: tf, DiskFiles: ruled, Rule: rule}
+				// Several files only when the mapped path is under several roots
+				ranked, scores := collapseCopies(rankCandidates(tf, ruled))
+				match.DiskFiles, match.Score = ranked, scores[0]
+				if len(ranked) == 1 {
+					match.Selected = &ranked[0]
+					match.AutoMatched = true
+					match.Exact = true
+					result.MatchedCount++
 				}
+				result.Matches = append(result.Matches, match)
+				continue
 			}
-			candidates = filtered
+		}
+
+		candidates := withFingerprint(filterCandidates(sizeMap[tf.Size], tf, opts), tf.Fingerprint)
+		sizeMismatch := false
+
+		if len(candidates) == 0 && opts.SizeTolerance > 0 {
+			candidates = filterCandidates(filesNearSize(bySize, tf.Size, opts.SizeTolerance), tf, opts)
+			sizeMismatch = true
+		}
+
+		partial := false
+		if len(candidates) == 0 && opts.PartialFiles {
+			candidates = partialCandidates(bySize, tf)
+			sizeMismatch, partial = false, true
 		}
 
 		if len(candidates) == 0 {
@@ -65,34 +166,123 @@ func FindMatches(torrentFiles []TorrentFileInfo, diskFiles []DiskFile, requireSa
 		}
 
 		match := Match{
-			TorrentFile: tf,
-			DiskFiles:   candidates,
-			AutoMatched: false,
+			TorrentFile:  tf,
+			DiskFiles:    candidates,
+			AutoMatched:  false,
+			SizeMismatch: sizeMismatch,
+			Partial:      partial,
 		}
 
+		// Rank candidates by name similarity, best first, keeping the best
+		// named of identical copies
+		ranked, scores := collapseCopies(rankCandidates(tf, candidates))
+		match.DiskFiles = ranked
+		match.Score = scores[0]
+
 		// Auto-match if there's exactly one candidate
-		if len(candidates) == 1 {
-			match.Selected = &candidates[0]
+		if sizeMismatch {
+			// Leave the choice to the user, ideally after verifying pieces
+		} else if partial {
+			// Only the name tells partial files apart, so it has to be
+			// clearly the closest
+			if scores[0] >= fuzzyAutoSelectScore && (len(scores) == 1 || scores[0]-scores[1] >= fuzzyAutoSelectMargin) {
+				match.Selected = &ranked[0]
+				match.AutoMatched = true
+				result.MatchedCount++
+			}
+		} else if len(ranked) == 1 {
+			match.Selected = &ranked[0]
 			match.AutoMatched = true
 			result.MatchedCount++
 		} else {
 			// Try to find exact name match
-			for i, c := range candidates {
-				if strings.EqualFold(filepath.Base(c.Path), tf.Name) {
-					match.Selected = &candidates[i]
-					match.AutoMatched = true
-					result.MatchedCount++
+			for i, c := range ranked {
+				if strings.EqualFold(normalizeName(filepath.Base(c.Path)), normalizeName(tf.Name)) {
+					match.Selected = &ranked[i]
 					break
 				}
 			}
+			// Otherwise take a clearly more similar name, e.g. one that
+			// differs only by release group tags or separators
+			if match.Selected == nil && scores[0] >= fuzzyAutoSelectScore && scores[0]-scores[1] >= fuzzyAutoSelectMargin {
+				match.Selected = &ranked[0]
+			}
+			if match.Selected != nil {
+				match.AutoMatched = true
+				result.MatchedCount++
+			}
+		}
+		if match.Selected != nil && !sizeMismatch && !partial {
+			match.Exact = exactSelection(tf, *match.Selected)
 		}
 
 		result.Matches = append(result.Matches, match)
 	}
 
+	if opts.SplitJoin && len(result.Unmatched) > 0 {
+		result = matchReassemblies(result, torrentFiles, diskFiles, opts)
+	}
+
+	recordMatch(result)
 	return result
 }
 
+// exactSelection reports whether a same-size file is the torrent file by more
+// than a similar name: it has the same file name, or the fingerprint of a
+// reference copy
+func exactSelection(tf TorrentFileInfo, selected DiskFile) bool {
+	if tf.Fingerprint != "" && selected.Fingerprint == tf.Fingerprint {
+		return true
+	}
+	return strings.EqualFold(normalizeName(filepath.Base(selected.Path)), normalizeName(path.Base(tf.Name)))
+}
+
+// filterCandidates drops candidates with a different extension when required
+func filterCandidates(candidates []DiskFile, tf TorrentFileInfo, opts MatchOptions) []DiskFile {
+	if !opts.RequireSameExtension {
+		return candidates
+	}
+	tfExt := strings.ToLower(filepath.Ext(tf.Name))
+	filtered := []DiskFile{}
+	for _, c := range candidates {
+		if strings.ToLower(filepath.Ext(c.Name)) == tfExt {
+			filtered = append(filtered, c)
+		}
+	}
+	return filtered
+}
+
+// filesNearSize returns the files, sorted by size, within tolerance bytes of size
+func filesNearSize(bySize []DiskFile, size int64, tolerance int64) []DiskFile {
+	start, _ := slices.BinarySearchFunc(bySize, size-tolerance, func(f DiskFile, target int64) int {
+		return cmp.Compare(f.Size, target)
+	})
+	end := start
+	for end < len(bySize) && bySize[end].Size <= size+tolerance {
+		end++
+	}
+	return bySize[start:end]
+}
+
+// partialCandidates returns the non-empty files, from bySize, that are
+// smaller than the torrent file, share its extension and have a similar name
+func partialCandidates(bySize []DiskFile, tf TorrentFileInfo) []DiskFile {
+	ext := strings.ToLower(filepath.Ext(tf.Name))
+	candidates := []DiskFile{}
+	for _, f := range bySize {
+		if f.Size >= tf.Size {
+			break
+		}
+		if f.Size == 0 || strings.ToLower(filepath.Ext(f.Name)) != ext {
+			continue
+		}
+		if MatchScore(tf.Name, f.Path) >= partialMinScore {
+			candidates = append(candidates, f)
+		}
+	}
+	return candidates
+}
+
 // GenerateRenames generates the rename operations needed to match files
 // Note: qBittorrent API uses forward slashes for paths on all platforms
 // searchPath is the directory that was scanned for disk files - the new path
@@ -114,22 +304,24 @@ func GenerateRenames(matches []Match, searchPath string) []RenameOperation {
 		// This matches the Python implementation:
 		//   new_relative_path = Path(selected_file_path).relative_to(download_path)
 		diskFilePath := filepath.Clean(m.Selected.Path)
-		relPath, err := filepath.Rel(searchPath, diskFilePath)
+		rel, err := relPath(searchPath, diskFilePath)
 		if err != nil {
 			// If we can't compute relative path, skip this file
 			continue
 		}
 
 		// Convert to forward slashes for qBittorrent API (works on all platforms)
-		newPath := filepath.ToSlash(relPath)
+		newPath := filepath.ToSlash(rel)
 
-		// Only add rename if paths are different
-		if oldPath != newPath {
+		// Only add rename if paths name different files on this platform,
+		// e.g. not for a difference in case on Windows or macOS
+		if !sameTorrentPath(oldPath, newPath) {
 			renames = append(renames, RenameOperation{
 				OldPath:     oldPath,
 				NewPath:     newPath,
 				TorrentFile: m.TorrentFile,
 				DiskFile:    *m.Selected,
+				Partial:     m.Partial,
 			})
 		}
 	}
@@ -137,10 +329,176 @@ func GenerateRenames(matches []Match, searchPath string) []RenameOperation {
 	return renames
 }
 
+// CollapseFolderRenames replaces file renames with a single folder rename
+// wherever every file under a torrent folder moves to the same new directory.
+// torrentFiles must contain all files of the torrent, so that folders holding
+// files without a rename (unmatched or already in place) are left alone.
+// Renames that cannot be folded are returned unchanged.
+func CollapseFolderRenames(renames []RenameOperation, torrentFiles []TorrentFileInfo) []RenameOperation {
+	if len(renames) < 2 {
+		return renames
+	}
+
+	byOldPath := make(map[string]RenameOperation, len(renames))
+	for _, r := range renames {
+		byOldPath[r.OldPath] = r
+	}
+
+	// Collect every folder in the torrent, shallowest first, so the largest
+	// possible subtree is folded before its children are considered
+	folderSet := make(map[string]bool)
+	for _, tf := range torrentFiles {
+		dir := path.Dir(tf.Name)
+		for dir != "." && dir != "/" {
+			folderSet[dir] = true
+			dir = path.Dir(dir)
+		}
+	}
+	folders := make([]string, 0, len(folderSet))
+	for f := range folderSet {
+		folders = append(folders, f)
+	}
+	sort.Slice(folders, func(i, j int) bool {
+		di, dj := strings.Count(folders[i], "/"), strings.Count(folders[j], "/")
+		if di != dj {
+			return di < dj
+		}
+		return folders[i] < folders[j]
+	})
+
+	folded := make(map[string]bool)               // old file path -> consumed by a folder rename
+	skipped := make(map[string]bool)              // folders inside an already folded folder
+	folderOps := make(map[string]RenameOperation) // old file path of first file -> folder op
+
+	for _, folder := range folders {
+		if skipped[folder] {
+			continue
+		}
+		prefix := folder + "/"
+
+		newFolder, count, ok := commonFolderTarget(prefix, byOldPath, torrentFiles)
+		if !ok {
+			continue
+		}
+
+		// Don't merge into a folder that already holds other torrent files
+		if folderInUse(newFolder+"/", prefix, torrentFiles, renames) {
+			continue
+		}
+
+		first := ""
+		for _, r := range renames {
+			if strings.HasPrefix(r.OldPath, prefix) {
+				folded[r.OldPath] = true
+				if first == "" {
+					first = r.OldPath
+				}
+			}
+		}
+		for _, sub := range folders {
+			if strings.HasPrefix(sub, prefix) {
+				skipped[sub] = true
+			}
+		}
+
+		folderOps[first] = RenameOperation{
+			OldPath:   folder,
+			NewPath:   newFolder,
+			IsFolder:  true,
+			FileCount: count,
+		}
+	}
+
+	if len(folderOps) == 0 {
+		return renames
+	}
+
+	result := make([]RenameOperation, 0, len(renames))
+	for _, r := range renames {
+		if op, ok := folderOps[r.OldPath]; ok {
+			result = append(result, op)
+			continue
+		}
+		if !folded[r.OldPath] {
+			result = append(result, r)
+		}
+	}
+
+	return result
+}
+
+// commonFolderTarget checks that every torrent file under prefix has a rename
+// and that all of them keep their path relative to the folder. It returns the
+// new folder path and the number of files it contains.
+func commonFolderTarget(prefix string, byOldPath map[string]RenameOperation, torrentFiles []TorrentFileInfo) (string, int, bool) {
+	newFolder := ""
+	count := 0
+
+	for _, tf := range torrentFiles {
+		// Pad and empty files have no rename; they move with the folder
+		if !strings.HasPrefix(tf.Name, prefix) || !tf.NeedsDiskFile() {
+			continue
+		}
+
+		r, ok := byOldPath[tf.Name]
+		if !ok {
+			return "", 0, false
+		}
+
+		rest := strings.TrimPrefix(tf.Name, prefix)
+		if !strings.HasSuffix(r.NewPath, "/"+rest) {
+			return "", 0, false
+		}
+		target := strings.TrimSuffix(r.NewPath, "/"+rest)
+
+		if count == 0 {
+			newFolder = target
+		} else if target != newFolder {
+			return "", 0, false
+		}
+		count++
+	}
+
+	// Moving a folder to the root, into itself or into one of its parents
+	// can't be done in one call
+	if count == 0 || newFolder == "" ||
+		strings.HasPrefix(newFolder+"/", prefix) || strings.HasPrefix(prefix, newFolder+"/") {
+		return "", 0, false
+	}
+
+	return newFolder, count, true
+}
+
+// folderInUse reports whether any torrent file outside oldPrefix already
+// lives under newPrefix, or is renamed into it by another rename of the
+// batch. Paths compare the way this platform's file systems do.
+func folderInUse(newPrefix string, oldPrefix string, torrentFiles []TorrentFileInfo, renames []RenameOperation) bool {
+	for _, tf := range torrentFiles {
+		if hasTorrentPrefix(tf.Name, newPrefix) && !hasTorrentPrefix(tf.Name, oldPrefix) {
+			return true
+		}
+	}
+	for _, r := range renames {
+		if hasTorrentPrefix(r.NewPath, newPrefix) && !hasTorrentPrefix(r.OldPath, oldPrefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTorrentPrefix is strings.HasPrefix for torrent paths, see sameTorrentPath
+func hasTorrentPrefix(name string, prefix string) bool {
+	return strings.HasPrefix(nameKey(name), nameKey(prefix))
+}
+
 // RenameOperation represents a single rename operation
+// Folder operations rename a whole torrent folder and carry no file details
 type RenameOperation struct {
 	OldPath     string          `json:"oldPath"`
 	NewPath     string          `json:"newPath"`
 	TorrentFile TorrentFileInfo `json:"torrentFile"`
 	DiskFile    DiskFile        `json:"diskFile"`
+	IsFolder    bool            `json:"isFolder"`
+	FileCount   int             `json:"fileCount"`
+	Partial     bool            `json:"partial,omitempty"` // the disk file is a partial download
 }
@@ -1,6 +1,8 @@
 package backend
 
 import (
+	"context"
+	"log"
 	"os"
 	"path/filepath"
 )
@@ -13,6 +15,14 @@ type DiskFileInfo struct {
 	Path string `json:"path"`
 	Name string `json:"name"`
 	Size int64  `json:"size"`
+
+	Archive string `json:"archive,omitempty"`
+	Entry   string `json:"entry,omitempty"`
+
+	Parts []FileSegment `json:"parts,omitempty"`
+
+	Fingerprint string   `json:"fingerprint,omitempty"`
+	Copies      []string `json:"copies,omitempty"`
 }
 
 // ScanDir scans a directory and returns all files
@@ -39,55 +49,111 @@ func (s *MatcherService) ScanDir(path string) ([]DiskFileInfo, error) {
 	return result, nil
 }
 
+// ExportOrphans saves an orphan report as JSON, or as CSV when path ends in .csv
+func (s *MatcherService) ExportOrphans(report OrphanReport, path string) error {
+	return ExportOrphans(report, path)
+}
+
+// ListRuleProfiles returns the names of the rename rule profiles
+func (s *MatcherService) ListRuleProfiles() ([]string, error) {
+	path, err := DefaultRulesPath()
+	if err != nil {
+		return nil, err
+	}
+	config, err := LoadRulesConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return config.ProfileNames(), nil
+}
+
 // MatchRequest represents a request to find matches
 type MatchRequest struct {
 	TorrentFiles         []TorrentFileInfo `json:"torrentFiles"`
 	DiskFiles            []DiskFile        `json:"diskFiles"`
 	RequireSameExtension bool              `json:"requireSameExtension"`
+	SizeTolerance        int64             `json:"sizeTolerance"` // bytes, 0 for exact sizes only
+	PartialFiles         bool              `json:"partialFiles"`  // also offer smaller, partially downloaded files
+	Fingerprints         bool              `json:"fingerprints"`  // sample same-size files to collapse identical copies
+	RulesProfile         string            `json:"rulesProfile"`  // rename rules to apply first, none when empty
 }
 
 // MatchResponse represents the match results
 type MatchResponse struct {
 	Matches      []MatchInfo       `json:"matches"`
 	Unmatched    []TorrentFileInfo `json:"unmatched"`
+	Skipped      []TorrentFileInfo `json:"skipped"`
 	TotalFiles   int               `json:"totalFiles"`
 	MatchedCount int               `json:"matchedCount"`
+	RuleErrors   []string          `json:"ruleErrors,omitempty"` // torrent files a rule failed to map
 }
 
 // MatchInfo represents a single match for the frontend
 type MatchInfo struct {
-	TorrentFile TorrentFileInfo `json:"torrentFile"`
-	DiskFiles   []DiskFile      `json:"diskFiles"`
-	Selected    *DiskFile       `json:"selected"`
-	AutoMatched bool            `json:"autoMatched"`
+	TorrentFile  TorrentFileInfo `json:"torrentFile"`
+	DiskFiles    []DiskFile      `json:"diskFiles"`
+	Selected     *DiskFile       `json:"selected"`
+	AutoMatched  bool            `json:"autoMatched"`
+	Score        float64         `json:"score"`
+	SizeMismatch bool            `json:"sizeMismatch"`
+	Partial      bool            `json:"partial"`
+	Rule         string          `json:"rule,omitempty"`
+	Exact        bool            `json:"exact"`
 }
 
 // FindMatches finds matches between torrent files and disk files
-func (s *MatcherService) FindMatches(req MatchRequest) MatchResponse {
-	result := FindMatches(req.TorrentFiles, req.DiskFiles, req.RequireSameExtension)
+func (s *MatcherService) FindMatches(req MatchRequest) (MatchResponse, error) {
+	if req.Fingerprints {
+		if _, err := FingerprintFiles(context.Background(), req.DiskFiles, DuplicateSizes(req.DiskFiles), DefaultFingerprintCache()); err != nil {
+			log.Printf("Failed to save fingerprints: %v", err)
+		}
+	}
+	var rules *RuleSet
+	if req.RulesProfile != "" {
+		var err error
+		if rules, err = LoadRules("", req.RulesProfile); err != nil {
+			return MatchResponse{}, err
+		}
+	}
+	result := FindMatchesWithOptions(req.TorrentFiles, req.DiskFiles, MatchOptions{
+		RequireSameExtension: req.RequireSameExtension,
+		SizeTolerance:        req.SizeTolerance,
+		PartialFiles:         req.PartialFiles,
+		Rules:                rules,
+	})
 
 	matches := make([]MatchInfo, len(result.Matches))
 	for i, m := range result.Matches {
 		matches[i] = MatchInfo{
-			TorrentFile: m.TorrentFile,
-			DiskFiles:   m.DiskFiles,
-			Selected:    m.Selected,
-			AutoMatched: m.AutoMatched,
+			TorrentFile:  m.TorrentFile,
+			DiskFiles:    m.DiskFiles,
+			Selected:     m.Selected,
+			AutoMatched:  m.AutoMatched,
+			Score:        m.Score,
+			SizeMismatch: m.SizeMismatch,
+			Partial:      m.Partial,
+			Rule:         m.Rule,
+			Exact:        m.Exact,
 		}
 	}
 
 	return MatchResponse{
 		Matches:      matches,
 		Unmatched:    result.Unmatched,
+		Skipped:      result.Skipped,
 		TotalFiles:   result.TotalFiles,
 		MatchedCount: result.MatchedCount,
-	}
+		RuleErrors:   result.RuleErrors,
+	}, nil
 }
 
 // RenameRequest represents a rename operation request
+// When TorrentFiles lists every file of the torrent, renames that move a whole
+// folder are collapsed into a single folder rename
 type RenameRequest struct {
-	Matches    []MatchInfo `json:"matches"`
-	SearchPath string      `json:"searchPath"`
+	Matches      []MatchInfo       `json:"matches"`
+	SearchPath   string            `json:"searchPath"`
+	TorrentFiles []TorrentFileInfo `json:"torrentFiles"`
 }
 
 // RenameOp represents a single rename operation
@@ -96,6 +162,9 @@ type RenameOp struct {
 	NewPath     string          `json:"newPath"`
 	TorrentFile TorrentFileInfo `json:"torrentFile"`
 	DiskFile    DiskFile        `json:"diskFile"`
+	IsFolder    bool            `json:"isFolder"`
+	FileCount   int             `json:"fileCount"`
+	Partial     bool            `json:"partial,omitempty"`
 }
 
 // GenRenames generates rename operations based on matches
@@ -104,14 +173,22 @@ func (s *MatcherService) GenRenames(req RenameRequest) []RenameOp {
 	matches := make([]Match, len(req.Matches))
 	for i, m := range req.Matches {
 		matches[i] = Match{
-			TorrentFile: m.TorrentFile,
-			DiskFiles:   m.DiskFiles,
-			Selected:    m.Selected,
-			AutoMatched: m.AutoMatched,
+			TorrentFile:  m.TorrentFile,
+			DiskFiles:    m.DiskFiles,
+			Selected:     m.Selected,
+			AutoMatched:  m.AutoMatched,
+			Score:        m.Score,
+			SizeMismatch: m.SizeMismatch,
+			Partial:      m.Partial,
+			Rule:         m.Rule,
+			Exact:        m.Exact,
 		}
 	}
 
 	renames := GenerateRenames(matches, req.SearchPath)
+	if len(req.TorrentFiles) > 0 {
+		renames = CollapseFolderRenames(renames, req.TorrentFiles)
+	}
 
 	result := make([]RenameOp, len(renames))
 	for i, r := range renames {
@@ -121,6 +198,75 @@ func (s *MatcherService) GenRenames(req RenameRequest) []RenameOp {
 	return result
 }
 
+// CountPendingRenames returns how many selected files would be renamed, i.e.
+// whose path under the search path names another file than the torrent's on
+// this platform. Folder renames are not collapsed, so each file counts.
+func (s *MatcherService) CountPendingRenames(matches []MatchInfo, searchPath string) int {
+	converted := make([]Match, len(matches))
+	for i, m := range matches {
+		converted[i] = Match(m)
+	}
+	return len(GenerateRenames(converted, searchPath))
+}
+
+// RevertRequest represents a request to undo renames that failed verification
+type RevertRequest struct {
+	Renames []RenameOp    `json:"renames"`
+	Report  RecheckReport `json:"report"`
+}
+
+// GenReverts generates the rename operations that undo renames of files
+// which did not verify after a recheck
+func (s *MatcherService) GenReverts(req RevertRequest) []RenameOp {
+	renames := make([]RenameOperation, len(req.Renames))
+	for i, r := range req.Renames {
+		renames[i] = RenameOperation(r)
+	}
+
+	reverts := RevertRenames(renames, req.Report)
+
+	result := make([]RenameOp, len(reverts))
+	for i, r := range reverts {
+		result[i] = RenameOp(r)
+	}
+
+	return result
+}
+
+// ClassifyOutcome summarises the current selection as a match outcome
+// (matched, partial, needs-review or unmatched)
+func (s *MatcherService) ClassifyOutcome(matches []MatchInfo, unmatched []TorrentFileInfo) string {
+	converted := make([]Match, len(matches))
+	for i, m := range matches {
+		converted[i] = Match(m)
+	}
+	return ClassifyOutcome(converted, len(unmatched))
+}
+
+// TorrentMetaInfo represents a parsed .torrent file for the frontend
+type TorrentMetaInfo struct {
+	Hash  string            `json:"hash"`
+	Name  string            `json:"name"`
+	Size  int64             `json:"size"`
+	Files []TorrentFileInfo `json:"files"`
+}
+
+// ReadTorrent parses a .torrent file and returns its files as qBittorrent
+// will name them
+func (s *MatcherService) ReadTorrent(path string) (TorrentMetaInfo, error) {
+	meta, err := ParseTorrentFile(path)
+	if err != nil {
+		return TorrentMetaInfo{}, err
+	}
+
+	return TorrentMetaInfo{
+		Hash:  meta.InfoHash,
+		Name:  meta.Name,
+		Size:  meta.TotalSize,
+		Files: meta.TorrentFiles(),
+	}, nil
+}
+
 // DirExists checks if a directory exists
 func (s *MatcherService) DirExists(path string) bool {
 	info, err := os.Stat(path)
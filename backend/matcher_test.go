@@ -1,6 +1,7 @@
 package backend
 
 import (
+	"slices"
 	"testing"
 )
 
@@ -142,6 +143,28 @@ func TestGenerateRenames_NoChange(t *testing.T) {
 	}
 }
 
+func TestGenerateRenames_PlatformNames(t *testing.T) {
+	matches := []Match{
+		{
+			TorrentFile: TorrentFileInfo{Index: 0, Name: cafeNFC + "/Video.mkv", Size: 1000},
+			Selected:    &DiskFile{Path: "/Downloads/" + cafeNFD + "/video.mkv", Name: "video.mkv", Size: 1000},
+		},
+	}
+
+	// macOS file systems ignore case and Unicode form
+	setPathPlatform(t, true, true)
+	if renames := GenerateRenames(matches, "/downloads"); len(renames) != 0 {
+		t.Errorf("Expected 0 renames on a case and Unicode insensitive platform, got %+v", renames)
+	}
+
+	// Elsewhere, the decomposed name on disk is another file
+	setPathPlatform(t, false, false)
+	renames := GenerateRenames(matches, "/Downloads")
+	if len(renames) != 1 || renames[0].NewPath != cafeNFD+"/video.mkv" {
+		t.Errorf("Expected a rename to the name on disk, got %+v", renames)
+	}
+}
+
 func TestGenerateRenames_WithSubdirectory(t *testing.T) {
 	matches := []Match{
 		{
@@ -175,6 +198,112 @@ func TestGenerateRenames_DiskFileInSubdir(t *testing.T) {
 	}
 }
 
+func TestCollapseFolderRenames_WholeFolder(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "Show.S01.1080p/E01.mkv", Size: 1000},
+		{Index: 1, Name: "Show.S01.1080p/E02.mkv", Size: 2000},
+		{Index: 2, Name: "Show.S01.1080p/Subs/E01.srt", Size: 10},
+	}
+	matches := []Match{
+		{TorrentFile: torrentFiles[0], Selected: &DiskFile{Path: "/downloads/Show/Season 1/E01.mkv", Size: 1000}},
+		{TorrentFile: torrentFiles[1], Selected: &DiskFile{Path: "/downloads/Show/Season 1/E02.mkv", Size: 2000}},
+		{TorrentFile: torrentFiles[2], Selected: &DiskFile{Path: "/downloads/Show/Season 1/Subs/E01.srt", Size: 10}},
+	}
+
+	renames := CollapseFolderRenames(GenerateRenames(matches, "/downloads"), torrentFiles)
+
+	if len(renames) != 1 {
+		t.Fatalf("Expected 1 folder rename, got %d: %+v", len(renames), renames)
+	}
+	if !renames[0].IsFolder {
+		t.Error("Expected a folder rename")
+	}
+	if renames[0].OldPath != "Show.S01.1080p" || renames[0].NewPath != "Show/Season 1" {
+		t.Errorf("Unexpected folder rename %s -> %s", renames[0].OldPath, renames[0].NewPath)
+	}
+	if renames[0].FileCount != 3 {
+		t.Errorf("Expected FileCount=3, got %d", renames[0].FileCount)
+	}
+}
+
+func TestCollapseFolderRenames_FallsBackToFiles(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "Album/01.flac", Size: 1000},
+		{Index: 1, Name: "Album/02.flac", Size: 2000},
+		{Index: 2, Name: "Album/cover.jpg", Size: 10},
+	}
+	// cover.jpg is unmatched, so renaming the folder would move it too
+	matches := []Match{
+		{TorrentFile: torrentFiles[0], Selected: &DiskFile{Path: "/music/Artist - Album/01.flac", Size: 1000}},
+		{TorrentFile: torrentFiles[1], Selected: &DiskFile{Path: "/music/Artist - Album/02.flac", Size: 2000}},
+	}
+
+	renames := CollapseFolderRenames(GenerateRenames(matches, "/music"), torrentFiles)
+
+	if len(renames) != 2 {
+		t.Fatalf("Expected 2 file renames, got %d", len(renames))
+	}
+	for _, r := range renames {
+		if r.IsFolder {
+			t.Errorf("Unexpected folder rename %s -> %s", r.OldPath, r.NewPath)
+		}
+	}
+}
+
+func TestCollapseFolderRenames_MixedTargets(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "Pack/A/1.mkv", Size: 1},
+		{Index: 1, Name: "Pack/A/2.mkv", Size: 2},
+		{Index: 2, Name: "Pack/B/3.mkv", Size: 3},
+	}
+	// Pack/A moves as a unit, Pack/B's only file is renamed as well
+	matches := []Match{
+		{TorrentFile: torrentFiles[0], Selected: &DiskFile{Path: "/d/X/1.mkv", Size: 1}},
+		{TorrentFile: torrentFiles[1], Selected: &DiskFile{Path: "/d/X/2.mkv", Size: 2}},
+		{TorrentFile: torrentFiles[2], Selected: &DiskFile{Path: "/d/Y/other.mkv", Size: 3}},
+	}
+
+	renames := CollapseFolderRenames(GenerateRenames(matches, "/d"), torrentFiles)
+
+	if len(renames) != 2 {
+		t.Fatalf("Expected 2 renames, got %d: %+v", len(renames), renames)
+	}
+	if !renames[0].IsFolder || renames[0].OldPath != "Pack/A" || renames[0].NewPath != "X" {
+		t.Errorf("Expected folder rename Pack/A -> X, got %+v", renames[0])
+	}
+	if renames[1].IsFolder || renames[1].NewPath != "Y/other.mkv" {
+		t.Errorf("Expected file rename to Y/other.mkv, got %+v", renames[1])
+	}
+}
+
+func TestCollapseFolderRenames_TargetInUse(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "Pack/A/1.mkv", Size: 1},
+		{Index: 1, Name: "Pack/A/2.mkv", Size: 2},
+		{Index: 2, Name: "Pack/B/3.mkv", Size: 3},
+		{Index: 3, Name: "x/4.mkv", Size: 4},
+	}
+	// Pack/B/3.mkv is renamed into X, so Pack/A can't be renamed to X
+	matches := []Match{
+		{TorrentFile: torrentFiles[0], Selected: &DiskFile{Path: "/d/X/1.mkv", Size: 1}},
+		{TorrentFile: torrentFiles[1], Selected: &DiskFile{Path: "/d/X/2.mkv", Size: 2}},
+		{TorrentFile: torrentFiles[2], Selected: &DiskFile{Path: "/d/X/3.mkv", Size: 3}},
+	}
+
+	renames := CollapseFolderRenames(GenerateRenames(matches, "/d"), torrentFiles[:3])
+	if len(renames) != 3 || slices.ContainsFunc(renames, func(r RenameOperation) bool { return r.IsFolder }) {
+		t.Errorf("Expected 3 file renames, got %+v", renames)
+	}
+
+	// A torrent folder differing only in case is the same folder on
+	// case-insensitive platforms
+	setPathPlatform(t, true, false)
+	renames = CollapseFolderRenames(GenerateRenames(matches[:2], "/d"), torrentFiles)
+	if len(renames) != 2 || renames[0].IsFolder {
+		t.Errorf("Expected 2 file renames into a folder in use, got %+v", renames)
+	}
+}
+
 func TestGroupFilesBySize(t *testing.T) {
 	files := []DiskFile{
 		{Path: "/a.txt", Name: "a.txt", Size: 100},
@@ -236,7 +365,10 @@ func TestMatcherService_FindMatches(t *testing.T) {
 		RequireSameExtension: false,
 	}
 
-	result := service.FindMatches(req)
+	result, err := service.FindMatches(req)
+	if err != nil {
+		t.Fatal(err)
+	}
 
 	if result.TotalFiles != 2 {
 		t.Errorf("Expected TotalFiles=2, got %d", result.TotalFiles)
@@ -251,3 +383,194 @@ func TestMatcherService_FindMatches(t *testing.T) {
 		t.Errorf("Expected 1 unmatched, got %d", len(result.Unmatched))
 	}
 }
+
+func TestMatcherService_FindMatchesMissingRules(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	_, err := (&MatcherService{}).FindMatches(MatchRequest{RulesProfile: "missing"})
+	if err == nil {
+		t.Error("Expected error for a missing rules profile")
+	}
+}
+
+func TestFindMatches_FuzzyNameMatch(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "Show.Name.S01E02.1080p.WEB-DL.x264-GROUP.mkv", Size: 1000},
+	}
+	diskFiles := []DiskFile{
+		{Path: "/tv/Show Name S01E01.mkv", Name: "Show Name S01E01.mkv", Size: 1000},
+		{Path: "/tv/Other Show S03E07.mkv", Name: "Other Show S03E07.mkv", Size: 1000},
+		{Path: "/tv/show name s01e02.mkv", Name: "show name s01e02.mkv", Size: 1000},
+	}
+
+	result := FindMatches(torrentFiles, diskFiles, true)
+
+	match := result.Matches[0]
+	if match.DiskFiles[0].Name != "show name s01e02.mkv" {
+		t.Errorf("Expected most similar candidate first, got '%s'", match.DiskFiles[0].Name)
+	}
+	if match.Selected == nil || match.Selected.Name != "show name s01e02.mkv" {
+		t.Fatalf("Expected fuzzy auto-selection, got %+v", match.Selected)
+	}
+	if !match.AutoMatched {
+		t.Error("Expected AutoMatched to be true")
+	}
+}
+
+func TestFindMatches_FuzzyAmbiguous(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "Movie.2020.mkv", Size: 1000},
+	}
+	diskFiles := []DiskFile{
+		{Path: "/a/Movie 2020 Remux.mkv", Name: "Movie 2020 Remux.mkv", Size: 1000},
+		{Path: "/b/Movie 2020 Proper.mkv", Name: "Movie 2020 Proper.mkv", Size: 1000},
+	}
+
+	result := FindMatches(torrentFiles, diskFiles, true)
+	if result.Matches[0].Selected != nil {
+		t.Errorf("Expected no selection between equally similar names, got '%s'", result.Matches[0].Selected.Name)
+	}
+}
+
+func TestFindMatches_RankingDoesNotReorderInput(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "b.mkv", Size: 1000},
+		{Index: 1, Name: "dir/a.mkv", Size: 1000},
+	}
+	diskFiles := []DiskFile{
+		{Path: "/x/a.mkv", Name: "a.mkv", Size: 1000},
+		{Path: "/x/b.mkv", Name: "b.mkv", Size: 1000},
+	}
+
+	result := FindMatches(torrentFiles, diskFiles, true)
+	if diskFiles[0].Name != "a.mkv" {
+		t.Error("Expected FindMatches to leave the disk files untouched")
+	}
+	if result.Matches[0].Selected == nil || result.Matches[0].Selected.Name != "b.mkv" {
+		t.Errorf("Expected b.mkv for the first torrent file, got %+v", result.Matches[0].Selected)
+	}
+}
+
+func TestFindMatchesWithOptions_SizeTolerance(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "01 - Track.flac", Size: 30_000_000},
+		{Index: 1, Name: "02 - Track.flac", Size: 25_000_000},
+	}
+	diskFiles := []DiskFile{
+		{Path: "/music/01 - Track.flac", Name: "01 - Track.flac", Size: 30_004_096},
+		{Path: "/music/02 - Track.flac", Name: "02 - Track.flac", Size: 25_000_000},
+		{Path: "/music/cover.flac", Name: "cover.flac", Size: 30_100_000},
+	}
+
+	// Exact sizes only
+	result := FindMatches(torrentFiles, diskFiles, true)
+	if len(result.Unmatched) != 1 {
+		t.Fatalf("Expected 1 unmatched without tolerance, got %d", len(result.Unmatched))
+	}
+
+	result = FindMatchesWithOptions(torrentFiles, diskFiles, MatchOptions{RequireSameExtension: true, SizeTolerance: 8192})
+	if len(result.Unmatched) != 0 {
+		t.Fatalf("Expected no unmatched files with tolerance, got %d", len(result.Unmatched))
+	}
+
+	fuzzy := result.Matches[0]
+	if !fuzzy.SizeMismatch {
+		t.Error("Expected the tag-edited file to be marked as size mismatch")
+	}
+	if len(fuzzy.DiskFiles) != 1 || fuzzy.DiskFiles[0].Name != "01 - Track.flac" {
+		t.Errorf("Expected only the file within tolerance, got %v", fuzzy.DiskFiles)
+	}
+	if fuzzy.Selected != nil {
+		t.Error("Expected size mismatch candidates not to be auto-selected")
+	}
+
+	exact := result.Matches[1]
+	if exact.SizeMismatch || exact.Selected == nil {
+		t.Errorf("Expected exact size match to be unaffected, got %+v", exact)
+	}
+}
+
+func TestFindMatchesWithOptions_PartialFiles(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "Movie.2019.1080p.mkv", Size: 4_000_000_000},
+		{Index: 1, Name: "Other.Film.2020.mkv", Size: 3_000_000_000},
+	}
+	diskFiles := []DiskFile{
+		{Path: "/incomplete/Movie.2019.1080p.mkv", Name: "Movie.2019.1080p.mkv", Size: 1_500_000_000},
+		{Path: "/incomplete/Movie.2019.1080p.srt", Name: "Movie.2019.1080p.srt", Size: 80_000},
+		{Path: "/incomplete/Unrelated.mkv", Name: "Unrelated.mkv", Size: 900_000_000},
+		{Path: "/incomplete/Other.Film.2020.mkv", Name: "Other.Film.2020.mkv", Size: 3_500_000_000},
+	}
+
+	result := FindMatches(torrentFiles, diskFiles, true)
+	if len(result.Unmatched) != 2 {
+		t.Fatalf("Expected nothing to match without partial files, got %+v", result.Matches)
+	}
+
+	result = FindMatchesWithOptions(torrentFiles, diskFiles, MatchOptions{PartialFiles: true})
+	if len(result.Matches) != 1 || len(result.Unmatched) != 1 {
+		t.Fatalf("Expected 1 partial match and 1 unmatched, got %+v", result)
+	}
+
+	// The .srt has another extension, Unrelated.mkv another name and the
+	// larger file is no partial download
+	partial := result.Matches[0]
+	if !partial.Partial || partial.SizeMismatch {
+		t.Errorf("Expected a partial match, got %+v", partial)
+	}
+	if len(partial.DiskFiles) != 1 || partial.Selected == nil || partial.Selected.Name != "Movie.2019.1080p.mkv" {
+		t.Errorf("Expected the aborted download to be selected, got %+v", partial)
+	}
+
+	renames := GenerateRenames(result.Matches, "/incomplete/..")
+	if len(renames) != 1 || !renames[0].Partial {
+		t.Errorf("Expected the rename to be marked partial, got %+v", renames)
+	}
+}
+
+func TestFindMatches_SkipsPadAndEmptyFiles(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "Pack/a.mkv", Size: 1000},
+		{Index: 1, Name: "Pack/.pad/24", Size: 24},
+		{Index: 2, Name: "Pack/b.mkv", Size: 2000},
+		{Index: 3, Name: "Pack/padding", Size: 40, Pad: true},
+		{Index: 4, Name: "Pack/empty.nfo", Size: 0},
+		{Index: 5, Name: "Pack/_____padding_file_0_", Size: 8},
+	}
+	diskFiles := []DiskFile{
+		{Path: "/data/a.mkv", Name: "a.mkv", Size: 1000},
+		{Path: "/data/pad", Name: "pad", Size: 24},
+	}
+
+	result := FindMatches(torrentFiles, diskFiles, false)
+
+	if len(result.Skipped) != 4 {
+		t.Errorf("Expected 4 skipped files, got %+v", result.Skipped)
+	}
+	if result.TotalFiles != 2 || result.MatchedCount != 1 {
+		t.Errorf("Expected 1 of 2 files matched, got %d of %d", result.MatchedCount, result.TotalFiles)
+	}
+	if len(result.Unmatched) != 1 || result.Unmatched[0].Index != 2 {
+		t.Errorf("Expected only b.mkv unmatched, got %+v", result.Unmatched)
+	}
+}
+
+func TestCollapseFolderRenames_IgnoresPadFiles(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "Pack/E01.mkv", Size: 1000},
+		{Index: 1, Name: "Pack/.pad/24", Size: 24},
+		{Index: 2, Name: "Pack/E02.mkv", Size: 2000},
+		{Index: 3, Name: "Pack/empty.txt", Size: 0},
+	}
+	matches := []Match{
+		{TorrentFile: torrentFiles[0], Selected: &DiskFile{Path: "/downloads/Show/E01.mkv", Size: 1000}},
+		{TorrentFile: torrentFiles[2], Selected: &DiskFile{Path: "/downloads/Show/E02.mkv", Size: 2000}},
+	}
+
+	renames := CollapseFolderRenames(GenerateRenames(matches, "/downloads"), torrentFiles)
+
+	if len(renames) != 1 || !renames[0].IsFolder || renames[0].NewPath != "Show" {
+		t.Errorf("Expected a single folder rename to Show, got %+v", renames)
+	}
+}
@@ -0,0 +1,130 @@
+package backend
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// MediaInfo is what can be told about a video from its file name, e.g.
+// "Show.Name.S01E03.1080p.mkv" or "Show Name (2019) - 1x03 - Title.mkv"
+type MediaInfo struct {
+	Title      string `json:"title"`
+	Season     int    `json:"season"`  // 0 when unknown
+	Episode    int    `json:"episode"` // 0 when unknown
+	Year       int    `json:"year"`    // 0 when unknown
+	Resolution string `json:"resolution"`
+}
+
+// HasEpisode reports whether a season and episode were found
+func (m MediaInfo) HasEpisode() bool {
+	return m.Season > 0 && m.Episode > 0
+}
+
+var (
+	episodePattern      = regexp.MustCompile(`(?i)\bs(\d{1,2})[ .-]?e(\d{1,3})`)           // S01E03, s1e3, S01.E03
+	crossEpisodePattern = regexp.MustCompile(`\b(\d{1,2})x(\d{2,3})\b`)                    // 1x03
+	seasonDirPattern    = regexp.MustCompile(`(?i)\b(?:season|series|s)[ .-]?(\d{1,2})\b`) // Season 1, S01
+	episodeOnlyPattern  = regexp.MustCompile(`(?i)\b(?:e|ep|episode)[ .-]?(\d{1,3})\b`)    // E03, Episode 3
+	yearPattern         = regexp.MustCompile(`\b(19\d\d|20\d\d)\b`)
+	resolutionPattern   = regexp.MustCompile(`(?i)\b(2160|1080|720|576|480)[pi]\b`)
+	resolution4KPattern = regexp.MustCompile(`(?i)\b(4k|uhd)\b`)
+)
+
+// ParseMediaInfo parses a file path. Season and episode come from the file
+// name, or from a "Season 1" parent folder when the name only has an episode.
+func ParseMediaInfo(filePath string) MediaInfo {
+	filePath = filepath.ToSlash(filePath)
+	base := path.Base(filePath)
+	name := normalizeSeparators(strings.TrimSuffix(base, path.Ext(base)))
+
+	var info MediaInfo
+	titleEnd := len(name)
+	cut := func(index int) {
+		if index >= 0 && index < titleEnd {
+			titleEnd = index
+		}
+	}
+
+	if m := episodePattern.FindStringSubmatchIndex(name); m != nil {
+		info.Season, _ = strconv.Atoi(name[m[2]:m[3]])
+		info.Episode, _ = strconv.Atoi(name[m[4]:m[5]])
+		cut(m[0])
+	} else if m := crossEpisodePattern.FindStringSubmatchIndex(name); m != nil {
+		info.Season, _ = strconv.Atoi(name[m[2]:m[3]])
+		info.Episode, _ = strconv.Atoi(name[m[4]:m[5]])
+		cut(m[0])
+	} else if m := episodeOnlyPattern.FindStringSubmatchIndex(name); m != nil {
+		dir := normalizeSeparators(path.Base(path.Dir(filePath)))
+		if s := seasonDirPattern.FindStringSubmatch(dir); s != nil {
+			info.Season, _ = strconv.Atoi(s[1])
+			info.Episode, _ = strconv.Atoi(name[m[2]:m[3]])
+			cut(m[0])
+		}
+	}
+
+	// The last year-like number after the start is the release year, so
+	// titles such as "2001 A Space Odyssey (1968)" keep their leading number
+	if years := yearPattern.FindAllStringSubmatchIndex(name, -1); len(years) > 0 {
+		last := years[len(years)-1]
+		if last[0] > 0 {
+			info.Year, _ = strconv.Atoi(name[last[2]:last[3]])
+			cut(last[0])
+		}
+	}
+
+	if m := resolutionPattern.FindStringSubmatchIndex(name); m != nil {
+		info.Resolution = name[m[2]:m[3]] + "p"
+		cut(m[0])
+	} else if m := resolution4KPattern.FindStringIndex(name); m != nil {
+		info.Resolution = "2160p"
+		cut(m[0])
+	}
+
+	info.Title = strings.Join(Tokenize(name[:titleEnd]), " ")
+	return info
+}
+
+// normalizeSeparators turns underscores into spaces, as regexp word
+// boundaries treat them as part of a word
+func normalizeSeparators(name string) string {
+	return strings.ReplaceAll(name, "_", " ")
+}
+
+// MetadataAgreement scores how well the parsed metadata of two files agrees.
+// Matching episodes are a strong signal, conflicting episodes or years are a
+// strong signal against; unknown fields count for nothing.
+func MetadataAgreement(a, b MediaInfo) float64 {
+	var score float64
+
+	if a.HasEpisode() && b.HasEpisode() {
+		if a.Season == b.Season && a.Episode == b.Episode {
+			score += 0.4
+		} else {
+			score -= 0.5
+		}
+	}
+
+	if a.Year > 0 && b.Year > 0 {
+		if a.Year == b.Year {
+			score += 0.1
+		} else {
+			score -= 0.3
+		}
+	}
+
+	if a.Resolution != "" && b.Resolution != "" && a.Resolution != b.Resolution {
+		score -= 0.1
+	}
+
+	return score
+}
+
+// MatchScore combines name similarity with metadata agreement, from 0 to 1
+func MatchScore(torrentPath string, diskPath string) float64 {
+	score := NameSimilarity(torrentPath, diskPath) +
+		MetadataAgreement(ParseMediaInfo(torrentPath), ParseMediaInfo(diskPath))
+	return min(max(score, 0), 1)
+}
@@ -0,0 +1,69 @@
+package backend
+
+import (
+	"testing"
+)
+
+func TestParseMediaInfo(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected MediaInfo
+	}{
+		{"Show.Name.S01E03.1080p.WEB-DL.x264-GRP.mkv", MediaInfo{Title: "show name", Season: 1, Episode: 3, Resolution: "1080p"}},
+		{"/tv/Show Name (2019)/Season 01/Show Name (2019) - 1x03 - Pilot.mkv", MediaInfo{Title: "show name", Season: 1, Episode: 3, Year: 2019}},
+		{"/tv/Show/Season 2/Episode 07.mkv", MediaInfo{Title: "", Season: 2, Episode: 7}},
+		{"show_name_s2e10_720p.mp4", MediaInfo{Title: "show name", Season: 2, Episode: 10, Resolution: "720p"}},
+		{"2001.A.Space.Odyssey.1968.2160p.mkv", MediaInfo{Title: "2001 a space odyssey", Year: 1968, Resolution: "2160p"}},
+		{"Movie Title (2020) 4K.mkv", MediaInfo{Title: "movie title", Year: 2020, Resolution: "2160p"}},
+		{"Sample 1920x1080.mkv", MediaInfo{Title: "sample 1920x1080"}},
+	}
+
+	for _, tt := range tests {
+		if result := ParseMediaInfo(tt.path); result != tt.expected {
+			t.Errorf("ParseMediaInfo(%q) = %+v, want %+v", tt.path, result, tt.expected)
+		}
+	}
+}
+
+func TestMetadataAgreement(t *testing.T) {
+	s01e03 := MediaInfo{Season: 1, Episode: 3}
+	s01e04 := MediaInfo{Season: 1, Episode: 4}
+
+	if MetadataAgreement(s01e03, s01e03) <= 0 {
+		t.Error("Expected matching episodes to agree")
+	}
+	if MetadataAgreement(s01e03, s01e04) >= 0 {
+		t.Error("Expected different episodes to disagree")
+	}
+	if MetadataAgreement(MediaInfo{Year: 1999}, MediaInfo{Year: 2003}) >= 0 {
+		t.Error("Expected different years to disagree")
+	}
+	if MetadataAgreement(s01e03, MediaInfo{}) != 0 {
+		t.Error("Expected unknown metadata to count for nothing")
+	}
+}
+
+func TestFindMatches_EpisodeDisambiguation(t *testing.T) {
+	// Sonarr renamed a season pack; episodes 3 and 4 happen to share a size
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "Show.S01.1080p/Show.S01E03.1080p.WEB.mkv", Size: 1000},
+		{Index: 1, Name: "Show.S01.1080p/Show.S01E04.1080p.WEB.mkv", Size: 1000},
+	}
+	diskFiles := []DiskFile{
+		{Path: "/tv/Show/Season 01/Show - 1x04 - The Fourth.mkv", Name: "Show - 1x04 - The Fourth.mkv", Size: 1000},
+		{Path: "/tv/Show/Season 01/Show - 1x03 - The Third.mkv", Name: "Show - 1x03 - The Third.mkv", Size: 1000},
+	}
+
+	result := FindMatches(torrentFiles, diskFiles, true)
+
+	for i, expected := range []string{"Show - 1x03 - The Third.mkv", "Show - 1x04 - The Fourth.mkv"} {
+		selected := result.Matches[i].Selected
+		if selected == nil {
+			t.Errorf("Expected %s to be auto-selected", torrentFiles[i].Name)
+			continue
+		}
+		if selected.Name != expected {
+			t.Errorf("Expected %s for %s, got %s", expected, torrentFiles[i].Name, selected.Name)
+		}
+	}
+}
@@ -0,0 +1,267 @@
+package backend
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Metrics exposed in the Prometheus text format on /metrics
+var (
+	metricScans             = newCounterVec("qbt_scans_total", "Directory scans by result.", "result")
+	metricScanFiles         = newCounterVec("qbt_scan_files_indexed_total", "Files indexed by directory scans.")
+	metricScanDuration      = newHistogramVec("qbt_scan_duration_seconds", "Duration of directory scans.", defaultBuckets)
+	metricMatchTorrents     = newCounterVec("qbt_match_torrents_total", "Torrents matched by outcome.", "outcome")
+	metricMatchFiles        = newCounterVec("qbt_match_files_total", "Torrent files matched by result (matched, ambiguous, unmatched).", "result")
+	metricRenames           = newCounterVec("qbt_renames_total", "File renames by result (applied, failed).", "result")
+	metricLinks             = newCounterVec("qbt_links_total", "Files linked into torrent layouts by result (ok, failed).", "result")
+	metricRechecks          = newCounterVec("qbt_rechecks_total", "Rechecks triggered by result.", "result")
+	metricRecheckFiles      = newCounterVec("qbt_recheck_files_total", "Files verified by rechecks by result (complete, incomplete).", "result")
+	metricRecheckDuration   = newHistogramVec("qbt_recheck_duration_seconds", "Time spent waiting for rechecks.", recheckBuckets)
+	metricAPIRequestLatency = newHistogramVec("qbt_api_request_duration_seconds", "Latency of qBittorrent Web API requests.", defaultBuckets, "endpoint")
+	metricAPIErrors         = newCounterVec("qbt_api_errors_total", "Failed qBittorrent Web API requests.", "endpoint")
+)
+
+var (
+	defaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+	recheckBuckets = []float64{1, 5, 15, 30, 60, 300, 900, 1800}
+)
+
+// metricsRegistry holds every metric family, keyed by name
+var metricsRegistry = struct {
+	mu       sync.Mutex
+	families map[string]metricFamily
+}{families: make(map[string]metricFamily)}
+
+type metricFamily interface {
+	write(w io.Writer)
+}
+
+func register(name string, f metricFamily) {
+	metricsRegistry.mu.Lock()
+	defer metricsRegistry.mu.Unlock()
+	metricsRegistry.families[name] = f
+}
+
+// MetricsHandler serves all metrics in the Prometheus text exposition format
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WriteMetrics(w)
+	})
+}
+
+// WriteMetrics writes all metrics in the Prometheus text exposition format
+func WriteMetrics(w io.Writer) {
+	metricsRegistry.mu.Lock()
+	names := make([]string, 0, len(metricsRegistry.families))
+	for name := range metricsRegistry.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	families := make([]metricFamily, len(names))
+	for i, name := range names {
+		families[i] = metricsRegistry.families[name]
+	}
+	metricsRegistry.mu.Unlock()
+
+	for _, f := range families {
+		f.write(w)
+	}
+}
+
+// counterVec is a counter partitioned by label values
+type counterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]float64 // keyed by joined label values
+}
+
+func newCounterVec(name, help string, labels ...string) *counterVec {
+	c := &counterVec{name: name, help: help, labels: labels, values: make(map[string]float64)}
+	register(name, c)
+	return c
+}
+
+func (c *counterVec) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+func (c *counterVec) Add(v float64, labelValues ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[labelKey(labelValues)] += v
+}
+
+func (c *counterVec) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	if len(c.labels) == 0 && len(c.values) == 0 {
+		fmt.Fprintf(w, "%s 0\n", c.name)
+		return
+	}
+	for _, key := range sortedKeys(c.values) {
+		fmt.Fprintf(w, "%s%s %s\n", c.name, formatLabels(c.labels, key, ""), formatValue(c.values[key]))
+	}
+}
+
+// histogramVec is a histogram partitioned by label values
+type histogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64
+
+	mu     sync.Mutex
+	values map[string]*histogram
+}
+
+type histogram struct {
+	counts []uint64 // per bucket, not cumulative
+	count  uint64
+	sum    float64
+}
+
+func newHistogramVec(name, help string, buckets []float64, labels ...string) *histogramVec {
+	h := &histogramVec{name: name, help: help, labels: labels, buckets: buckets, values: make(map[string]*histogram)}
+	register(name, h)
+	return h
+}
+
+func (h *histogramVec) Observe(v float64, labelValues ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	key := labelKey(labelValues)
+	hist := h.values[key]
+	if hist == nil {
+		hist = &histogram{counts: make([]uint64, len(h.buckets))}
+		h.values[key] = hist
+	}
+	if i, _ := slices.BinarySearch(h.buckets, v); i < len(h.buckets) {
+		hist.counts[i]++
+	}
+	hist.count++
+	hist.sum += v
+}
+
+// ObserveSince records the time elapsed since start in seconds
+func (h *histogramVec) ObserveSince(start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
+
+func (h *histogramVec) write(w io.Writer) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s histogram\n", h.name, h.help, h.name)
+	for _, key := range sortedKeys(h.values) {
+		hist := h.values[key]
+		var cumulative uint64
+		for i, bound := range h.buckets {
+			cumulative += hist.counts[i]
+			fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, key, formatValue(bound)), cumulative)
+		}
+		fmt.Fprintf(w, "%s_bucket%s %d\n", h.name, formatLabels(h.labels, key, "+Inf"), hist.count)
+		fmt.Fprintf(w, "%s_sum%s %s\n", h.name, formatLabels(h.labels, key, ""), formatValue(hist.sum))
+		fmt.Fprintf(w, "%s_count%s %d\n", h.name, formatLabels(h.labels, key, ""), hist.count)
+	}
+}
+
+// Label values are joined with a byte that can't appear in valid UTF-8
+const labelSeparator = "\xff"
+
+func labelKey(values []string) string {
+	return strings.Join(values, labelSeparator)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatLabels renders {name="value",...}, adding le for histogram buckets
+func formatLabels(names []string, key string, le string) string {
+	var parts []string
+	if len(names) > 0 {
+		values := strings.Split(key, labelSeparator)
+		for i, name := range names {
+			value := ""
+			if i < len(values) {
+				value = values[i]
+			}
+			parts = append(parts, name+"="+strconv.Quote(value))
+		}
+	}
+	if le != "" {
+		parts = append(parts, `le="`+le+`"`)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	if math.IsInf(v, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// metricsTransport records latency and errors of qBittorrent API requests
+type metricsTransport struct {
+	base http.RoundTripper
+}
+
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	endpoint := apiEndpoint(req.URL.Path)
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	metricAPIRequestLatency.ObserveSince(start, endpoint)
+	if err != nil || resp.StatusCode >= http.StatusBadRequest {
+		metricAPIErrors.Inc(endpoint)
+	}
+	return resp, err
+}
+
+// apiEndpoint returns the part of a Web API path after /api/v2/, e.g.
+// "torrents/info", so reverse proxy prefixes don't split the metrics
+func apiEndpoint(path string) string {
+	if _, endpoint, ok := strings.Cut(path, "/api/v2/"); ok {
+		return endpoint
+	}
+	return "other"
+}
+
+// recordMatch counts a torrent's files by match result and its outcome
+func recordMatch(result MatchResult) {
+	var matched, ambiguous int
+	for _, m := range result.Matches {
+		if m.Selected != nil {
+			matched++
+		} else {
+			ambiguous++
+		}
+	}
+	metricMatchFiles.Add(float64(matched), "matched")
+	metricMatchFiles.Add(float64(ambiguous), "ambiguous")
+	metricMatchFiles.Add(float64(len(result.Unmatched)), "unmatched")
+	metricMatchTorrents.Inc(ClassifyOutcome(result.Matches, len(result.Unmatched)))
+}
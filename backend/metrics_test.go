@@ -0,0 +1,81 @@
+package backend
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCounterVec_Write(t *testing.T) {
+	c := &counterVec{name: "test_total", help: "Test.", labels: []string{"result"}, values: make(map[string]float64)}
+	c.Inc("ok")
+	c.Add(2, "ok")
+	c.Inc(`say "hi"`)
+
+	var sb strings.Builder
+	c.write(&sb)
+	expected := "# HELP test_total Test.\n# TYPE test_total counter\n" +
+		"test_total{result=\"ok\"} 3\n" +
+		"test_total{result=\"say \\\"hi\\\"\"} 1\n"
+	if sb.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", sb.String(), expected)
+	}
+}
+
+func TestHistogramVec_Write(t *testing.T) {
+	h := &histogramVec{name: "test_seconds", help: "Test.", buckets: []float64{1, 5}, values: make(map[string]*histogram)}
+	h.Observe(0.5)
+	h.Observe(1)
+	h.Observe(3)
+	h.Observe(10)
+
+	var sb strings.Builder
+	h.write(&sb)
+	expected := "# HELP test_seconds Test.\n# TYPE test_seconds histogram\n" +
+		"test_seconds_bucket{le=\"1\"} 2\n" +
+		"test_seconds_bucket{le=\"5\"} 3\n" +
+		"test_seconds_bucket{le=\"+Inf\"} 4\n" +
+		"test_seconds_sum 14.5\n" +
+		"test_seconds_count 4\n"
+	if sb.String() != expected {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", sb.String(), expected)
+	}
+}
+
+func TestAPIEndpoint(t *testing.T) {
+	tests := map[string]string{
+		"/api/v2/torrents/info":   "torrents/info",
+		"/qbit/api/v2/auth/login": "auth/login",
+		"/something/else":         "other",
+	}
+	for path, expected := range tests {
+		if result := apiEndpoint(path); result != expected {
+			t.Errorf("apiEndpoint(%q) = %q, want %q", path, result, expected)
+		}
+	}
+}
+
+func TestMetricsHandler(t *testing.T) {
+	FindMatches(
+		[]TorrentFileInfo{{Index: 0, Name: "a.mkv", Size: 100}, {Index: 1, Name: "b.mkv", Size: 200}},
+		[]DiskFile{{Path: "/data/a.mkv", Name: "a.mkv", Size: 100}},
+		true,
+	)
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := rec.Body.String()
+
+	for _, expected := range []string{
+		"# TYPE qbt_match_files_total counter",
+		`qbt_match_files_total{result="unmatched"}`,
+		`qbt_match_torrents_total{outcome="partial"}`,
+		"# TYPE qbt_scan_duration_seconds histogram",
+		"# TYPE qbt_api_request_duration_seconds histogram",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Expected metrics to contain %q", expected)
+		}
+	}
+}
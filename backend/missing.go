@@ -0,0 +1,163 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// maxMissingCandidates is how many candidates the missing report keeps per
+// file, best first
+const maxMissingCandidates = 3
+
+// MissingFile is a torrent file that is not where qBittorrent expects it, or
+// has the wrong size there
+type MissingFile struct {
+	Index      int        `json:"index"`
+	Name       string     `json:"name"`
+	Size       int64      `json:"size"`
+	Path       string     `json:"path"`     // where qBittorrent expects it
+	DiskSize   int64      `json:"diskSize"` // size found at Path, -1 when missing
+	Candidates []DiskFile `json:"candidates"`
+	AutoMatch  bool       `json:"autoMatch"` // the first candidate would be selected by match
+}
+
+// WrongSize reports whether a file exists at Path with another size
+func (f MissingFile) WrongSize() bool {
+	return f.DiskSize >= 0
+}
+
+// MissingTorrent lists the missing files of a torrent
+type MissingTorrent struct {
+	Torrent    TorrentInfo   `json:"torrent"`
+	Files      []MissingFile `json:"files"`
+	TotalFiles int           `json:"totalFiles"` // files checked
+}
+
+// MissingReport lists every torrent with missing or wrong-sized files
+type MissingReport struct {
+	Checked   int              `json:"checked"` // torrents checked
+	Torrents  []MissingTorrent `json:"torrents"`
+	FileCount int              `json:"fileCount"`
+}
+
+// CheckTorrentFiles finds the files of a torrent missing on disk. Files
+// qBittorrent has complete must be at the save path with their size; files
+// still downloading only have to exist, in the save path or download path
+// and possibly with the incomplete suffix. Unwanted, pad and empty files are
+// not checked.
+func CheckTorrentFiles(t TorrentInfo, files []TorrentFile) MissingTorrent {
+	result := MissingTorrent{Torrent: t, Files: []MissingFile{}}
+	for _, f := range files {
+		if f.Priority == 0 || !(TorrentFileInfo{Name: f.Name, Size: f.Size}).NeedsDiskFile() {
+			continue
+		}
+		result.TotalFiles++
+
+		expected := filepath.Join(t.SavePath, filepath.FromSlash(f.Name))
+		diskSize := int64(-1)
+		if info, err := os.Stat(expected); err == nil && info.Mode().IsRegular() {
+			diskSize = info.Size()
+		}
+		if diskSize == f.Size {
+			continue
+		}
+		if f.Progress < 1 && (diskSize >= 0 || incompleteExists(t, f.Name)) {
+			// Preallocated or partially downloaded
+			continue
+		}
+		result.Files = append(result.Files, MissingFile{
+			Index:      f.Index,
+			Name:       f.Name,
+			Size:       f.Size,
+			Path:       expected,
+			DiskSize:   diskSize,
+			Candidates: []DiskFile{},
+		})
+	}
+	return result
+}
+
+// incompleteExists reports whether a file still downloading is on disk
+// under one of the names qBittorrent gives incomplete files
+func incompleteExists(t TorrentInfo, name string) bool {
+	for _, dir := range []string{t.SavePath, t.DownloadPath} {
+		if dir == "" {
+			continue
+		}
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		for _, p := range []string{path, path + incompleteSuffix} {
+			if _, err := os.Stat(p); err == nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AddMissingCandidates ranks the disk files that could replace each missing
+// file, the way match would
+func AddMissingCandidates(report *MissingReport, diskFiles []DiskFile, opts MatchOptions) {
+	for i := range report.Torrents {
+		mt := &report.Torrents[i]
+		torrentFiles := make([]TorrentFileInfo, len(mt.Files))
+		for j, f := range mt.Files {
+			torrentFiles[j] = TorrentFileInfo{Index: f.Index, Name: f.Name, Size: f.Size}
+		}
+		result := FindMatchesWithOptions(torrentFiles, diskFiles, opts)
+
+		byIndex := make(map[int]Match, len(result.Matches))
+		for _, m := range result.Matches {
+			byIndex[m.TorrentFile.Index] = m
+		}
+		for j := range mt.Files {
+			f := &mt.Files[j]
+			m, ok := byIndex[f.Index]
+			if !ok {
+				continue
+			}
+			f.Candidates = m.DiskFiles[:min(len(m.DiskFiles), maxMissingCandidates)]
+			f.AutoMatch = m.Selected != nil && m.Selected.Path == m.DiskFiles[0].Path
+		}
+	}
+}
+
+// FindMissingFiles checks every torrent's files on disk and, when roots are
+// given, scans them once for candidates for the files that are missing.
+// Save paths must be paths on this machine.
+func (s *QBitService) FindMissingFiles(ctx context.Context, roots []string, opts MatchOptions) (MissingReport, error) {
+	torrents, err := s.GetTorrents()
+	if err != nil {
+		return MissingReport{}, err
+	}
+
+	report := MissingReport{Checked: len(torrents), Torrents: []MissingTorrent{}}
+	for _, t := range torrents {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+		files, err := s.GetTorrentFiles(t.Hash)
+		if err != nil {
+			return report, fmt.Errorf("failed to get files of %s: %w", t.Name, err)
+		}
+		if missing := CheckTorrentFiles(t, files); len(missing.Files) > 0 {
+			report.Torrents = append(report.Torrents, missing)
+			report.FileCount += len(missing.Files)
+		}
+	}
+
+	if len(roots) == 0 || report.FileCount == 0 {
+		return report, nil
+	}
+	var diskFiles []DiskFile
+	for _, root := range roots {
+		scanned, err := ScanDirectoryContext(ctx, root, nil)
+		if err != nil {
+			return report, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+		diskFiles = append(diskFiles, scanned...)
+	}
+	AddMissingCandidates(&report, diskFiles, opts)
+	return report, nil
+}
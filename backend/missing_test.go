@@ -0,0 +1,61 @@
+package backend
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckTorrentFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"Show/E01.mkv":     "episode 1",
+		"Show/E02.mkv":     "short",
+		"Show/E04.mkv.!qB": "part",
+	})
+	torrent := TorrentInfo{Hash: "a", Name: "Show", SavePath: dir}
+	files := []TorrentFile{
+		{Index: 0, Name: "Show/E01.mkv", Size: 9, Progress: 1, Priority: 1},
+		{Index: 1, Name: "Show/E02.mkv", Size: 9, Progress: 1, Priority: 1},
+		{Index: 2, Name: "Show/E03.mkv", Size: 9, Progress: 1, Priority: 1},
+		// Still downloading, under the incomplete suffix
+		{Index: 3, Name: "Show/E04.mkv", Size: 9, Progress: 0.4, Priority: 1},
+		// Not wanted
+		{Index: 4, Name: "Show/E05.mkv", Size: 9, Priority: 0},
+	}
+
+	result := CheckTorrentFiles(torrent, files)
+
+	if result.TotalFiles != 4 || len(result.Files) != 2 {
+		t.Fatalf("Expected 2 of 4 files missing, got %+v", result)
+	}
+	if f := result.Files[0]; f.Index != 1 || !f.WrongSize() || f.DiskSize != 5 {
+		t.Errorf("Expected E02.mkv to have the wrong size, got %+v", f)
+	}
+	if f := result.Files[1]; f.Index != 2 || f.WrongSize() || f.Path != filepath.Join(dir, "Show", "E03.mkv") {
+		t.Errorf("Expected E03.mkv to be missing, got %+v", f)
+	}
+}
+
+func TestAddMissingCandidates(t *testing.T) {
+	report := MissingReport{Torrents: []MissingTorrent{{
+		Torrent: TorrentInfo{Hash: "a"},
+		Files: []MissingFile{
+			{Index: 0, Name: "Show/E01.mkv", Size: 9, DiskSize: -1},
+			{Index: 1, Name: "Show/E02.mkv", Size: 7, DiskSize: -1},
+		},
+	}}}
+	diskFiles := []DiskFile{
+		{Path: "/data/Show.E01.mkv", Name: "Show.E01.mkv", Size: 9},
+		{Path: "/data/other.avi", Name: "other.avi", Size: 7},
+	}
+
+	AddMissingCandidates(&report, diskFiles, MatchOptions{RequireSameExtension: true})
+
+	files := report.Torrents[0].Files
+	if len(files[0].Candidates) != 1 || files[0].Candidates[0].Name != "Show.E01.mkv" || !files[0].AutoMatch {
+		t.Errorf("Expected Show.E01.mkv as the selected candidate, got %+v", files[0])
+	}
+	if len(files[1].Candidates) != 0 {
+		t.Errorf("Expected no candidate with another extension, got %+v", files[1].Candidates)
+	}
+}
@@ -0,0 +1,366 @@
+package backend
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// What to do when a move's target already exists
+const (
+	CollisionSkip   = "skip"   // leave the disk file where it is
+	CollisionBackup = "backup" // move the existing file aside to <name>.bak first
+)
+
+// ParseCollisionPolicy validates a collision policy, defaulting to skip
+func ParseCollisionPolicy(value string) (string, error) {
+	switch value {
+	case "", CollisionSkip:
+		return CollisionSkip, nil
+	case CollisionBackup:
+		return CollisionBackup, nil
+	default:
+		return "", fmt.Errorf("unknown collision policy %q, expected skip or backup", value)
+	}
+}
+
+// GenerateMoves returns the moves that put every selected disk file at the
+// torrent file's path under savePath, so the disk mirrors the torrent.
+// OldPath and NewPath are disk paths. Files already in place are left out.
+func GenerateMoves(matches []Match, savePath string) []RenameOperation {
+	moves := []RenameOperation{}
+	for _, m := range matches {
+		if m.Selected == nil {
+			continue
+		}
+		target := filepath.Join(savePath, filepath.FromSlash(m.TorrentFile.Name))
+		if samePath(target, m.Selected.Path) {
+			continue
+		}
+		moves = append(moves, RenameOperation{
+			OldPath:     m.Selected.Path,
+			NewPath:     target,
+			TorrentFile: m.TorrentFile,
+			DiskFile:    *m.Selected,
+		})
+	}
+	return moves
+}
+
+// MoveStep is a single file move on disk
+type MoveStep struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// MoveJournal records every step of a batch of moves as soon as it is done,
+// so the batch can be undone later, even after a crash. Journals of renames
+// in qBittorrent record the applied renames instead of disk moves.
+type MoveJournal struct {
+	ID      string            `json:"id"`
+	Hash    string            `json:"hash"`
+	Time    time.Time         `json:"time"`
+	Steps   []MoveStep        `json:"steps"`             // in the order they were done
+	Renames []RenameOperation `json:"renames,omitempty"` // qBittorrent renames, in the order they were applied
+	Undone  bool              `json:"undone"`
+
+	path string
+}
+
+// DefaultJournalDir returns the directory move journals are kept in
+func DefaultJournalDir() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "qbt-file-matcher", "journal"), nil
+}
+
+func newMoveJournal(dir string, hash string) (*MoveJournal, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	j := &MoveJournal{
+		ID:    now.Format("20060102-150405") + "-" + hex.EncodeToString(b),
+		Hash:  hash,
+		Time:  now,
+		Steps: []MoveStep{},
+	}
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+		j.path = filepath.Join(dir, j.ID+".json")
+	}
+	return j, j.save()
+}
+
+// save writes the journal through a temporary file so it is never partial
+func (j *MoveJournal) save() error {
+	if j.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := j.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, j.path)
+}
+
+func (j *MoveJournal) record(step MoveStep) error {
+	j.Steps = append(j.Steps, step)
+	return j.save()
+}
+
+func (j *MoveJournal) recordRename(r RenameOperation) error {
+	j.Renames = append(j.Renames, r)
+	return j.save()
+}
+
+// IsRenames reports whether the journal holds qBittorrent renames rather
+// than moves on disk
+func (j *MoveJournal) IsRenames() bool {
+	return len(j.Renames) > 0
+}
+
+// LoadJournal reads a move journal by ID
+func LoadJournal(dir string, id string) (*MoveJournal, error) {
+	if !filepath.IsLocal(id) || strings.ContainsAny(id, `/\`) {
+		return nil, fmt.Errorf("invalid journal ID %q", id)
+	}
+	path := filepath.Join(dir, id+".json")
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("journal %s not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	var j MoveJournal
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("invalid journal %s: %w", id, err)
+	}
+	j.path = path
+	return &j, nil
+}
+
+// ListJournals returns the move journals in dir, newest first
+func ListJournals(dir string) ([]*MoveJournal, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var journals []*MoveJournal
+	for _, e := range entries {
+		id, ok := strings.CutSuffix(e.Name(), ".json")
+		if !ok || e.IsDir() {
+			continue
+		}
+		if j, err := LoadJournal(dir, id); err == nil {
+			journals = append(journals, j)
+		}
+	}
+	slices.SortFunc(journals, func(a, b *MoveJournal) int { return b.Time.Compare(a.Time) })
+	return journals, nil
+}
+
+// MoveOptions controls how MoveFiles handles collisions and journaling
+type MoveOptions struct {
+	Hash       string // the torrent the moves belong to, kept in the journal
+	Collision  string // CollisionSkip or CollisionBackup
+	JournalDir string // empty keeps the journal in memory only
+}
+
+// MoveResult summarises a batch of moves
+type MoveResult struct {
+	MovedCount   int               `json:"movedCount"`
+	SkippedCount int               `json:"skippedCount"` // targets taken, with CollisionSkip
+	FailedCount  int               `json:"failedCount"`
+	Moved        []RenameOperation `json:"moved"`
+	Errors       []string          `json:"errors"`
+	JournalID    string            `json:"journalId"`
+}
+
+// MoveFiles moves disk files to their targets, creating folders as needed.
+// A target taken by the source of a later move is cleared by moving that
+// source aside first, so swaps and chains work. Moves across filesystems
+// fall back to copying and removing the source. Every step is journaled
+// before the next one starts. A failed move is recorded and the remaining
+// ones are still done.
+func MoveFiles(ctx context.Context, moves []RenameOperation, opts MoveOptions) (MoveResult, error) {
+	result := MoveResult{Moved: []RenameOperation{}, Errors: []string{}}
+	journal, err := newMoveJournal(opts.JournalDir, opts.Hash)
+	if err != nil {
+		return result, fmt.Errorf("failed to create journal: %w", err)
+	}
+	result.JournalID = journal.ID
+
+	pending := slices.Clone(moves)
+	step := func(from, to string) error {
+		if err := moveFile(from, to); err != nil {
+			return err
+		}
+		return journal.record(MoveStep{From: from, To: to})
+	}
+
+	for i := range pending {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		m := &pending[i]
+
+		err := clearTarget(m.NewPath, pending[i+1:], opts.Collision, step)
+		if errors.Is(err, errTargetTaken) {
+			result.SkippedCount++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: target already exists, skipped", m.NewPath))
+			continue
+		}
+		if err == nil {
+			err = step(m.OldPath, m.NewPath)
+		}
+		if err != nil {
+			result.FailedCount++
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", m.OldPath, err))
+			continue
+		}
+		result.MovedCount++
+		result.Moved = append(result.Moved, moves[i])
+	}
+
+	// Nothing to undo
+	if len(journal.Steps) == 0 && journal.path != "" {
+		os.Remove(journal.path)
+		result.JournalID = ""
+	}
+	return result, nil
+}
+
+var errTargetTaken = errors.New("target already exists")
+
+// clearTarget makes room at target. The source of a later move is moved
+// aside and that move updated; any other file is backed up or, with
+// CollisionSkip, reported as errTargetTaken.
+func clearTarget(target string, later []RenameOperation, policy string, step func(from, to string) error) error {
+	if _, err := os.Lstat(target); errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	for i := range later {
+		if samePath(later[i].OldPath, target) {
+			aside := freePath(target, ".qbt-move")
+			if err := step(target, aside); err != nil {
+				return err
+			}
+			later[i].OldPath = aside
+			return nil
+		}
+	}
+
+	if policy != CollisionBackup {
+		return errTargetTaken
+	}
+	return step(target, freePath(target, ".bak"))
+}
+
+// freePath returns path with suffix, numbered when taken
+func freePath(path string, suffix string) string {
+	candidate := path + suffix
+	for n := 1; ; n++ {
+		if _, err := os.Lstat(candidate); errors.Is(err, os.ErrNotExist) {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s%s.%d", path, suffix, n)
+	}
+}
+
+// moveFile renames from to to, copying across filesystems. The target must
+// not exist.
+func moveFile(from, to string) error {
+	if _, err := os.Lstat(to); err == nil {
+		return errTargetTaken
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0o755); err != nil {
+		return err
+	}
+
+	err := os.Rename(from, to)
+	if err == nil || !isCrossDevice(err) {
+		return err
+	}
+
+	info, err := os.Stat(from)
+	if err != nil {
+		return err
+	}
+	if err := copyFile(from, to); err != nil {
+		return err
+	}
+	_ = os.Chmod(to, info.Mode().Perm())
+	_ = os.Chtimes(to, info.ModTime(), info.ModTime())
+	return os.Remove(from)
+}
+
+// UndoMoves reverses a journal's steps, newest first, and marks it undone.
+// Steps whose file has since been moved again, or whose original path is
+// taken, are reported and skipped; they stay in the journal, which is not
+// marked undone, so undoing again retries them.
+func UndoMoves(ctx context.Context, journal *MoveJournal) (int, []string, error) {
+	if journal.Undone {
+		return 0, nil, fmt.Errorf("journal %s was already undone", journal.ID)
+	}
+	if journal.IsRenames() {
+		return 0, nil, fmt.Errorf("journal %s holds renames in qBittorrent, not moves", journal.ID)
+	}
+
+	undone := 0
+	var errs []string
+	done := make([]bool, len(journal.Steps))
+	var err error
+	for i := len(journal.Steps) - 1; i >= 0; i-- {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		s := journal.Steps[i]
+		if moveErr := moveFile(s.To, s.From); moveErr != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", s.To, moveErr))
+			continue
+		}
+		done[i] = true
+		undone++
+	}
+
+	if undone == len(journal.Steps) {
+		journal.Undone = true
+	} else {
+		// Keep what is left to undo
+		left := []MoveStep{}
+		for i, s := range journal.Steps {
+			if !done[i] {
+				left = append(left, s)
+			}
+		}
+		journal.Steps = left
+	}
+	if saveErr := journal.save(); err == nil {
+		err = saveErr
+	}
+	return undone, errs, err
+}
@@ -0,0 +1,178 @@
+package backend
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFiles creates files under dir from relative paths to contents
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func expectContent(t *testing.T, path string, expected string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil || string(data) != expected {
+		t.Errorf("Expected %s to contain %q, got %q (%v)", path, expected, data, err)
+	}
+}
+
+func TestGenerateMoves(t *testing.T) {
+	matches := []Match{
+		{TorrentFile: TorrentFileInfo{Name: "Show.S01/E01.mkv"}, Selected: &DiskFile{Path: "/library/Show/E01.mkv"}},
+		{TorrentFile: TorrentFileInfo{Name: "Show.S01/E02.mkv"}, Selected: &DiskFile{Path: "/downloads/Show.S01/E02.mkv"}},
+		{TorrentFile: TorrentFileInfo{Name: "Show.S01/E03.mkv"}},
+	}
+
+	moves := GenerateMoves(matches, "/downloads")
+
+	if len(moves) != 1 || moves[0].OldPath != "/library/Show/E01.mkv" || moves[0].NewPath != filepath.Join("/downloads", "Show.S01", "E01.mkv") {
+		t.Errorf("Expected a single move of E01.mkv, got %+v", moves)
+	}
+}
+
+func TestMoveFiles_AndUndo(t *testing.T) {
+	dir := t.TempDir()
+	journalDir := filepath.Join(dir, "journal")
+	writeFiles(t, dir, map[string]string{"library/E01.mkv": "one", "library/E02.mkv": "two"})
+	moves := []RenameOperation{
+		{OldPath: filepath.Join(dir, "library", "E01.mkv"), NewPath: filepath.Join(dir, "downloads", "Show", "E01.mkv")},
+		{OldPath: filepath.Join(dir, "library", "E02.mkv"), NewPath: filepath.Join(dir, "downloads", "Show", "E02.mkv")},
+	}
+
+	result, err := MoveFiles(context.Background(), moves, MoveOptions{Hash: "abc", JournalDir: journalDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MovedCount != 2 || result.JournalID == "" {
+		t.Fatalf("Expected 2 journaled moves, got %+v", result)
+	}
+	expectContent(t, moves[0].NewPath, "one")
+	expectContent(t, moves[1].NewPath, "two")
+
+	journals, err := ListJournals(journalDir)
+	if err != nil || len(journals) != 1 || len(journals[0].Steps) != 2 || journals[0].Hash != "abc" {
+		t.Fatalf("Expected one journal with 2 steps, got %+v (%v)", journals, err)
+	}
+
+	undone, errs, err := UndoMoves(context.Background(), journals[0])
+	if err != nil || undone != 2 || len(errs) != 0 {
+		t.Fatalf("Expected 2 moves undone, got %d %v %v", undone, errs, err)
+	}
+	expectContent(t, moves[0].OldPath, "one")
+	expectContent(t, moves[1].OldPath, "two")
+
+	reloaded, _ := LoadJournal(journalDir, result.JournalID)
+	if !reloaded.Undone {
+		t.Error("Expected journal to be marked undone")
+	}
+	if _, _, err := UndoMoves(context.Background(), reloaded); err == nil {
+		t.Error("Expected error undoing twice")
+	}
+}
+
+func TestUndoMoves_KeepsFailedSteps(t *testing.T) {
+	dir := t.TempDir()
+	journalDir := filepath.Join(dir, "journal")
+	writeFiles(t, dir, map[string]string{"library/E01.mkv": "one", "library/E02.mkv": "two"})
+	moves := []RenameOperation{
+		{OldPath: filepath.Join(dir, "library", "E01.mkv"), NewPath: filepath.Join(dir, "downloads", "E01.mkv")},
+		{OldPath: filepath.Join(dir, "library", "E02.mkv"), NewPath: filepath.Join(dir, "downloads", "E02.mkv")},
+	}
+	result, err := MoveFiles(context.Background(), moves, MoveOptions{JournalDir: journalDir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A new file took E02.mkv's original path
+	writeFiles(t, dir, map[string]string{"library/E02.mkv": "new"})
+	journal, _ := LoadJournal(journalDir, result.JournalID)
+	undone, errs, err := UndoMoves(context.Background(), journal)
+	if err != nil || undone != 1 || len(errs) != 1 {
+		t.Fatalf("Expected 1 move undone and 1 error, got %d %v %v", undone, errs, err)
+	}
+	reloaded, _ := LoadJournal(journalDir, result.JournalID)
+	if reloaded.Undone || len(reloaded.Steps) != 1 || reloaded.Steps[0].To != moves[1].NewPath {
+		t.Fatalf("Expected the failed step to be left to undo, got %+v", reloaded)
+	}
+
+	if err := os.Remove(moves[1].OldPath); err != nil {
+		t.Fatal(err)
+	}
+	if undone, errs, err := UndoMoves(context.Background(), reloaded); err != nil || undone != 1 || len(errs) != 0 {
+		t.Fatalf("Expected the retry to undo the move, got %d %v %v", undone, errs, err)
+	}
+	expectContent(t, moves[1].OldPath, "two")
+	if reloaded, _ := LoadJournal(journalDir, result.JournalID); !reloaded.Undone {
+		t.Error("Expected journal to be marked undone after the retry")
+	}
+}
+
+func TestMoveFiles_Collisions(t *testing.T) {
+	for _, policy := range []string{CollisionSkip, CollisionBackup} {
+		dir := t.TempDir()
+		writeFiles(t, dir, map[string]string{"library/E01.mkv": "new", "downloads/E01.mkv": "old"})
+		moves := []RenameOperation{{OldPath: filepath.Join(dir, "library", "E01.mkv"), NewPath: filepath.Join(dir, "downloads", "E01.mkv")}}
+
+		result, err := MoveFiles(context.Background(), moves, MoveOptions{Collision: policy})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch policy {
+		case CollisionSkip:
+			if result.SkippedCount != 1 || result.MovedCount != 0 {
+				t.Errorf("Expected move to be skipped, got %+v", result)
+			}
+			expectContent(t, moves[0].NewPath, "old")
+		case CollisionBackup:
+			if result.MovedCount != 1 {
+				t.Errorf("Expected move after backup, got %+v", result)
+			}
+			expectContent(t, moves[0].NewPath, "new")
+			expectContent(t, moves[0].NewPath+".bak", "old")
+		}
+	}
+}
+
+func TestMoveFiles_Swap(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"a.mkv": "A", "b.mkv": "B"})
+	a, b := filepath.Join(dir, "a.mkv"), filepath.Join(dir, "b.mkv")
+	moves := []RenameOperation{{OldPath: a, NewPath: b}, {OldPath: b, NewPath: a}}
+
+	result, err := MoveFiles(context.Background(), moves, MoveOptions{JournalDir: filepath.Join(dir, "journal")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.MovedCount != 2 {
+		t.Fatalf("Expected both moves, got %+v", result)
+	}
+	expectContent(t, a, "B")
+	expectContent(t, b, "A")
+
+	journal, _ := LoadJournal(filepath.Join(dir, "journal"), result.JournalID)
+	if _, errs, err := UndoMoves(context.Background(), journal); err != nil || len(errs) != 0 {
+		t.Fatalf("Undo failed: %v %v", errs, err)
+	}
+	expectContent(t, a, "A")
+	expectContent(t, b, "B")
+}
+
+func TestLoadJournal_InvalidID(t *testing.T) {
+	if _, err := LoadJournal(t.TempDir(), "../secret"); err == nil {
+		t.Error("Expected error for a journal ID outside the directory")
+	}
+}
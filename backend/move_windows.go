@@ -0,0 +1,15 @@
+package backend
+
+import (
+	"errors"
+	"syscall"
+)
+
+// ERROR_NOT_SAME_DEVICE
+const errNotSameDevice = syscall.Errno(17)
+
+// isCrossDevice reports whether a rename failed because source and target
+// are on different volumes
+func isCrossDevice(err error) bool {
+	return errors.Is(err, errNotSameDevice)
+}
@@ -0,0 +1,215 @@
+package backend
+
+import (
+	"cmp"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// incompleteSuffix is appended by qBittorrent to files still downloading
+// when "append .!qB extension to incomplete files" is on
+const incompleteSuffix = ".!qB"
+
+// OrphanJournalHash marks move journals of orphans moved to the trash, in
+// place of a torrent hash
+const OrphanJournalHash = "orphans"
+
+// OrphanDir is a directory with files no torrent points at
+type OrphanDir struct {
+	Dir   string     `json:"dir"`
+	Files []DiskFile `json:"files"`
+	Size  int64      `json:"size"`
+}
+
+// OrphanReport lists the disk files under the scanned roots that no torrent
+// in qBittorrent points at, grouped by directory
+type OrphanReport struct {
+	Roots      []string    `json:"roots"`
+	Dirs       []OrphanDir `json:"dirs"`
+	FileCount  int         `json:"fileCount"`
+	TotalSize  int64       `json:"totalSize"`
+	Referenced int         `json:"referenced"` // scanned files some torrent points at
+}
+
+// Files returns every orphan in the report
+func (r OrphanReport) Files() []DiskFile {
+	var files []DiskFile
+	for _, d := range r.Dirs {
+		files = append(files, d.Files...)
+	}
+	return files
+}
+
+// ReferencedPaths returns the disk paths torrents point at, in the save
+// path and, for torrents with one, the download path, with and without the
+// incomplete file suffix. Keys are made with pathKey.
+func ReferencedPaths(torrents []TorrentInfo, files map[string][]TorrentFile) map[string]bool {
+	referenced := make(map[string]bool)
+	for _, t := range torrents {
+		for _, dir := range []string{t.SavePath, t.DownloadPath} {
+			if dir == "" {
+				continue
+			}
+			for _, f := range files[t.Hash] {
+				path := filepath.Join(dir, filepath.FromSlash(f.Name))
+				referenced[pathKey(path)] = true
+				referenced[pathKey(path+incompleteSuffix)] = true
+			}
+		}
+	}
+	return referenced
+}
+
+// FindOrphans groups the disk files whose path is not referenced, sorted by
+// directory
+func FindOrphans(diskFiles []DiskFile, referenced map[string]bool) OrphanReport {
+	report := OrphanReport{Dirs: []OrphanDir{}}
+	byDir := make(map[string]int)
+	for _, f := range diskFiles {
+		if referenced[pathKey(f.Path)] {
+			report.Referenced++
+			continue
+		}
+		dir := filepath.Dir(f.Path)
+		i, ok := byDir[dir]
+		if !ok {
+			i = len(report.Dirs)
+			byDir[dir] = i
+			report.Dirs = append(report.Dirs, OrphanDir{Dir: dir})
+		}
+		report.Dirs[i].Files = append(report.Dirs[i].Files, f)
+		report.Dirs[i].Size += f.Size
+		report.FileCount++
+		report.TotalSize += f.Size
+	}
+	slices.SortFunc(report.Dirs, func(a, b OrphanDir) int { return cmp.Compare(a.Dir, b.Dir) })
+	for _, d := range report.Dirs {
+		slices.SortFunc(d.Files, func(a, b DiskFile) int { return cmp.Compare(a.Path, b.Path) })
+	}
+	return report
+}
+
+// FindOrphanFiles scans roots and reports the files no torrent in
+// qBittorrent points at. Files under exclude, e.g. the trash directory, are
+// left out. Save paths must be paths on this machine.
+func (s *QBitService) FindOrphanFiles(ctx context.Context, roots []string, exclude string) (OrphanReport, error) {
+	torrents, err := s.GetTorrents()
+	if err != nil {
+		return OrphanReport{}, err
+	}
+	files := make(map[string][]TorrentFile, len(torrents))
+	for _, t := range torrents {
+		if err := ctx.Err(); err != nil {
+			return OrphanReport{}, err
+		}
+		list, err := s.GetTorrentFiles(t.Hash)
+		if err != nil {
+			return OrphanReport{}, fmt.Errorf("failed to get files of %s: %w", t.Name, err)
+		}
+		files[t.Hash] = list
+	}
+
+	var diskFiles []DiskFile
+	for _, root := range roots {
+		scanned, err := ScanDirectoryContext(ctx, root, nil)
+		if err != nil {
+			return OrphanReport{}, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+		for _, f := range scanned {
+			if exclude == "" || !isWithin(f.Path, exclude) {
+				diskFiles = append(diskFiles, f)
+			}
+		}
+	}
+
+	report := FindOrphans(diskFiles, ReferencedPaths(torrents, files))
+	report.Roots = roots
+	return report, nil
+}
+
+// isWithin reports whether path is dir or lies under it
+func isWithin(path string, dir string) bool {
+	rel, err := filepath.Rel(pathKey(dir), pathKey(path))
+	return err == nil && filepath.IsLocal(rel)
+}
+
+// ExportOrphans writes the report as JSON, or as CSV rows of path, size and
+// directory when path ends in .csv
+func ExportOrphans(report OrphanReport, path string) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		err = writeOrphansCSV(f, report)
+	} else {
+		enc := json.NewEncoder(f)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(report)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func writeOrphansCSV(w io.Writer, report OrphanReport) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"path", "size", "dir"})
+	for _, d := range report.Dirs {
+		for _, f := range d.Files {
+			cw.Write([]string{f.Path, strconv.FormatInt(f.Size, 10), d.Dir})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// TrashMoves returns the moves that put orphans in trash, keeping their path
+// relative to the root they were found under, inside a folder named after
+// the root
+func TrashMoves(files []DiskFile, roots []string, trash string) []RenameOperation {
+	var moves []RenameOperation
+	for _, f := range files {
+		for _, root := range roots {
+			rel, err := filepath.Rel(root, f.Path)
+			if err != nil || !filepath.IsLocal(rel) {
+				continue
+			}
+			moves = append(moves, RenameOperation{
+				OldPath:  f.Path,
+				NewPath:  filepath.Join(trash, filepath.Base(filepath.Clean(root)), rel),
+				DiskFile: f,
+			})
+			break
+		}
+	}
+	return moves
+}
+
+// TrashOrphans moves orphans into trash instead of deleting them. The moves
+// are journaled like those of move mode, so they can be undone, and a file
+// already in the trash is never overwritten.
+func TrashOrphans(ctx context.Context, files []DiskFile, roots []string, trash string, journalDir string) (MoveResult, error) {
+	if trash == "" {
+		return MoveResult{}, fmt.Errorf("no trash directory")
+	}
+	for _, root := range roots {
+		if isWithin(root, trash) {
+			return MoveResult{}, fmt.Errorf("trash directory %s contains root %s", trash, root)
+		}
+	}
+	return MoveFiles(ctx, TrashMoves(files, roots, trash), MoveOptions{
+		Hash:       OrphanJournalHash,
+		Collision:  CollisionSkip,
+		JournalDir: journalDir,
+	})
+}
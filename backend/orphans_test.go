@@ -0,0 +1,87 @@
+package backend
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindOrphans(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"movies/Movie/Movie.mkv":       "movie",
+		"movies/Movie/extra.nfo":       "nfo",
+		"movies/Old/Old.mkv":           "old movie",
+		"incomplete/Show/E01.mkv.!qB":  "partial",
+		"incomplete/Show/leftover.txt": "x",
+	})
+	torrents := []TorrentInfo{
+		{Hash: "a", SavePath: filepath.Join(dir, "movies")},
+		{Hash: "b", SavePath: filepath.Join(dir, "tv"), DownloadPath: filepath.Join(dir, "incomplete")},
+	}
+	files := map[string][]TorrentFile{
+		"a": {{Name: "Movie/Movie.mkv"}},
+		"b": {{Name: "Show/E01.mkv"}},
+	}
+	diskFiles, err := ScanDirectory(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := FindOrphans(diskFiles, ReferencedPaths(torrents, files))
+
+	if report.FileCount != 3 || report.Referenced != 2 || len(report.Dirs) != 3 {
+		t.Fatalf("Expected 3 orphans in 3 folders, got %+v", report)
+	}
+	if report.Dirs[0].Dir != filepath.Join(dir, "incomplete", "Show") || report.Dirs[0].Files[0].Name != "leftover.txt" {
+		t.Errorf("Expected folders sorted by path, got %+v", report.Dirs)
+	}
+	if report.TotalSize != int64(len("nfo")+len("old movie")+len("x")) {
+		t.Errorf("Unexpected total size %d", report.TotalSize)
+	}
+}
+
+func TestExportOrphans(t *testing.T) {
+	dir := t.TempDir()
+	report := FindOrphans([]DiskFile{{Path: filepath.Join("/data", "a.mkv"), Name: "a.mkv", Size: 5}}, nil)
+	path := filepath.Join(dir, "orphans.csv")
+
+	if err := ExportOrphans(report, path); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || lines[1] != filepath.Join("/data", "a.mkv")+",5,"+filepath.Clean("/data") {
+		t.Errorf("Unexpected CSV export: %q", data)
+	}
+}
+
+func TestTrashOrphans_AndUndo(t *testing.T) {
+	dir := t.TempDir()
+	root := filepath.Join(dir, "data")
+	trash := filepath.Join(dir, "trash")
+	journalDir := filepath.Join(dir, "journal")
+	writeFiles(t, root, map[string]string{"Old/Old.mkv": "old"})
+	files := []DiskFile{{Path: filepath.Join(root, "Old", "Old.mkv"), Name: "Old.mkv", Size: 3}}
+
+	if _, err := TrashOrphans(context.Background(), files, []string{root}, dir, journalDir); err == nil {
+		t.Error("Expected error for a trash directory containing the root")
+	}
+
+	result, err := TrashOrphans(context.Background(), files, []string{root}, trash, journalDir)
+	if err != nil || result.MovedCount != 1 {
+		t.Fatalf("Expected 1 file moved, got %+v (%v)", result, err)
+	}
+	expectContent(t, filepath.Join(trash, "data", "Old", "Old.mkv"), "old")
+
+	journal, err := LoadJournal(journalDir, result.JournalID)
+	if err != nil || journal.Hash != OrphanJournalHash {
+		t.Fatalf("Expected an orphans journal, got %+v (%v)", journal, err)
+	}
+	if _, errs, err := UndoMoves(context.Background(), journal); err != nil || len(errs) != 0 {
+		t.Fatalf("Undo failed: %v %v", errs, err)
+	}
+	expectContent(t, files[0].Path, "old")
+}
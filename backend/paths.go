@@ -0,0 +1,77 @@
+package backend
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// caseInsensitivePaths is whether this platform's file systems ignore case
+// in file names by default (NTFS, APFS, HFS+)
+var caseInsensitivePaths = runtime.GOOS == "windows" || runtime.GOOS == "darwin"
+
+// unicodeInsensitivePaths is whether this platform's file systems treat the
+// composed (NFC) and decomposed (NFD) forms of a name as the same file
+// (APFS, HFS+). Elsewhere they are different names, e.g. a file created on
+// macOS and copied to a Linux share keeps its NFD name, and qBittorrent will
+// not find it under the NFC name in the torrent.
+var unicodeInsensitivePaths = runtime.GOOS == "darwin"
+
+// nameKey normalises a file name or slash-separated relative path the way
+// this platform's file systems compare names
+func nameKey(name string) string {
+	if unicodeInsensitivePaths {
+		name = norm.NFC.String(name)
+	}
+	if caseInsensitivePaths {
+		name = strings.ToLower(name)
+	}
+	return name
+}
+
+// pathKey normalises a path for comparison, see nameKey
+func pathKey(path string) string {
+	return nameKey(filepath.Clean(path))
+}
+
+// samePath reports whether two paths name the same file on this platform
+func samePath(a, b string) bool {
+	return pathKey(a) == pathKey(b)
+}
+
+// sameTorrentPath reports whether a torrent file path and a path relative
+// to the save path name the same file on this platform, so renaming one to
+// the other would change nothing
+func sameTorrentPath(a, b string) bool {
+	return nameKey(filepath.ToSlash(a)) == nameKey(filepath.ToSlash(b))
+}
+
+// relPath is filepath.Rel, but with base matched against target the way
+// this platform compares names, so a search path typed in another case or
+// Unicode form than the scanned paths still yields a path inside it
+func relPath(base, target string) (string, error) {
+	base, target = filepath.Clean(base), filepath.Clean(target)
+	baseParts := strings.Split(base, string(filepath.Separator))
+	targetParts := strings.Split(target, string(filepath.Separator))
+	if base != string(filepath.Separator) && len(targetParts) > len(baseParts) {
+		inside := true
+		for i, part := range baseParts {
+			if nameKey(part) != nameKey(targetParts[i]) {
+				inside = false
+				break
+			}
+		}
+		if inside {
+			return filepath.Join(targetParts[len(baseParts):]...), nil
+		}
+	}
+	return filepath.Rel(base, target)
+}
+
+// normalizeName returns a file name in composed Unicode form (NFC), so names
+// typed on different systems compare and tokenise alike when matching
+func normalizeName(name string) string {
+	return norm.NFC.String(name)
+}
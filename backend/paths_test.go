@@ -0,0 +1,61 @@
+package backend
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// Composed and decomposed forms of "Café"
+const (
+	cafeNFC = "Caf\u00e9"
+	cafeNFD = "Cafe\u0301"
+)
+
+// setPathPlatform makes paths compare like on another platform for a test
+func setPathPlatform(t *testing.T, caseInsensitive bool, unicodeInsensitive bool) {
+	t.Helper()
+	oldCase, oldUnicode := caseInsensitivePaths, unicodeInsensitivePaths
+	caseInsensitivePaths, unicodeInsensitivePaths = caseInsensitive, unicodeInsensitive
+	t.Cleanup(func() { caseInsensitivePaths, unicodeInsensitivePaths = oldCase, oldUnicode })
+}
+
+func TestSameTorrentPath(t *testing.T) {
+	tests := []struct {
+		name                           string
+		caseInsensitive, unicodeInsens bool
+		a, b                           string
+		expected                       bool
+	}{
+		{"linux case", false, false, "Show/E01.mkv", "show/e01.mkv", false},
+		{"linux unicode", false, false, cafeNFC + "/E01.mkv", cafeNFD + "/E01.mkv", false},
+		{"windows case", true, false, "Show/E01.mkv", "show/e01.mkv", true},
+		{"windows unicode", true, false, cafeNFC + "/E01.mkv", cafeNFD + "/E01.mkv", false},
+		{"darwin case and unicode", true, true, cafeNFC + "/E01.mkv", "CAFÉ/e01.MKV", true},
+		{"darwin different", true, true, "Show/E01.mkv", "Show/E02.mkv", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setPathPlatform(t, tt.caseInsensitive, tt.unicodeInsens)
+			if got := sameTorrentPath(tt.a, tt.b); got != tt.expected {
+				t.Errorf("sameTorrentPath(%q, %q) = %v, expected %v", tt.a, tt.b, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRelPath(t *testing.T) {
+	setPathPlatform(t, true, true)
+	base := filepath.Join("/data", "downloads", cafeNFC)
+	target := filepath.Join("/data", "Downloads", cafeNFD, "Show", "E01.mkv")
+
+	rel, err := relPath(base, target)
+	if err != nil || rel != filepath.Join("Show", "E01.mkv") {
+		t.Errorf("Expected Show/E01.mkv, got %q (%v)", rel, err)
+	}
+
+	// Outside the base, as filepath.Rel
+	rel, err = relPath(base, filepath.Join("/data", "other", "E01.mkv"))
+	if err != nil || rel != filepath.Join("..", "..", "other", "E01.mkv") {
+		t.Errorf("Expected a path outside the base, got %q (%v)", rel, err)
+	}
+}
@@ -0,0 +1,380 @@
+package backend
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// PieceLayout describes how a torrent's pieces span its files
+type PieceLayout struct {
+	PieceLength int64             `json:"pieceLength"`
+	PieceHashes []string          `json:"pieceHashes"` // hex-encoded SHA-1
+	Files       []TorrentFileInfo `json:"files"`       // in torrent order
+}
+
+// PieceLayout returns the piece layout of a parsed .torrent
+func (m *Metainfo) PieceLayout() PieceLayout {
+	return PieceLayout{
+		PieceLength: m.PieceLength,
+		PieceHashes: m.PieceHashes,
+		Files:       m.TorrentFiles(),
+	}
+}
+
+// GetPieceLayout returns the piece layout of a torrent in qBittorrent
+func (s *QBitService) GetPieceLayout(hash string) (PieceLayout, error) {
+	if s.client == nil {
+		return PieceLayout{}, ErrNotConnected
+	}
+
+	props, err := s.client.GetTorrentProperties(hash)
+	if err != nil {
+		return PieceLayout{}, fmt.Errorf("failed to get torrent properties: %w", err)
+	}
+	hashes, err := s.client.GetTorrentPieceHashes(hash)
+	if err != nil {
+		return PieceLayout{}, fmt.Errorf("failed to get piece hashes: %w", err)
+	}
+	files, err := s.GetTorrentFiles(hash)
+	if err != nil {
+		return PieceLayout{}, err
+	}
+
+	layout := PieceLayout{
+		PieceLength: int64(props.PieceSize),
+		PieceHashes: hashes,
+		Files:       make([]TorrentFileInfo, len(files)),
+	}
+	for i, f := range files {
+		layout.Files[i] = TorrentFileInfo{Index: f.Index, Name: f.Name, Size: f.Size}
+	}
+	sort.Slice(layout.Files, func(i, j int) bool { return layout.Files[i].Index < layout.Files[j].Index })
+	layout.restoreHiddenPadding()
+	return layout, nil
+}
+
+// restoreHiddenPadding puts back the pad files qBittorrent hides from its
+// file list. Without them every file after a pad starts at the wrong offset.
+// When the listed files don't fill the pieces, every file but the last is
+// assumed to be padded to a piece boundary, as BEP 47 torrent creators do.
+func (l *PieceLayout) restoreHiddenPadding() {
+	var total int64
+	for _, f := range l.Files {
+		total += f.Size
+	}
+	if l.PieceLength <= 0 || pieceCount(total, l.PieceLength) == len(l.PieceHashes) {
+		return
+	}
+
+	padded := make([]TorrentFileInfo, 0, 2*len(l.Files))
+	total = 0
+	for i, f := range l.Files {
+		padded = append(padded, f)
+		total += f.Size
+		if rest := f.Size % l.PieceLength; rest != 0 && i < len(l.Files)-1 {
+			size := l.PieceLength - rest
+			padded = append(padded, TorrentFileInfo{Index: -1, Name: fmt.Sprintf(".pad/%d", size), Size: size, Pad: true})
+			total += size
+		}
+	}
+	if pieceCount(total, l.PieceLength) == len(l.PieceHashes) {
+		l.Files = padded
+	}
+}
+
+func pieceCount(total int64, pieceLength int64) int {
+	return int((total + pieceLength - 1) / pieceLength)
+}
+
+// PieceReport summarises which pieces would pass verification
+type PieceReport struct {
+	TotalPieces int          `json:"totalPieces"`
+	Passed      int          `json:"passed"`
+	Failed      int          `json:"failed"`
+	Unchecked   int          `json:"unchecked"` // pieces touching files without a disk file
+	Files       []FilePieces `json:"files"`
+}
+
+// FilePieces counts the pieces overlapping a single torrent file
+type FilePieces struct {
+	Index  int    `json:"index"`
+	Name   string `json:"name"`
+	Pieces int    `json:"pieces"`
+	Passed int    `json:"passed"`
+	Failed int    `json:"failed"`
+}
+
+// VerifyPieces hashes the disk files mapped to torrent files (by index) and
+// reports how many pieces would pass a recheck. Disk files are read at the
+// torrent file's offsets, so a file with rewritten tags still passes the
+// pieces its edit did not touch. Pad files are hashed as zeros and left out
+// of the per-file counts.
+func VerifyPieces(ctx context.Context, layout PieceLayout, diskPaths map[int]string) (PieceReport, error) {
+	sources := make(map[int][]FileSegment, len(diskPaths))
+	for index, path := range diskPaths {
+		sources[index] = []FileSegment{{Path: path, Size: -1}}
+	}
+	return VerifySources(ctx, layout, sources)
+}
+
+// VerifySources is VerifyPieces for torrent files whose data is made of
+// segments of disk files, e.g. files that still have to be joined from parts
+func VerifySources(ctx context.Context, layout PieceLayout, sources map[int][]FileSegment) (PieceReport, error) {
+	report := PieceReport{TotalPieces: len(layout.PieceHashes)}
+	if layout.PieceLength <= 0 {
+		return report, fmt.Errorf("invalid piece length %d", layout.PieceLength)
+	}
+
+	// Global byte offset of every file
+	offsets := make([]int64, len(layout.Files)+1)
+	for i, f := range layout.Files {
+		offsets[i+1] = offsets[i] + f.Size
+		report.Files = append(report.Files, FilePieces{Index: f.Index, Name: f.Name})
+	}
+
+	open := make(map[int]*openSource)
+	defer func() {
+		for _, f := range open {
+			f.Close()
+		}
+	}()
+
+	buf := make([]byte, layout.PieceLength)
+	first := 0 // first file that may overlap the current piece
+	for p, expected := range layout.PieceHashes {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		start := int64(p) * layout.PieceLength
+		end := min(start+layout.PieceLength, offsets[len(layout.Files)])
+		for first < len(layout.Files) && offsets[first+1] <= start {
+			first++
+		}
+
+		piece := buf[:end-start]
+		clear(piece)
+		checkable := true
+		short := false // a disk file ends inside the piece, so it fails
+		var overlapping []int
+		for i := first; i < len(layout.Files) && offsets[i] < end; i++ {
+			if layout.Files[i].Size == 0 || layout.Files[i].IsPad() {
+				continue // pad files are zeros, which piece is already
+			}
+			overlapping = append(overlapping, i)
+
+			segments, ok := sources[layout.Files[i].Index]
+			if !ok {
+				checkable = false
+				continue
+			}
+			f, err := openCached(open, i, segments)
+			if err != nil {
+				short = true
+				continue
+			}
+
+			// Bytes of this file inside the piece. A partial download
+			// lacks them past its end, so there is nothing to hash.
+			from := max(start, offsets[i])
+			to := min(end, offsets[i+1])
+			if short || to-offsets[i] > f.size {
+				short = true
+				continue
+			}
+			if _, err := f.ReadAt(piece[from-start:to-start], from-offsets[i]); err != nil && err != io.EOF {
+				return report, fmt.Errorf("failed to read %s: %w", segments[0].Path, err)
+			}
+		}
+
+		if !checkable {
+			report.Unchecked++
+			for _, i := range overlapping {
+				report.Files[i].Pieces++
+			}
+			continue
+		}
+
+		passed := false
+		if !short {
+			sum := sha1.Sum(piece)
+			passed = hex.EncodeToString(sum[:]) == expected
+		}
+		if passed {
+			report.Passed++
+		} else {
+			report.Failed++
+		}
+		for _, i := range overlapping {
+			report.Files[i].Pieces++
+			if passed {
+				report.Files[i].Passed++
+			} else {
+				report.Files[i].Failed++
+			}
+		}
+	}
+
+	files := report.Files[:0]
+	for i, f := range report.Files {
+		if !layout.Files[i].IsPad() {
+			files = append(files, f)
+		}
+	}
+	report.Files = files
+	return report, nil
+}
+
+// openSource is the data of a torrent file opened for hashing: segments of
+// disk files read back to back
+type openSource struct {
+	files    []*os.File
+	sections []*io.SectionReader
+	size     int64
+}
+
+func openCached(open map[int]*openSource, i int, segments []FileSegment) (*openSource, error) {
+	if s, ok := open[i]; ok {
+		return s, nil
+	}
+	s := &openSource{}
+	for _, seg := range segments {
+		f, err := os.Open(seg.Path)
+		if err != nil {
+			s.Close()
+			return nil, err
+		}
+		s.files = append(s.files, f)
+
+		size := seg.Size
+		if size < 0 {
+			info, err := f.Stat()
+			if err != nil {
+				s.Close()
+				return nil, err
+			}
+			size = info.Size() - seg.Offset
+		}
+		s.sections = append(s.sections, io.NewSectionReader(f, seg.Offset, size))
+		s.size += size
+	}
+	open[i] = s
+	return s, nil
+}
+
+// ReadAt reads from the segments as if they were one file
+func (s *openSource) ReadAt(p []byte, off int64) (int, error) {
+	n := 0
+	for _, section := range s.sections {
+		if len(p) == 0 {
+			break
+		}
+		if off >= section.Size() {
+			off -= section.Size()
+			continue
+		}
+		read, err := section.ReadAt(p[:min(int64(len(p)), section.Size()-off)], off)
+		n += read
+		p = p[read:]
+		off = 0
+		if err != nil && err != io.EOF {
+			return n, err
+		}
+	}
+	if len(p) > 0 {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (s *openSource) Close() error {
+	for _, f := range s.files {
+		f.Close()
+	}
+	return nil
+}
+
+// VerifyPiecesRequest asks which pieces of a torrent would pass with the
+// selected disk files
+type VerifyPiecesRequest struct {
+	Hash    string      `json:"hash"`
+	Matches []MatchInfo `json:"matches"`
+}
+
+// VerifyMatchPieces reports how many pieces of a torrent in qBittorrent would
+// pass a recheck with the selected disk files, without renaming anything
+func (s *QBitService) VerifyMatchPieces(req VerifyPiecesRequest) (PieceReport, error) {
+	layout, err := s.GetPieceLayout(req.Hash)
+	if err != nil {
+		return PieceReport{}, err
+	}
+	matches := make([]Match, len(req.Matches))
+	for i, m := range req.Matches {
+		matches[i] = Match(m)
+	}
+	return VerifySources(context.Background(), layout, SelectedSources(matches))
+}
+
+// SelectedPaths maps torrent file indices to their selected disk files.
+// Archive entries and reassembled files are left out until they are written.
+func SelectedPaths(matches []Match) map[int]string {
+	paths := make(map[int]string)
+	for _, m := range matches {
+		if m.Selected != nil && !m.Selected.IsArchiveEntry() && !m.Selected.IsReassembled() {
+			paths[m.TorrentFile.Index] = m.Selected.Path
+		}
+	}
+	return paths
+}
+
+// SelectedSources is SelectedPaths with reassembled files read from the
+// disk files they would be rebuilt from
+func SelectedSources(matches []Match) map[int][]FileSegment {
+	sources := make(map[int][]FileSegment)
+	for index, path := range SelectedPaths(matches) {
+		sources[index] = []FileSegment{{Path: path, Size: -1}}
+	}
+	for _, m := range matches {
+		if m.Selected != nil && m.Selected.IsReassembled() {
+			sources[m.TorrentFile.Index] = m.Selected.Parts
+		}
+	}
+	return sources
+}
+
+// NeedsPieceVerification reports whether a selection is only worth applying
+// once its pieces are verified: a partial download, or a file joined or
+// split from other disk files
+func NeedsPieceVerification(matches []Match) bool {
+	for _, m := range matches {
+		if m.Selected != nil && (m.Partial || m.Selected.IsReassembled()) {
+			return true
+		}
+	}
+	return false
+}
+
+// UnselectUnsalvaged clears the selection of partial downloads none of
+// whose pieces pass, as renaming to them gains nothing, and returns how many
+// were cleared
+func UnselectUnsalvaged(matches []Match, report PieceReport) int {
+	passed := make(map[int]int, len(report.Files))
+	for _, f := range report.Files {
+		passed[f.Index] = f.Passed
+	}
+	cleared := 0
+	for i := range matches {
+		m := &matches[i]
+		if m.Partial && m.Selected != nil && passed[m.TorrentFile.Index] == 0 {
+			m.Selected = nil
+			m.AutoMatched = false
+			cleared++
+		}
+	}
+	return cleared
+}
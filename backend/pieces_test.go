@@ -0,0 +1,156 @@
+package backend
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// testLayout writes files to dir and returns their layout with the given
+// piece length, hashed from the original contents
+func testLayout(t *testing.T, dir string, pieceLength int64, contents ...string) (PieceLayout, map[int]string) {
+	t.Helper()
+	var all []byte
+	layout := PieceLayout{PieceLength: pieceLength}
+	paths := make(map[int]string)
+	for i, c := range contents {
+		path := filepath.Join(dir, string(rune('a'+i))+".bin")
+		if err := os.WriteFile(path, []byte(c), 0644); err != nil {
+			t.Fatal(err)
+		}
+		layout.Files = append(layout.Files, TorrentFileInfo{Index: i, Name: filepath.Base(path), Size: int64(len(c))})
+		paths[i] = path
+		all = append(all, c...)
+	}
+	for start := 0; start < len(all); start += int(pieceLength) {
+		sum := sha1.Sum(all[start:min(start+int(pieceLength), len(all))])
+		layout.PieceHashes = append(layout.PieceHashes, hex.EncodeToString(sum[:]))
+	}
+	return layout, paths
+}
+
+func TestVerifyPieces_AllPass(t *testing.T) {
+	layout, paths := testLayout(t, t.TempDir(), 8, "0123456789", "abcdefghijklmn", "xyz")
+
+	report, err := VerifyPieces(context.Background(), layout, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalPieces != 4 || report.Passed != 4 || report.Failed != 0 {
+		t.Errorf("Expected 4 of 4 pieces to pass, got %+v", report)
+	}
+	// The second file (bytes 10 to 24) spans pieces 1 and 2
+	if report.Files[1].Pieces != 2 || report.Files[1].Passed != 2 {
+		t.Errorf("Expected second file to overlap 2 passing pieces, got %+v", report.Files[1])
+	}
+}
+
+func TestVerifyPieces_ModifiedFile(t *testing.T) {
+	dir := t.TempDir()
+	layout, paths := testLayout(t, dir, 8, "0123456789ABCDEFGHIJKLMNOPQRSTUV")
+
+	// Rewrite the "header" in place and grow the file, as a tag editor would
+	if err := os.WriteFile(paths[0], []byte("XXXX456789ABCDEFGHIJKLMNOPQRSTUV++"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := VerifyPieces(context.Background(), layout, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Passed != 3 || report.Failed != 1 {
+		t.Errorf("Expected 3 passing and 1 failing piece, got %+v", report)
+	}
+}
+
+func TestVerifyPieces_UnmappedFile(t *testing.T) {
+	layout, paths := testLayout(t, t.TempDir(), 8, "0123456789", "abcdef")
+	delete(paths, 1)
+
+	report, err := VerifyPieces(context.Background(), layout, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Piece 0 is only the first file; piece 1 spans both
+	if report.Passed != 1 || report.Unchecked != 1 {
+		t.Errorf("Expected 1 passing and 1 unchecked piece, got %+v", report)
+	}
+}
+
+func TestVerifyPieces_ShortFile(t *testing.T) {
+	dir := t.TempDir()
+	layout, paths := testLayout(t, dir, 4, "01234567")
+	if err := os.WriteFile(paths[0], []byte("0123"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := VerifyPieces(context.Background(), layout, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Passed != 1 || report.Failed != 1 {
+		t.Errorf("Expected the missing tail to fail, got %+v", report)
+	}
+}
+
+func TestVerifyPieces_PartialDownload(t *testing.T) {
+	dir := t.TempDir()
+	layout, paths := testLayout(t, dir, 4, "0123456789ABCDEFGH", "xyz")
+	// An aborted download that stops inside the third piece
+	if err := os.WriteFile(paths[0], []byte("0123456789"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := VerifyPieces(context.Background(), layout, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Files[0].Pieces != 5 || report.Files[0].Passed != 2 {
+		t.Errorf("Expected 2 of 5 pieces to be salvaged, got %+v", report.Files[0])
+	}
+
+	matches := []Match{
+		{TorrentFile: layout.Files[0], Selected: &DiskFile{Path: paths[0]}, Partial: true},
+		{TorrentFile: layout.Files[1], Selected: &DiskFile{Path: paths[1]}, Partial: true},
+	}
+	report.Files[0].Passed = 0
+	if cleared := UnselectUnsalvaged(matches, report); cleared != 1 || matches[0].Selected != nil || matches[1].Selected == nil {
+		t.Errorf("Expected only the partial file without verified pieces to be unselected, got %d %+v", cleared, matches)
+	}
+}
+
+func TestVerifyPieces_HiddenPadFiles(t *testing.T) {
+	dir := t.TempDir()
+	// As created, the first file is padded to the 8 byte piece boundary
+	layout, paths := testLayout(t, dir, 8, "0123456789", "\x00\x00\x00\x00\x00\x00", "abc")
+	delete(paths, 1)
+
+	// qBittorrent doesn't list the pad file
+	layout.Files = []TorrentFileInfo{layout.Files[0], layout.Files[2]}
+	layout.restoreHiddenPadding()
+	if len(layout.Files) != 3 || !layout.Files[1].IsPad() || layout.Files[1].Size != 6 {
+		t.Fatalf("Expected the pad file to be restored, got %+v", layout.Files)
+	}
+
+	report, err := VerifyPieces(context.Background(), layout, paths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Passed != 3 || report.Unchecked != 0 {
+		t.Errorf("Expected 3 of 3 pieces to pass, got %+v", report)
+	}
+	if len(report.Files) != 2 || report.Files[1].Index != 2 {
+		t.Errorf("Expected pad file to be left out of the file counts, got %+v", report.Files)
+	}
+}
+
+func TestRestoreHiddenPadding_Unpadded(t *testing.T) {
+	layout, _ := testLayout(t, t.TempDir(), 8, "0123456789", "abc")
+	layout.restoreHiddenPadding()
+	if len(layout.Files) != 2 {
+		t.Errorf("Expected files to be left alone, got %+v", layout.Files)
+	}
+}
@@ -0,0 +1,93 @@
+package backend
+
+import (
+	"fmt"
+)
+
+// Match outcomes, also used as tag names when tagging processed torrents
+const (
+	OutcomeMatched     = "matched"
+	OutcomePartial     = "partial"
+	OutcomeNeedsReview = "needs-review"
+	OutcomeUnmatched   = "unmatched"
+)
+
+// ClassifyOutcome summarises a match for tagging:
+// matched when every file has a selected disk file, needs-review when some
+// files still have several candidates to choose from, partial when some files
+// matched and the rest have no candidates, and unmatched when nothing matched
+func ClassifyOutcome(matches []Match, unmatchedCount int) string {
+	selected := 0
+	ambiguous := 0
+	for _, m := range matches {
+		if m.Selected != nil {
+			selected++
+		} else if len(m.DiskFiles) > 0 {
+			ambiguous++
+		}
+	}
+
+	switch {
+	case ambiguous > 0:
+		return OutcomeNeedsReview
+	case selected == 0:
+		return OutcomeUnmatched
+	case unmatchedCount > 0 || selected < len(matches):
+		return OutcomePartial
+	default:
+		return OutcomeMatched
+	}
+}
+
+// PostProcessOptions controls what happens to a torrent after it was matched
+type PostProcessOptions struct {
+	TagOutcome bool     `json:"tagOutcome"` // add the outcome as a tag
+	Tags       []string `json:"tags"`       // extra tags added regardless of outcome
+	Category   string   `json:"category"`   // set only when every file matched
+	Resume     bool     `json:"resume"`     // resume only when every file matched
+}
+
+// TagsFor returns the tags to add for the given outcome
+func (o PostProcessOptions) TagsFor(outcome string) []string {
+	var tags []string
+	if o.TagOutcome && outcome != "" {
+		tags = append(tags, outcome)
+	}
+	for _, t := range o.Tags {
+		if t != "" {
+			tags = append(tags, t)
+		}
+	}
+	return tags
+}
+
+// PostProcess tags, categorizes and resumes a torrent based on its match outcome
+func (s *QBitService) PostProcess(hash string, outcome string, opts PostProcessOptions) error {
+	if s.client == nil {
+		return ErrNotConnected
+	}
+
+	if tags := opts.TagsFor(outcome); len(tags) > 0 {
+		if err := s.AddTags(hash, tags); err != nil {
+			return fmt.Errorf("failed to add tags: %w", err)
+		}
+	}
+
+	if outcome != OutcomeMatched {
+		return nil
+	}
+
+	if opts.Category != "" {
+		if err := s.SetCategory(hash, opts.Category); err != nil {
+			return fmt.Errorf("failed to set category: %w", err)
+		}
+	}
+
+	if opts.Resume {
+		if err := s.ResumeTorrent(hash); err != nil {
+			return fmt.Errorf("failed to resume torrent: %w", err)
+		}
+	}
+
+	return nil
+}
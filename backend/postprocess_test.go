@@ -0,0 +1,55 @@
+package backend
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestClassifyOutcome(t *testing.T) {
+	selected := &DiskFile{Path: "/a.mkv", Name: "a.mkv", Size: 1}
+	candidates := []DiskFile{{Path: "/a.mkv"}, {Path: "/b.mkv"}}
+
+	tests := []struct {
+		name      string
+		matches   []Match
+		unmatched int
+		expected  string
+	}{
+		{"all selected", []Match{{Selected: selected}, {Selected: selected}}, 0, OutcomeMatched},
+		{"some unmatched", []Match{{Selected: selected}}, 1, OutcomePartial},
+		{"ambiguous", []Match{{Selected: selected}, {DiskFiles: candidates}}, 0, OutcomeNeedsReview},
+		{"nothing matched", nil, 2, OutcomeUnmatched},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := ClassifyOutcome(tt.matches, tt.unmatched); result != tt.expected {
+				t.Errorf("ClassifyOutcome() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPostProcessOptions_TagsFor(t *testing.T) {
+	opts := PostProcessOptions{TagOutcome: true, Tags: []string{"qbt-file-matcher", ""}}
+
+	tags := opts.TagsFor(OutcomePartial)
+
+	if !slices.Equal(tags, []string{"partial", "qbt-file-matcher"}) {
+		t.Errorf("Unexpected tags: %v", tags)
+	}
+
+	opts.TagOutcome = false
+	if tags := opts.TagsFor(OutcomeMatched); !slices.Equal(tags, []string{"qbt-file-matcher"}) {
+		t.Errorf("Unexpected tags without outcome: %v", tags)
+	}
+}
+
+func TestQBitService_PostProcess_NotConnected(t *testing.T) {
+	service := &QBitService{}
+
+	err := service.PostProcess("somehash", OutcomeMatched, PostProcessOptions{TagOutcome: true})
+	if err == nil || err.Error() != "not connected" {
+		t.Errorf("Expected 'not connected' error, got: %v", err)
+	}
+}
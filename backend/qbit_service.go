@@ -1,14 +1,24 @@
 package backend
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/autobrr/go-qbittorrent"
 )
 
+// ErrNotConnected is returned by operations that need qBittorrent before
+// Connect succeeded
+var ErrNotConnected = errors.New("not connected")
+
 // QBitService handles qBittorrent operations
 type QBitService struct {
 	client *qbittorrent.Client
+
+	// JournalDir is where applied renames are journaled, empty keeps no
+	// journal
+	JournalDir string
 }
 
 // ConnectionConfig represents connection settings
@@ -16,19 +26,39 @@ type ConnectionConfig struct {
 	URL      string `json:"url"`
 	Username string `json:"username"`
 	Password string `json:"password"`
+
+	// Reverse proxy settings
+	CACertFile         string            `json:"caCertFile"` // PEM bundle trusted in addition to system roots
+	InsecureSkipVerify bool              `json:"insecureSkipVerify"`
+	ClientCertFile     string            `json:"clientCertFile"` // PEM client certificate
+	ClientKeyFile      string            `json:"clientKeyFile"`  // PEM key, defaults to ClientCertFile
+	BasicUsername      string            `json:"basicUsername"`  // HTTP basic auth
+	BasicPassword      string            `json:"basicPassword"`
+	Headers            map[string]string `json:"headers"` // extra headers sent with every request
 }
 
 // Connect connects to qBittorrent
 func (s *QBitService) Connect(config ConnectionConfig) error {
-	s.client = qbittorrent.NewClient(qbittorrent.Config{
-		Host:     config.URL,
-		Username: config.Username,
-		Password: config.Password,
+	client := qbittorrent.NewClient(qbittorrent.Config{
+		Host:          config.URL,
+		Username:      config.Username,
+		Password:      config.Password,
+		TLSSkipVerify: config.InsecureSkipVerify,
+		BasicUser:     config.BasicUsername,
+		BasicPass:     config.BasicPassword,
 	})
 
-	if err := s.client.Login(); err != nil {
+	// Always use our own client so API requests are instrumented
+	httpClient, err := newHTTPClient(config)
+	if err != nil {
+		return err
+	}
+	client = client.WithHTTPClient(httpClient)
+
+	if err := client.Login(); err != nil {
 		return fmt.Errorf("failed to connect: %w", err)
 	}
+	s.client = client
 	return nil
 }
 
@@ -46,7 +76,7 @@ func (s *QBitService) IsConnected() bool {
 // GetVersion returns the qBittorrent version
 func (s *QBitService) GetVersion() (string, error) {
 	if s.client == nil {
-		return "", fmt.Errorf("not connected")
+		return "", ErrNotConnected
 	}
 	return s.client.GetAppVersion()
 }
@@ -58,14 +88,18 @@ type TorrentInfo struct {
 	Size        int64   `json:"size"`
 	Progress    float64 `json:"progress"`
 	State       string  `json:"state"`
+	Category    string  `json:"category"`
 	SavePath    string  `json:"savePath"`
 	ContentPath string  `json:"contentPath"`
+	// DownloadPath is where incomplete files are kept, when qBittorrent
+	// keeps them apart from the save path
+	DownloadPath string `json:"downloadPath,omitempty"`
 }
 
 // GetTorrents returns all torrents
 func (s *QBitService) GetTorrents() ([]TorrentInfo, error) {
 	if s.client == nil {
-		return nil, fmt.Errorf("not connected")
+		return nil, ErrNotConnected
 	}
 
 	torrents, err := s.client.GetTorrents(qbittorrent.TorrentFilterOptions{})
@@ -75,32 +109,56 @@ func (s *QBitService) GetTorrents() ([]TorrentInfo, error) {
 
 	result := make([]TorrentInfo, len(torrents))
 	for i, t := range torrents {
-		result[i] = TorrentInfo{
-			Hash:        t.Hash,
-			Name:        t.Name,
-			Size:        t.Size,
-			Progress:    t.Progress,
-			State:       string(t.State),
-			SavePath:    t.SavePath,
-			ContentPath: t.ContentPath,
-		}
+		result[i] = torrentInfo(t)
 	}
 
 	return result, nil
 }
 
+// GetTorrent returns a single torrent
+func (s *QBitService) GetTorrent(hash string) (TorrentInfo, error) {
+	if s.client == nil {
+		return TorrentInfo{}, ErrNotConnected
+	}
+
+	torrents, err := s.client.GetTorrents(qbittorrent.TorrentFilterOptions{Hashes: []string{hash}})
+	if err != nil {
+		return TorrentInfo{}, err
+	}
+	if len(torrents) == 0 {
+		return TorrentInfo{}, fmt.Errorf("torrent %s not found", hash)
+	}
+	return torrentInfo(torrents[0]), nil
+}
+
+func torrentInfo(t qbittorrent.Torrent) TorrentInfo {
+	return TorrentInfo{
+		Hash:        t.Hash,
+		Name:        t.Name,
+		Size:        t.Size,
+		Progress:    t.Progress,
+		State:       string(t.State),
+		Category:    t.Category,
+		SavePath:    t.SavePath,
+		ContentPath: t.ContentPath,
+
+		DownloadPath: t.DownloadPath,
+	}
+}
+
 // TorrentFileInfo represents a file in a torrent for the frontend
 type TorrentFile struct {
 	Index    int     `json:"index"`
 	Name     string  `json:"name"`
 	Size     int64   `json:"size"`
 	Progress float64 `json:"progress"`
+	Priority int     `json:"priority"`
 }
 
 // GetTorrentFiles returns files for a specific torrent
 func (s *QBitService) GetTorrentFiles(hash string) ([]TorrentFile, error) {
 	if s.client == nil {
-		return nil, fmt.Errorf("not connected")
+		return nil, ErrNotConnected
 	}
 
 	files, err := s.client.GetFilesInformation(hash)
@@ -120,6 +178,7 @@ func (s *QBitService) GetTorrentFiles(hash string) ([]TorrentFile, error) {
 			Name:     f.Name,
 			Size:     f.Size,
 			Progress: float64(f.Progress),
+			Priority: f.Priority,
 		}
 	}
 
@@ -129,15 +188,23 @@ func (s *QBitService) GetTorrentFiles(hash string) ([]TorrentFile, error) {
 // RenameFile renames a file in qBittorrent
 func (s *QBitService) RenameFile(hash string, oldPath string, newPath string) error {
 	if s.client == nil {
-		return fmt.Errorf("not connected")
+		return ErrNotConnected
 	}
 	return s.client.RenameFile(hash, oldPath, newPath)
 }
 
+// RenameFolder renames a folder in qBittorrent, moving every file beneath it
+func (s *QBitService) RenameFolder(hash string, oldPath string, newPath string) error {
+	if s.client == nil {
+		return ErrNotConnected
+	}
+	return s.client.RenameFolder(hash, oldPath, newPath)
+}
+
 // SetTorrentLocation sets the download location for a torrent
 func (s *QBitService) SetTorrentLocation(hash string, location string) error {
 	if s.client == nil {
-		return fmt.Errorf("not connected")
+		return ErrNotConnected
 	}
 	return s.client.SetLocation([]string{hash}, location)
 }
@@ -147,7 +214,7 @@ func (s *QBitService) SetTorrentLocation(hash string, location string) error {
 // Priority: 0 = do not download, 1 = normal, 6 = high, 7 = maximum
 func (s *QBitService) SetFilePriority(hash string, fileIDs string, priority int) error {
 	if s.client == nil {
-		return fmt.Errorf("not connected")
+		return ErrNotConnected
 	}
 	return s.client.SetFilePriority(hash, fileIDs, priority)
 }
@@ -155,7 +222,36 @@ func (s *QBitService) SetFilePriority(hash string, fileIDs string, priority int)
 // RecheckTorrent triggers a hash recheck for the torrent
 func (s *QBitService) RecheckTorrent(hash string) error {
 	if s.client == nil {
-		return fmt.Errorf("not connected")
+		return ErrNotConnected
+	}
+	if err := s.client.Recheck([]string{hash}); err != nil {
+		metricRechecks.Inc("error")
+		return err
+	}
+	metricRechecks.Inc("ok")
+	return nil
+}
+
+// AddTags adds tags to a torrent, creating any that don't exist yet
+func (s *QBitService) AddTags(hash string, tags []string) error {
+	if s.client == nil {
+		return ErrNotConnected
+	}
+	return s.client.AddTags([]string{hash}, strings.Join(tags, ","))
+}
+
+// SetCategory sets the category of a torrent
+func (s *QBitService) SetCategory(hash string, category string) error {
+	if s.client == nil {
+		return ErrNotConnected
+	}
+	return s.client.SetCategory([]string{hash}, category)
+}
+
+// ResumeTorrent resumes (starts) a paused torrent
+func (s *QBitService) ResumeTorrent(hash string) error {
+	if s.client == nil {
+		return ErrNotConnected
 	}
-	return s.client.Recheck([]string{hash})
+	return s.client.Resume([]string{hash})
 }
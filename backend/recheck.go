@@ -0,0 +1,226 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/autobrr/go-qbittorrent"
+)
+
+// How often the torrent state is polled while waiting for a recheck, and how
+// long to wait for qBittorrent to start checking before assuming it finished
+var (
+	recheckPollInterval = time.Second
+	recheckStartGrace   = 5 * time.Second
+)
+
+// DefaultRecheckTimeout is used when WaitForRecheck is given no timeout
+const DefaultRecheckTimeout = 30 * time.Minute
+
+// RecheckFile represents the verification outcome of a single torrent file
+type RecheckFile struct {
+	Index    int     `json:"index"`
+	Name     string  `json:"name"`
+	Size     int64   `json:"size"`
+	Progress float64 `json:"progress"`
+	Complete bool    `json:"complete"`
+	Skipped  bool    `json:"skipped"` // priority 0 or a pad file, not expected to verify
+}
+
+// RecheckReport represents the result of waiting for a torrent recheck
+type RecheckReport struct {
+	Hash            string        `json:"hash"`
+	State           string        `json:"state"`
+	TimedOut        bool          `json:"timedOut"`
+	Files           []RecheckFile `json:"files"`
+	CompleteCount   int           `json:"completeCount"`
+	IncompleteCount int           `json:"incompleteCount"`
+}
+
+// Failed returns the files that were expected to verify but did not
+func (r RecheckReport) Failed() []RecheckFile {
+	var failed []RecheckFile
+	for _, f := range r.Files {
+		if !f.Complete && !f.Skipped {
+			failed = append(failed, f)
+		}
+	}
+	return failed
+}
+
+// IsCheckingState reports whether a qBittorrent state means the torrent is
+// still being verified
+func IsCheckingState(state string) bool {
+	return strings.HasPrefix(state, "checking") ||
+		state == "queuedForChecking" ||
+		state == string(qbittorrent.TorrentStateMoving)
+}
+
+// WaitForRecheck polls the torrent until qBittorrent finishes checking it,
+// then reports per-file progress. A timeout of 0 uses DefaultRecheckTimeout.
+func (s *QBitService) WaitForRecheck(hash string, timeoutSeconds int) (RecheckReport, error) {
+	return s.waitForRecheck(context.Background(), hash, timeoutSeconds)
+}
+
+// waitForRecheck is WaitForRecheck with cancellation
+func (s *QBitService) waitForRecheck(ctx context.Context, hash string, timeoutSeconds int) (RecheckReport, error) {
+	if s.client == nil {
+		return RecheckReport{}, ErrNotConnected
+	}
+
+	timeout := time.Duration(timeoutSeconds) * time.Second
+	if timeout <= 0 {
+		timeout = DefaultRecheckTimeout
+	}
+	start := time.Now()
+	deadline := start.Add(timeout)
+	graceEnd := time.Now().Add(recheckStartGrace)
+
+	report := RecheckReport{Hash: hash}
+	started := false
+
+	for {
+		torrents, err := s.client.GetTorrents(qbittorrent.TorrentFilterOptions{Hashes: []string{hash}})
+		if err != nil {
+			return report, err
+		}
+		if len(torrents) == 0 {
+			return report, fmt.Errorf("torrent %s not found", hash)
+		}
+
+		report.State = string(torrents[0].State)
+		if IsCheckingState(report.State) {
+			started = true
+		} else if started || time.Now().After(graceEnd) {
+			break
+		}
+
+		if time.Now().After(deadline) {
+			report.TimedOut = true
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return report, ctx.Err()
+		case <-time.After(recheckPollInterval):
+		}
+	}
+
+	files, err := s.GetTorrentFiles(hash)
+	if err != nil {
+		return report, err
+	}
+
+	metricRecheckDuration.ObserveSince(start)
+	addRecheckFiles(&report, files)
+	metricRecheckFiles.Add(float64(report.CompleteCount), "complete")
+	metricRecheckFiles.Add(float64(report.IncompleteCount), "incomplete")
+	return report, nil
+}
+
+// addRecheckFiles reports the verification outcome of each torrent file
+func addRecheckFiles(report *RecheckReport, files []TorrentFile) {
+	report.Files = make([]RecheckFile, len(files))
+	for i, f := range files {
+		rf := RecheckFile{
+			Index:    f.Index,
+			Name:     f.Name,
+			Size:     f.Size,
+			Progress: f.Progress,
+			Complete: f.Progress >= 1 || f.Size == 0, // empty files have nothing to verify
+			Skipped:  f.Priority == 0 || IsPadPath(f.Name),
+		}
+		report.Files[i] = rf
+
+		switch {
+		case rf.Skipped:
+		case rf.Complete:
+			report.CompleteCount++
+		default:
+			report.IncompleteCount++
+		}
+	}
+}
+
+// recheckSummary describes a recheck result for notifications
+func recheckSummary(report RecheckReport) string {
+	summary := fmt.Sprintf("%d files complete, %d incomplete", report.CompleteCount, report.IncompleteCount)
+	if report.TimedOut {
+		summary += fmt.Sprintf(", timed out in state %s", report.State)
+	}
+	for _, f := range report.Failed() {
+		summary += fmt.Sprintf("\n%s (%.1f%%)", f.Name, f.Progress*100)
+	}
+	return summary
+}
+
+// RevertRenames returns the operations that undo the renames of files which
+// failed verification, as these were most likely matched to the wrong file.
+// Files moved by a folder rename are renamed back individually so verified
+// files in the same folder stay where they are. Partial downloads that
+// verified some pieces are kept, as qBittorrent downloads the rest.
+func RevertRenames(renames []RenameOperation, report RecheckReport) []RenameOperation {
+	var reverts []RenameOperation
+
+	for _, f := range report.Failed() {
+		for _, r := range renames {
+			if r.IsFolder {
+				if !strings.HasPrefix(f.Name, r.NewPath+"/") {
+					continue
+				}
+				original := r.OldPath + "/" + strings.TrimPrefix(f.Name, r.NewPath+"/")
+				reverts = append(reverts, RenameOperation{
+					OldPath:     f.Name,
+					NewPath:     original,
+					TorrentFile: TorrentFileInfo{Index: f.Index, Name: original, Size: f.Size},
+				})
+				break
+			}
+
+			if r.NewPath == f.Name {
+				if r.Partial && f.Progress > 0 {
+					break
+				}
+				reverts = append(reverts, RenameOperation{
+					OldPath:     r.NewPath,
+					NewPath:     r.OldPath,
+					TorrentFile: r.TorrentFile,
+					DiskFile:    r.DiskFile,
+				})
+				break
+			}
+		}
+	}
+
+	return reverts
+}
+
+// JournalReverts returns the operations that undo the journaled renames of
+// files which fail verification now, as RevertRenames does. The torrent
+// should have been rechecked since the renames were applied.
+func (s *QBitService) JournalReverts(journal *MoveJournal) ([]RenameOperation, RecheckReport, error) {
+	report := RecheckReport{Hash: journal.Hash}
+	if journal.Undone {
+		return nil, report, fmt.Errorf("journal %s was already undone", journal.ID)
+	}
+	if !journal.IsRenames() {
+		return nil, report, fmt.Errorf("journal %s holds no renames", journal.ID)
+	}
+	torrent, err := s.GetTorrent(journal.Hash)
+	if err != nil {
+		return nil, report, err
+	}
+	report.State = torrent.State
+	if IsCheckingState(torrent.State) {
+		return nil, report, fmt.Errorf("torrent %s is still being checked", torrent.Name)
+	}
+	files, err := s.GetTorrentFiles(journal.Hash)
+	if err != nil {
+		return nil, report, err
+	}
+	addRecheckFiles(&report, files)
+	return RevertRenames(journal.Renames, report), report, nil
+}
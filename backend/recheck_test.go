@@ -0,0 +1,93 @@
+package backend
+
+import (
+	"testing"
+)
+
+func TestIsCheckingState(t *testing.T) {
+	tests := []struct {
+		state    string
+		expected bool
+	}{
+		{"checkingDL", true},
+		{"checkingUP", true},
+		{"checkingResumeData", true},
+		{"moving", true},
+		{"stalledUP", false},
+		{"pausedDL", false},
+		{"missingFiles", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.state, func(t *testing.T) {
+			if result := IsCheckingState(tt.state); result != tt.expected {
+				t.Errorf("IsCheckingState(%q) = %v, want %v", tt.state, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRecheckReport_Failed(t *testing.T) {
+	report := RecheckReport{
+		Files: []RecheckFile{
+			{Index: 0, Name: "a.mkv", Complete: true, Progress: 1},
+			{Index: 1, Name: "b.mkv", Progress: 0.5},
+			{Index: 2, Name: "c.nfo", Skipped: true},
+		},
+	}
+
+	failed := report.Failed()
+
+	if len(failed) != 1 || failed[0].Name != "b.mkv" {
+		t.Errorf("Expected only b.mkv to fail, got %+v", failed)
+	}
+}
+
+func TestRevertRenames(t *testing.T) {
+	renames := []RenameOperation{
+		{OldPath: "a.mkv", NewPath: "Movies/a.mkv", TorrentFile: TorrentFileInfo{Index: 0, Name: "a.mkv"}},
+		{OldPath: "b.mkv", NewPath: "Movies/b.mkv", TorrentFile: TorrentFileInfo{Index: 1, Name: "b.mkv"}},
+		{OldPath: "Pack", NewPath: "Library/Pack", IsFolder: true, FileCount: 2},
+	}
+	report := RecheckReport{
+		Files: []RecheckFile{
+			{Index: 0, Name: "Movies/a.mkv", Complete: true, Progress: 1},
+			{Index: 1, Name: "Movies/b.mkv", Progress: 0.2},
+			{Index: 2, Name: "Library/Pack/1.mkv", Complete: true, Progress: 1},
+			{Index: 3, Name: "Library/Pack/2.mkv", Progress: 0},
+			{Index: 4, Name: "untouched.nfo", Progress: 0},
+		},
+	}
+
+	reverts := RevertRenames(renames, report)
+
+	if len(reverts) != 2 {
+		t.Fatalf("Expected 2 reverts, got %d: %+v", len(reverts), reverts)
+	}
+	if reverts[0].OldPath != "Movies/b.mkv" || reverts[0].NewPath != "b.mkv" {
+		t.Errorf("Unexpected file revert %s -> %s", reverts[0].OldPath, reverts[0].NewPath)
+	}
+	if reverts[1].IsFolder {
+		t.Error("Expected folder members to be reverted individually")
+	}
+	if reverts[1].OldPath != "Library/Pack/2.mkv" || reverts[1].NewPath != "Pack/2.mkv" {
+		t.Errorf("Unexpected folder member revert %s -> %s", reverts[1].OldPath, reverts[1].NewPath)
+	}
+}
+
+func TestRevertRenames_KeepsPartialDownloads(t *testing.T) {
+	renames := []RenameOperation{
+		{OldPath: "a.mkv", NewPath: "incomplete/a.mkv", Partial: true},
+		{OldPath: "b.mkv", NewPath: "incomplete/b.mkv", Partial: true},
+	}
+	report := RecheckReport{Files: []RecheckFile{
+		{Index: 0, Name: "incomplete/a.mkv", Progress: 0.4},
+		{Index: 1, Name: "incomplete/b.mkv", Progress: 0},
+	}}
+
+	reverts := RevertRenames(renames, report)
+
+	if len(reverts) != 1 || reverts[0].OldPath != "incomplete/b.mkv" {
+		t.Errorf("Expected only the partial file without verified pieces to be reverted, got %+v", reverts)
+	}
+}
@@ -0,0 +1,258 @@
+package backend
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+)
+
+// DefaultRulesProfile is the profile used when none is named
+const DefaultRulesProfile = "default"
+
+// Rule maps torrent file paths to the path the file is expected at on disk,
+// for mappings known in advance, e.g. "strip the release group" or "folder X
+// lives at Library/Y (Year)". Pattern is matched against the torrent path,
+// with "/" separators. With Replace, the matched part is replaced, with $1
+// or ${name} expanding to submatches. With Template, the whole path is
+// rendered from a RuleContext instead.
+type Rule struct {
+	Name     string `json:"name"`
+	Pattern  string `json:"pattern"`
+	Replace  string `json:"replace,omitempty"`
+	Template string `json:"template,omitempty"`
+}
+
+// RuleContext is what a rule template is rendered with
+type RuleContext struct {
+	Path   string            // the torrent path
+	Dir    string            // its folder, "." for a file at the top
+	Folder string            // the torrent's top folder, empty for single files
+	Base   string            // the file name
+	Name   string            // the file name without its extension
+	Ext    string            // the extension, with its dot
+	Groups map[string]string // submatches of Pattern, by name and by number
+	MediaInfo
+}
+
+// ruleFuncs are available in rule templates
+var ruleFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+	"trim":  strings.TrimSpace,
+	// clean turns dots and underscores into spaces, e.g. for folder names
+	// "Movie.Name.2019" -> "Movie Name 2019"
+	"clean": func(s string) string {
+		return strings.Join(strings.Fields(strings.NewReplacer(".", " ", "_", " ").Replace(s)), " ")
+	},
+}
+
+type compiledRule struct {
+	Rule
+	re   *regexp.Regexp
+	tmpl *template.Template
+}
+
+// RuleSet is a compiled list of rules, tried in order
+type RuleSet struct {
+	Profile string
+	rules   []compiledRule
+}
+
+// CompileRules checks and compiles rules
+func CompileRules(profile string, rules []Rule) (*RuleSet, error) {
+	set := &RuleSet{Profile: profile}
+	for i, r := range rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("rule %d", i+1)
+			r.Name = name
+		}
+		if (r.Replace == "") == (r.Template == "") {
+			return nil, fmt.Errorf("%s: set either replace or template", name)
+		}
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid pattern: %w", name, err)
+		}
+		c := compiledRule{Rule: r, re: re}
+		if r.Template != "" {
+			if c.tmpl, err = template.New(name).Funcs(ruleFuncs).Option("missingkey=error").Parse(r.Template); err != nil {
+				return nil, fmt.Errorf("%s: invalid template: %w", name, err)
+			}
+		}
+		set.rules = append(set.rules, c)
+	}
+	return set, nil
+}
+
+// Len returns the number of rules
+func (s *RuleSet) Len() int {
+	if s == nil {
+		return 0
+	}
+	return len(s.rules)
+}
+
+// Apply returns the disk path the first matching rule maps a torrent path
+// to, and that rule's name. A relative path is looked for under any scanned
+// folder. ok is false when no rule matches.
+func (s *RuleSet) Apply(torrentPath string) (mapped string, rule string, ok bool, err error) {
+	if s == nil {
+		return "", "", false, nil
+	}
+	for _, r := range s.rules {
+		match := r.re.FindStringSubmatchIndex(torrentPath)
+		if match == nil {
+			continue
+		}
+		if r.tmpl == nil {
+			mapped = r.re.ReplaceAllString(torrentPath, r.Replace)
+		} else {
+			var b strings.Builder
+			if err := r.tmpl.Execute(&b, newRuleContext(torrentPath, r.re, match)); err != nil {
+				return "", r.Name, false, fmt.Errorf("%s: %w", r.Name, err)
+			}
+			mapped = b.String()
+		}
+		mapped = path.Clean(strings.TrimSpace(filepath.ToSlash(mapped)))
+		if mapped == "." || mapped == "/" {
+			return "", r.Name, false, fmt.Errorf("%s: maps %s to an empty path", r.Name, torrentPath)
+		}
+		return mapped, r.Name, true, nil
+	}
+	return "", "", false, nil
+}
+
+func newRuleContext(torrentPath string, re *regexp.Regexp, match []int) RuleContext {
+	base := path.Base(torrentPath)
+	ctx := RuleContext{
+		Path:      torrentPath,
+		Dir:       path.Dir(torrentPath),
+		Base:      base,
+		Ext:       path.Ext(base),
+		Name:      strings.TrimSuffix(base, path.Ext(base)),
+		Groups:    make(map[string]string),
+		MediaInfo: ParseMediaInfo(torrentPath),
+	}
+	if i := strings.Index(torrentPath, "/"); i > 0 {
+		ctx.Folder = torrentPath[:i]
+	}
+	for i, name := range re.SubexpNames() {
+		if match[2*i] < 0 {
+			continue
+		}
+		group := torrentPath[match[2*i]:match[2*i+1]]
+		ctx.Groups[fmt.Sprint(i)] = group
+		if name != "" {
+			ctx.Groups[name] = group
+		}
+	}
+	return ctx
+}
+
+// ruleCandidates returns the files of the right size at the path a rule
+// maps a torrent file to, or the error of a rule that failed to map it
+func (s *RuleSet) ruleCandidates(tf TorrentFileInfo, sameSize []DiskFile) ([]DiskFile, string, error) {
+	mapped, rule, ok, err := s.Apply(tf.Name)
+	if err != nil {
+		return nil, rule, fmt.Errorf("%s: %w", tf.Name, err)
+	}
+	if !ok {
+		return nil, "", nil
+	}
+	var candidates []DiskFile
+	for _, c := range sameSize {
+		if hasPathSuffix(c.Path, mapped) {
+			candidates = append(candidates, c)
+		}
+	}
+	return candidates, rule, nil
+}
+
+// hasPathSuffix reports whether a disk path is mapped, when absolute, or
+// ends with it at a folder boundary, comparing names the way this platform
+// does
+func hasPathSuffix(diskPath string, mapped string) bool {
+	absolute := path.IsAbs(mapped) || filepath.IsAbs(filepath.FromSlash(mapped))
+	diskPath = filepath.ToSlash(pathKey(diskPath))
+	mapped = filepath.ToSlash(pathKey(filepath.FromSlash(mapped)))
+	if absolute {
+		return diskPath == mapped
+	}
+	return diskPath == mapped || strings.HasSuffix(diskPath, "/"+mapped)
+}
+
+// RulesConfig holds rule profiles, e.g. one per library or tracker
+type RulesConfig struct {
+	Profiles map[string][]Rule `json:"profiles"`
+}
+
+// DefaultRulesPath returns where rule profiles are kept
+func DefaultRulesPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "qbt-file-matcher", "rules.json"), nil
+}
+
+// LoadRulesConfig reads rule profiles. A missing file has no profiles.
+func LoadRulesConfig(path string) (RulesConfig, error) {
+	config := RulesConfig{Profiles: map[string][]Rule{}}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, fmt.Errorf("invalid rules file %s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ProfileNames returns the profile names, sorted
+func (c RulesConfig) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Compile compiles a profile's rules
+func (c RulesConfig) Compile(profile string) (*RuleSet, error) {
+	if profile == "" {
+		profile = DefaultRulesProfile
+	}
+	rules, ok := c.Profiles[profile]
+	if !ok {
+		return nil, fmt.Errorf("no rules profile %q", profile)
+	}
+	return CompileRules(profile, rules)
+}
+
+// LoadRules compiles a profile from the rules file at path, or from the
+// default rules file when path is empty
+func LoadRules(path string, profile string) (*RuleSet, error) {
+	if path == "" {
+		var err error
+		if path, err = DefaultRulesPath(); err != nil {
+			return nil, err
+		}
+	}
+	config, err := LoadRulesConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	return config.Compile(profile)
+}
@@ -0,0 +1,133 @@
+package backend
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRuleSet_Apply(t *testing.T) {
+	rules, err := CompileRules("test", []Rule{
+		{Name: "library", Pattern: `^Movie\.Name\.(?P<year>\d{4})[^/]*/`, Template: "Library/{{clean .Folder | printf \"%.10s\"}} ({{.Groups.year}})/{{.Name}}{{lower .Ext}}"},
+		{Name: "strip group", Pattern: `-[A-Za-z0-9]+(\.\w+)$`, Replace: "$1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		torrentPath string
+		expected    string
+		rule        string
+	}{
+		{"Movie.Name.2019.1080p-GRP/Movie.Name.2019.1080p-GRP.MKV", "Library/Movie Name (2019)/Movie.Name.2019.1080p-GRP.mkv", "library"},
+		{"Show/Show.S01E01-GRP.mkv", "Show/Show.S01E01.mkv", "strip group"},
+		{"Show/plain.mkv", "", ""},
+	}
+	for _, tt := range tests {
+		mapped, rule, ok, err := rules.Apply(tt.torrentPath)
+		if err != nil || ok != (tt.expected != "") || mapped != tt.expected || rule != tt.rule {
+			t.Errorf("Apply(%q) = %q, %q, %v, %v; expected %q by %q", tt.torrentPath, mapped, rule, ok, err, tt.expected, tt.rule)
+		}
+	}
+}
+
+func TestCompileRules_Invalid(t *testing.T) {
+	for _, r := range []Rule{
+		{Pattern: `(`, Replace: "x"},
+		{Pattern: `x`},
+		{Pattern: `x`, Replace: "a", Template: "b"},
+		{Pattern: `x`, Template: "{{.Missing"},
+	} {
+		if _, err := CompileRules("test", []Rule{r}); err == nil {
+			t.Errorf("Expected error for %+v", r)
+		}
+	}
+}
+
+func TestFindMatchesWithOptions_Rules(t *testing.T) {
+	rules, err := CompileRules("test", []Rule{{Pattern: `^Film\.(\d{4})-GRP/.*$`, Replace: "Library/Film ($1)/Film.mkv"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	torrentFiles := []TorrentFileInfo{{Index: 0, Name: "Film.2019-GRP/Film.2019-GRP.mkv", Size: 100}}
+	diskFiles := []DiskFile{
+		// Closer by name, but not where the rule says the file lives
+		{Path: filepath.Join("/data", "downloads", "Film.2019-GRP.mkv"), Name: "Film.2019-GRP.mkv", Size: 100},
+		{Path: filepath.Join("/data", "Library", "Film (2019)", "Film.mkv"), Name: "Film.mkv", Size: 100},
+	}
+
+	result := FindMatchesWithOptions(torrentFiles, diskFiles, MatchOptions{RequireSameExtension: true, Rules: rules})
+
+	match := result.Matches[0]
+	if match.Selected == nil || match.Selected.Name != "Film.mkv" || match.Rule != "rule 1" || len(match.DiskFiles) != 1 || !match.Exact {
+		t.Errorf("Expected the file the rule maps to, got %+v", match)
+	}
+
+	// A mapped file of another size falls back to size matching
+	diskFiles[1].Size = 99
+	result = FindMatchesWithOptions(torrentFiles, diskFiles, MatchOptions{RequireSameExtension: true, Rules: rules})
+	if match := result.Matches[0]; match.Rule != "" || match.Selected == nil || match.Selected.Name != "Film.2019-GRP.mkv" {
+		t.Errorf("Expected size matching without a mapped file, got %+v", match)
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"rules.json": `{"profiles": {"movies": [{"pattern": "x", "replace": "y"}]}}`})
+
+	rules, err := LoadRules(filepath.Join(dir, "rules.json"), "movies")
+	if err != nil || rules.Len() != 1 {
+		t.Fatalf("Expected 1 rule, got %v (%v)", rules, err)
+	}
+	if _, err := LoadRules(filepath.Join(dir, "rules.json"), "tv"); err == nil {
+		t.Error("Expected error for an unknown profile")
+	}
+	if _, err := LoadRules(filepath.Join(dir, "missing.json"), ""); err == nil {
+		t.Error("Expected error for the default profile of a missing file")
+	}
+}
+
+func TestFindMatchesWithOptions_RuleErrors(t *testing.T) {
+	rules, err := CompileRules("test", []Rule{{Name: "year", Pattern: `^Film`, Template: "Library/{{.Groups.year}}/{{.Base}}"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	torrentFiles := []TorrentFileInfo{{Index: 0, Name: "Film.mkv", Size: 100}}
+	diskFiles := []DiskFile{{Path: filepath.Join("/data", "Film.mkv"), Name: "Film.mkv", Size: 100}}
+
+	result := FindMatchesWithOptions(torrentFiles, diskFiles, MatchOptions{Rules: rules})
+	if len(result.RuleErrors) != 1 || !strings.Contains(result.RuleErrors[0], "Film.mkv: year") {
+		t.Errorf("Expected the failed rule to be reported, got %v", result.RuleErrors)
+	}
+	if result.MatchedCount != 1 {
+		t.Errorf("Expected size matching after the failed rule, got %d matched", result.MatchedCount)
+	}
+}
+
+func TestHasPathSuffix(t *testing.T) {
+	tests := []struct {
+		diskPath        string
+		mapped          string
+		caseInsensitive bool
+		expected        bool
+	}{
+		{"/data/Library/Film/Film.mkv", "Library/Film/Film.mkv", false, true},
+		{"/data/MyLibrary/Film/Film.mkv", "Library/Film/Film.mkv", false, false},
+		{"/data/library/film/Film.mkv", "Library/Film/Film.mkv", false, false},
+		{"/data/library/film/Film.mkv", "Library/Film/Film.mkv", true, true},
+		{"/data/Library/Film.mkv", "/data/Library/Film.mkv", false, true},
+		{"/other/data/Library/Film.mkv", "/data/Library/Film.mkv", false, false},
+	}
+	for _, tt := range tests {
+		setPathPlatform(t, tt.caseInsensitive, false)
+		if got := hasPathSuffix(filepath.FromSlash(tt.diskPath), tt.mapped); got != tt.expected {
+			t.Errorf("hasPathSuffix(%q, %q) with case-insensitive %v = %v, expected %v", tt.diskPath, tt.mapped, tt.caseInsensitive, got, tt.expected)
+		}
+	}
+
+	setPathPlatform(t, false, true)
+	if !hasPathSuffix("/data/"+cafeNFD+"/Film.mkv", cafeNFC+"/Film.mkv") {
+		t.Error("Expected NFD and NFC folder names to match where the file system treats them alike")
+	}
+}
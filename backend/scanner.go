@@ -1,9 +1,11 @@
 package backend
 
 import (
+	"context"
 	"log"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // DiskFile represents a file on the disk
@@ -11,14 +13,42 @@ type DiskFile struct {
 	Path string `json:"path"`
 	Name string `json:"name"`
 	Size int64  `json:"size"`
+
+	// Archive and Entry locate a file that only exists inside a zip or tar
+	// archive; Path is then where it would be extracted. See ScanArchives.
+	Archive string `json:"archive,omitempty"`
+	Entry   string `json:"entry,omitempty"`
+
+	// Parts are the disk file ranges a file that has to be joined or cut
+	// out of other files is rebuilt from; Path is then where it would be
+	// written. See IsReassembled.
+	Parts []FileSegment `json:"parts,omitempty"`
+
+	// Fingerprint samples the file's content, see FingerprintFiles. Copies
+	// lists identical files the matcher collapsed into this one.
+	Fingerprint string   `json:"fingerprint,omitempty"`
+	Copies      []string `json:"copies,omitempty"`
 }
 
+// How many files are scanned between progress callbacks
+const scanProgressInterval = 1000
+
 // ScanDirectory scans a directory recursively and returns all files with their sizes
 func ScanDirectory(root string) ([]DiskFile, error) {
+	return ScanDirectoryContext(context.Background(), root, nil)
+}
+
+// ScanDirectoryContext is ScanDirectory with cancellation. progress, when
+// set, is called periodically with the number of files found so far.
+func ScanDirectoryContext(ctx context.Context, root string, progress func(found int)) ([]DiskFile, error) {
 	var files []DiskFile
 	var skippedCount int
+	start := time.Now()
 
 	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
 		if err != nil {
 			// Log and skip files/directories we can't access
 			skippedCount++
@@ -32,15 +62,23 @@ func ScanDirectory(root string) ([]DiskFile, error) {
 				Name: info.Name(),
 				Size: info.Size(),
 			})
+			if progress != nil && len(files)%scanProgressInterval == 0 {
+				progress(len(files))
+			}
 		}
 
 		return nil
 	})
 
 	if err != nil {
+		metricScans.Inc("error")
 		return nil, err
 	}
 
+	metricScans.Inc("ok")
+	metricScanFiles.Add(float64(len(files)))
+	metricScanDuration.ObserveSince(start)
+
 	if skippedCount > 0 {
 		log.Printf("Skipped %d inaccessible files/directories during scan", skippedCount)
 	}
@@ -56,3 +94,43 @@ func GroupFilesBySize(files []DiskFile) map[int64][]DiskFile {
 	}
 	return sizeMap
 }
+
+// DuplicateSizes returns the sizes shared by more than one file, which size
+// alone can't tell apart
+func DuplicateSizes(files []DiskFile) map[int64]bool {
+	counts := make(map[int64]int)
+	for _, f := range files {
+		counts[f.Size]++
+	}
+	sizes := make(map[int64]bool)
+	for size, n := range counts {
+		if n > 1 && size > 0 {
+			sizes[size] = true
+		}
+	}
+	return sizes
+}
+
+// FingerprintFiles fingerprints the files with one of the given sizes,
+// through the cache, and returns how many were fingerprinted. Files that
+// can't be read are left without a fingerprint.
+func FingerprintFiles(ctx context.Context, files []DiskFile, sizes map[int64]bool, cache *FingerprintCache) (int, error) {
+	count := 0
+	for i := range files {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		f := &files[i]
+		if !sizes[f.Size] || f.IsArchiveEntry() || f.IsReassembled() {
+			continue
+		}
+		fingerprint, err := cache.Fingerprint(f.Path)
+		if err != nil {
+			log.Printf("Failed to fingerprint %s: %v", f.Path, err)
+			continue
+		}
+		f.Fingerprint = fingerprint
+		count++
+	}
+	return count, cache.Save()
+}
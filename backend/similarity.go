@@ -0,0 +1,202 @@
+package backend
+
+import (
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// A same-size candidate is auto-selected by name similarity when it scores at
+// least fuzzyAutoSelectScore and beats the runner-up by fuzzyAutoSelectMargin
+const (
+	fuzzyAutoSelectScore  = 0.85
+	fuzzyAutoSelectMargin = 0.1
+)
+
+// partialMinScore is the name similarity a smaller file needs to be offered
+// as a partial download of a torrent file
+const partialMinScore = 0.6
+
+// Tokenize splits a file name into lower-case words, dropping the extension
+// and separators such as dots, underscores, dashes and brackets, so
+// "Show.Name.S01E02-GRP.mkv" and "show name s01e02.mkv" share their tokens.
+// Names are put in composed Unicode form first, as macOS decomposes them.
+func Tokenize(name string) []string {
+	name = normalizeName(name)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	return strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// NameSimilarity scores how alike a torrent file path and a disk file path
+// are, from 0 to 1. Basenames are compared, and when the torrent file lives
+// in a folder its parent directory is weighed in as well.
+func NameSimilarity(torrentPath string, diskPath string) float64 {
+	torrentPath = filepath.ToSlash(torrentPath)
+	diskPath = filepath.ToSlash(diskPath)
+
+	score := stringSimilarity(path.Base(torrentPath), path.Base(diskPath))
+
+	torrentDir := path.Dir(torrentPath)
+	if torrentDir == "." {
+		return score
+	}
+	dirScore := stringSimilarity(path.Base(torrentDir), path.Base(path.Dir(diskPath)))
+	return 0.75*score + 0.25*dirScore
+}
+
+// stringSimilarity is the better of the token set ratio, which ignores extra
+// words such as release group tags, and the Jaro-Winkler similarity of the
+// normalised names, which tolerates small spelling differences
+func stringSimilarity(a, b string) float64 {
+	tokensA, tokensB := Tokenize(a), Tokenize(b)
+	if len(tokensA) == 0 || len(tokensB) == 0 {
+		return 0
+	}
+	return max(tokenSetRatio(tokensA, tokensB), jaroWinkler(strings.Join(tokensA, " "), strings.Join(tokensB, " ")))
+}
+
+// tokenSetRatio compares the shared tokens with each side's full token set.
+// When one name's tokens are a subset of the other's, the ratio is 1.
+func tokenSetRatio(a, b []string) float64 {
+	setA, setB := tokenSet(a), tokenSet(b)
+
+	var common, onlyA, onlyB []string
+	for t := range setA {
+		if setB[t] {
+			common = append(common, t)
+		} else {
+			onlyA = append(onlyA, t)
+		}
+	}
+	for t := range setB {
+		if !setA[t] {
+			onlyB = append(onlyB, t)
+		}
+	}
+	sort.Strings(common)
+	sort.Strings(onlyA)
+	sort.Strings(onlyB)
+
+	joinedCommon := strings.Join(common, " ")
+	withA := strings.TrimSpace(joinedCommon + " " + strings.Join(onlyA, " "))
+	withB := strings.TrimSpace(joinedCommon + " " + strings.Join(onlyB, " "))
+
+	best := levenshteinRatio(withA, withB)
+	if len(common) > 0 {
+		best = max(best, levenshteinRatio(joinedCommon, withA), levenshteinRatio(joinedCommon, withB))
+	}
+	return best
+}
+
+func tokenSet(tokens []string) map[string]bool {
+	set := make(map[string]bool, len(tokens))
+	for _, t := range tokens {
+		set[t] = true
+	}
+	return set
+}
+
+// levenshteinRatio is 1 minus the edit distance relative to the longer string
+func levenshteinRatio(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := max(len(ra), len(rb))
+	if longest == 0 {
+		return 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return 1 - float64(prev[len(rb)])/float64(longest)
+}
+
+// jaroWinkler returns the Jaro-Winkler similarity of two strings
+func jaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+	if slices.Equal(ra, rb) {
+		return 1
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	window = max(window, 0)
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+
+	matches := 0
+	for i := range ra {
+		for j := max(0, i-window); j < min(len(rb), i+window+1); j++ {
+			if !matchedB[j] && ra[i] == rb[j] {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions := 0
+	j := 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
+
+// rankCandidates sorts candidates by name similarity and metadata agreement
+// with the torrent file, best first, and returns their scores in the same order
+func rankCandidates(tf TorrentFileInfo, candidates []DiskFile) ([]DiskFile, []float64) {
+	ranked := slices.Clone(candidates)
+	scores := make(map[string]float64, len(ranked))
+	for _, c := range ranked {
+		scores[c.Path] = MatchScore(tf.Name, c.Path)
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return scores[ranked[i].Path] > scores[ranked[j].Path]
+	})
+
+	ordered := make([]float64, len(ranked))
+	for i, c := range ranked {
+		ordered[i] = scores[c.Path]
+	}
+	return ranked, ordered
+}
@@ -0,0 +1,60 @@
+package backend
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := map[string][]string{
+		"Show.Name.S01E02-GRP.mkv":     {"show", "name", "s01e02", "grp"},
+		"show name s01e02.mkv":         {"show", "name", "s01e02"},
+		"[Group] Anime_Title - 01.mkv": {"group", "anime", "title", "01"},
+		"Café.Noir.2019":               {"café", "noir"},
+		cafeNFD + ".Noir.2019":         {"café", "noir"},
+	}
+	for name, expected := range tests {
+		if result := Tokenize(name); !slices.Equal(result, expected) {
+			t.Errorf("Tokenize(%q) = %v, want %v", name, result, expected)
+		}
+	}
+}
+
+func TestJaroWinkler(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected float64
+	}{
+		{"martha", "marhta", 0.961},
+		{"dixon", "dicksonx", 0.813},
+		{"same", "same", 1},
+		{"abc", "xyz", 0},
+	}
+	for _, tt := range tests {
+		if result := jaroWinkler(tt.a, tt.b); math.Abs(result-tt.expected) > 0.001 {
+			t.Errorf("jaroWinkler(%q, %q) = %.3f, want %.3f", tt.a, tt.b, result, tt.expected)
+		}
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	// Release group tags and separators don't matter
+	if score := NameSimilarity("Show.S01E02.1080p-GRP.mkv", "/tv/show s01e02 1080p.mkv"); score < 0.99 {
+		t.Errorf("Expected subset tokens to score 1, got %.2f", score)
+	}
+
+	// A different episode scores lower than the right one
+	right := NameSimilarity("Show.S01E02.mkv", "/tv/Show S01E02.mkv")
+	wrong := NameSimilarity("Show.S01E02.mkv", "/tv/Show S01E03.mkv")
+	if wrong >= right {
+		t.Errorf("Expected %.2f < %.2f", wrong, right)
+	}
+
+	// The parent directory breaks ties between identical basenames
+	inSeason := NameSimilarity("Show S01/e01.mkv", "/tv/Show S01/e01.mkv")
+	elsewhere := NameSimilarity("Show S01/e01.mkv", "/tv/Other S04/e01.mkv")
+	if elsewhere >= inSeason {
+		t.Errorf("Expected matching parent directory to score higher, got %.2f >= %.2f", elsewhere, inSeason)
+	}
+}
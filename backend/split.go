@@ -0,0 +1,390 @@
+package backend
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// maxSplitFiles caps how many torrent files one disk file is tried as the
+// concatenation of, e.g. the VOBs of a title set
+const maxSplitFiles = 64
+
+// partPattern matches numbered parts such as "movie.mkv.001"
+var partPattern = regexp.MustCompile(`^(.+)\.(\d{3})$`)
+
+// FileSegment is a byte range of a disk file
+type FileSegment struct {
+	Path   string `json:"path"`
+	Offset int64  `json:"offset"`
+	Size   int64  `json:"size"` // -1 for the rest of the file
+}
+
+// IsReassembled reports whether the file has to be rebuilt from parts of
+// other disk files before qBittorrent can use it. Path is then where it
+// would be written.
+func (f DiskFile) IsReassembled() bool {
+	return len(f.Parts) > 0
+}
+
+// Joined reports whether a reassembled file is joined from several parts,
+// rather than cut out of a single larger file
+func (f DiskFile) Joined() bool {
+	return len(f.Parts) > 1
+}
+
+// partSet is a complete set of numbered parts, in order
+type partSet struct {
+	base  string // the path without the part number
+	parts []DiskFile
+	size  int64
+}
+
+// findPartSets groups numbered parts by the path they share. Only sets
+// numbered without gaps from 000 or 001 are returned.
+func findPartSets(diskFiles []DiskFile) []partSet {
+	type part struct {
+		number int
+		file   DiskFile
+	}
+	groups := make(map[string][]part)
+	var bases []string
+	for _, f := range diskFiles {
+		if f.IsArchiveEntry() || f.IsReassembled() {
+			continue
+		}
+		m := partPattern.FindStringSubmatch(f.Path)
+		if m == nil {
+			continue
+		}
+		n, _ := strconv.Atoi(m[2])
+		if _, ok := groups[m[1]]; !ok {
+			bases = append(bases, m[1])
+		}
+		groups[m[1]] = append(groups[m[1]], part{n, f})
+	}
+
+	var sets []partSet
+	for _, base := range bases {
+		parts := groups[base]
+		slices.SortFunc(parts, func(a, b part) int { return cmp.Compare(a.number, b.number) })
+		if len(parts) < 2 || parts[0].number > 1 {
+			continue
+		}
+		set := partSet{base: base}
+		for i, p := range parts {
+			if p.number != parts[0].number+i {
+				set.parts = nil
+				break
+			}
+			set.parts = append(set.parts, p.file)
+			set.size += p.file.Size
+		}
+		if set.parts != nil {
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+// splitSpan is a run of torrent files, back to back in one folder
+type splitSpan struct {
+	files []TorrentFileInfo
+	size  int64
+}
+
+// findSplitSpans returns every run of 2 to maxSplitFiles unmatched torrent
+// files that follow each other in one folder, by total size. Pad and empty
+// files don't break a run.
+func findSplitSpans(torrentFiles []TorrentFileInfo, unmatched map[int]bool) map[int64][]splitSpan {
+	ordered := slices.Clone(torrentFiles)
+	slices.SortFunc(ordered, func(a, b TorrentFileInfo) int { return cmp.Compare(a.Index, b.Index) })
+
+	var runs [][]TorrentFileInfo
+	var run []TorrentFileInfo
+	for _, tf := range ordered {
+		if !tf.NeedsDiskFile() {
+			continue
+		}
+		if !unmatched[tf.Index] || (len(run) > 0 && path.Dir(run[0].Name) != path.Dir(tf.Name)) {
+			runs = append(runs, run)
+			run = nil
+		}
+		if unmatched[tf.Index] {
+			run = append(run, tf)
+		}
+	}
+	runs = append(runs, run)
+
+	spans := make(map[int64][]splitSpan)
+	for _, run := range runs {
+		for i := range run {
+			size := run[i].Size
+			for j := i + 1; j < len(run) && j-i < maxSplitFiles; j++ {
+				size += run[j].Size
+				spans[size] = append(spans[size], splitSpan{files: run[i : j+1], size: size})
+			}
+		}
+	}
+	return spans
+}
+
+// matchReassemblies looks for the unmatched torrent files that can be
+// rebuilt from disk files: a torrent file whose size is that of a set of
+// numbered parts, joined, or torrent files following each other whose sizes
+// add up to one disk file, cut out of it. Each rebuilt file is written next
+// to the disk files it is made of, under the torrent file's name. The
+// matches are selected, but only piece verification tells whether the data
+// really lines up; see UnselectBrokenReassemblies.
+func matchReassemblies(result MatchResult, torrentFiles []TorrentFileInfo, diskFiles []DiskFile, opts MatchOptions) MatchResult {
+	unmatched := make(map[int]bool, len(result.Unmatched))
+	for _, tf := range result.Unmatched {
+		unmatched[tf.Index] = true
+	}
+	targets := make(map[string]bool)
+	for _, f := range diskFiles {
+		targets[f.Path] = true
+	}
+	sameExt := func(name string, diskPath string) bool {
+		return !opts.RequireSameExtension || strings.EqualFold(path.Ext(name), filepath.Ext(diskPath))
+	}
+	add := func(tf TorrentFileInfo, target string, parts []FileSegment) {
+		result.Matches = append(result.Matches, Match{
+			TorrentFile: tf,
+			DiskFiles:   []DiskFile{{Path: target, Name: filepath.Base(target), Size: tf.Size, Parts: parts}},
+			AutoMatched: true,
+		})
+		m := &result.Matches[len(result.Matches)-1]
+		m.Selected = &m.DiskFiles[0]
+		result.MatchedCount++
+		unmatched[tf.Index] = false
+		targets[target] = true
+	}
+
+	// Joins: the closest named set of parts with the torrent file's size
+	sets := findPartSets(diskFiles)
+	used := make([]bool, len(sets))
+	for _, tf := range result.Unmatched {
+		best, bestScore := -1, -1.0
+		for i, set := range sets {
+			if used[i] || set.size != tf.Size || !sameExt(tf.Name, set.base) {
+				continue
+			}
+			if score := MatchScore(tf.Name, set.base); score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			continue
+		}
+		target := filepath.Join(filepath.Dir(sets[best].base), path.Base(tf.Name))
+		if targets[target] {
+			continue
+		}
+		used[best] = true
+		parts := make([]FileSegment, len(sets[best].parts))
+		for i, p := range sets[best].parts {
+			parts[i] = FileSegment{Path: p.Path, Size: p.Size}
+		}
+		add(tf, target, parts)
+	}
+
+	// Splits: a disk file holding a run of torrent files back to back
+	spans := findSplitSpans(torrentFiles, unmatched)
+	if len(spans) > 0 {
+		for _, f := range diskFiles {
+			if f.IsArchiveEntry() || f.IsReassembled() {
+				continue
+			}
+			for _, span := range spans[f.Size] {
+				if !spanAvailable(span, unmatched, f.Path, targets, sameExt) {
+					continue
+				}
+				var offset int64
+				for _, tf := range span.files {
+					target := filepath.Join(filepath.Dir(f.Path), path.Base(tf.Name))
+					add(tf, target, []FileSegment{{Path: f.Path, Offset: offset, Size: tf.Size}})
+					offset += tf.Size
+				}
+				break
+			}
+		}
+	}
+
+	if len(result.Matches) > 0 {
+		slices.SortStableFunc(result.Matches, func(a, b Match) int { return cmp.Compare(a.TorrentFile.Index, b.TorrentFile.Index) })
+	}
+	remaining := []TorrentFileInfo{}
+	for _, tf := range result.Unmatched {
+		if unmatched[tf.Index] {
+			remaining = append(remaining, tf)
+		}
+	}
+	result.Unmatched = remaining
+	return result
+}
+
+// spanAvailable reports whether every file of a span is still unmatched and
+// can be cut out of the disk file at diskPath to a free target
+func spanAvailable(span splitSpan, unmatched map[int]bool, diskPath string, targets map[string]bool, sameExt func(string, string) bool) bool {
+	for _, tf := range span.files {
+		if !unmatched[tf.Index] || !sameExt(tf.Name, diskPath) {
+			return false
+		}
+		if targets[filepath.Join(filepath.Dir(diskPath), path.Base(tf.Name))] {
+			return false
+		}
+	}
+	return true
+}
+
+// UnselectBrokenReassemblies clears the selection of rebuilt files with a
+// piece that fails verification, as an exact join or split passes every
+// piece it can be checked on, and returns how many were cleared
+func UnselectBrokenReassemblies(matches []Match, report PieceReport) int {
+	failed := make(map[int]bool, len(report.Files))
+	for _, f := range report.Files {
+		failed[f.Index] = f.Failed > 0
+	}
+	cleared := 0
+	for i := range matches {
+		m := &matches[i]
+		if m.Selected != nil && m.Selected.IsReassembled() && failed[m.TorrentFile.Index] {
+			m.Selected = nil
+			m.AutoMatched = false
+			cleared++
+		}
+	}
+	return cleared
+}
+
+// Rebuild writes a torrent file made of parts of other disk files to where
+// the renames expect it
+type Rebuild struct {
+	Target string        `json:"target"`
+	Parts  []FileSegment `json:"parts"`
+	Size   int64         `json:"size"`
+}
+
+// Rebuilds returns the rebuilds needed for the selected reassembled files
+func Rebuilds(matches []Match) []Rebuild {
+	rebuilds := []Rebuild{}
+	for _, m := range matches {
+		if m.Selected != nil && m.Selected.IsReassembled() {
+			rebuilds = append(rebuilds, Rebuild{
+				Target: m.Selected.Path,
+				Parts:  m.Selected.Parts,
+				Size:   m.Selected.Size,
+			})
+		}
+	}
+	return rebuilds
+}
+
+// UnselectRebuilds clears the selection of matches whose file could not be
+// rebuilt and returns how many were cleared
+func UnselectRebuilds(matches []Match, failed []Rebuild) int {
+	targets := make(map[string]bool, len(failed))
+	for _, r := range failed {
+		targets[r.Target] = true
+	}
+	count := 0
+	for i := range matches {
+		if s := matches[i].Selected; s != nil && s.IsReassembled() && targets[s.Path] {
+			matches[i].Selected = nil
+			matches[i].AutoMatched = false
+			count++
+		}
+	}
+	return count
+}
+
+// RebuildResult summarises a batch of rebuilds
+type RebuildResult struct {
+	RebuiltCount int       `json:"rebuiltCount"`
+	FailedCount  int       `json:"failedCount"`
+	Failed       []Rebuild `json:"failed"`
+	Errors       []string  `json:"errors"`
+}
+
+// RebuildAll writes every rebuilt file. A failed rebuild is recorded and the
+// remaining ones are still written.
+func RebuildAll(ctx context.Context, rebuilds []Rebuild) RebuildResult {
+	result := RebuildResult{Failed: []Rebuild{}, Errors: []string{}}
+	for _, r := range rebuilds {
+		if err := RebuildFile(ctx, r); err != nil {
+			result.FailedCount++
+			result.Failed = append(result.Failed, r)
+			result.Errors = append(result.Errors, fmt.Sprintf("%s: %v", r.Target, err))
+			continue
+		}
+		result.RebuiltCount++
+	}
+	return result
+}
+
+// RebuildFile writes a single rebuilt file from its parts, which are left
+// in place. Existing files are never overwritten. The file is written to a
+// temporary file first, so a failed or cancelled rebuild leaves nothing
+// behind.
+func RebuildFile(ctx context.Context, r Rebuild) error {
+	if _, err := os.Lstat(r.Target); err == nil {
+		return fmt.Errorf("target already exists")
+	}
+	if err := os.MkdirAll(filepath.Dir(r.Target), 0755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(r.Target), "."+filepath.Base(r.Target)+".*.part")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	var written int64
+	for _, part := range r.Parts {
+		var n int64
+		n, err = copySegment(ctx, tmp, part)
+		written += n
+		if err != nil {
+			break
+		}
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if written != r.Size {
+		return fmt.Errorf("wrote %d bytes, expected %d", written, r.Size)
+	}
+	return os.Rename(tmp.Name(), r.Target)
+}
+
+// copySegment writes a segment of a disk file to w
+func copySegment(ctx context.Context, w io.Writer, s FileSegment) (int64, error) {
+	f, err := os.Open(s.Path)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	size := s.Size
+	if size < 0 {
+		info, err := f.Stat()
+		if err != nil {
+			return 0, err
+		}
+		size = info.Size() - s.Offset
+	}
+	return io.Copy(w, contextReader{ctx, io.NewSectionReader(f, s.Offset, size)})
+}
@@ -0,0 +1,122 @@
+package backend
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindMatchesWithOptions_Join(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{{Index: 0, Name: "Movie/Movie.2019.mkv", Size: 25}}
+	diskFiles := []DiskFile{
+		{Path: "/data/movie.mkv.001", Name: "movie.mkv.001", Size: 10},
+		{Path: "/data/movie.mkv.002", Name: "movie.mkv.002", Size: 10},
+		{Path: "/data/movie.mkv.003", Name: "movie.mkv.003", Size: 5},
+		// A gap in the numbering makes an incomplete set
+		{Path: "/data/other.mkv.001", Name: "other.mkv.001", Size: 20},
+		{Path: "/data/other.mkv.003", Name: "other.mkv.003", Size: 5},
+	}
+
+	result := FindMatchesWithOptions(torrentFiles, diskFiles, MatchOptions{RequireSameExtension: true})
+	if len(result.Unmatched) != 1 {
+		t.Fatalf("Expected no join without SplitJoin, got %+v", result.Matches)
+	}
+
+	result = FindMatchesWithOptions(torrentFiles, diskFiles, MatchOptions{RequireSameExtension: true, SplitJoin: true})
+	if len(result.Matches) != 1 || len(result.Unmatched) != 0 || result.MatchedCount != 1 {
+		t.Fatalf("Expected the parts to be joined, got %+v", result)
+	}
+	selected := result.Matches[0].Selected
+	if selected == nil || !selected.Joined() || len(selected.Parts) != 3 || selected.Path != filepath.Join("/data", "Movie.2019.mkv") {
+		t.Errorf("Unexpected joined file: %+v", selected)
+	}
+}
+
+func TestFindMatchesWithOptions_Split(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "DVD/VIDEO_TS/VIDEO_TS.IFO", Size: 4},
+		{Index: 1, Name: "DVD/VIDEO_TS/VTS_01_1.VOB", Size: 10},
+		{Index: 2, Name: "DVD/VIDEO_TS/VTS_01_2.VOB", Size: 10},
+		{Index: 3, Name: "DVD/VIDEO_TS/VTS_01_3.VOB", Size: 7},
+	}
+	diskFiles := []DiskFile{
+		{Path: "/data/VIDEO_TS.IFO", Name: "VIDEO_TS.IFO", Size: 4},
+		{Path: "/data/title.vob", Name: "title.vob", Size: 27},
+	}
+
+	result := FindMatchesWithOptions(torrentFiles, diskFiles, MatchOptions{RequireSameExtension: true, SplitJoin: true})
+
+	if len(result.Matches) != 4 || len(result.Unmatched) != 0 {
+		t.Fatalf("Expected the VOBs to be cut out of title.vob, got %+v", result)
+	}
+	var offset int64
+	for i, m := range result.Matches[1:] {
+		if m.TorrentFile.Index != i+1 || m.Selected == nil || m.Selected.Joined() {
+			t.Fatalf("Unexpected split match %+v", m)
+		}
+		part := m.Selected.Parts[0]
+		if part.Path != "/data/title.vob" || part.Offset != offset || part.Size != m.TorrentFile.Size {
+			t.Errorf("Unexpected segment %+v for %s", part, m.TorrentFile.Name)
+		}
+		offset += part.Size
+	}
+}
+
+func TestVerifySources_JoinAndRebuild(t *testing.T) {
+	dir := t.TempDir()
+	layout, _ := testLayout(t, dir, 4, "0123456789", "abcdef")
+	writeFiles(t, dir, map[string]string{"a.bin.001": "01234", "a.bin.002": "56789", "all.bin": "0123456789abcdef"})
+
+	matches := []Match{
+		{TorrentFile: layout.Files[0], Selected: &DiskFile{Path: filepath.Join(dir, "joined", "a.bin"), Size: 10, Parts: []FileSegment{
+			{Path: filepath.Join(dir, "a.bin.001"), Size: 5},
+			{Path: filepath.Join(dir, "a.bin.002"), Size: 5},
+		}}},
+		{TorrentFile: layout.Files[1], Selected: &DiskFile{Path: filepath.Join(dir, "joined", "b.bin"), Size: 6, Parts: []FileSegment{
+			{Path: filepath.Join(dir, "all.bin"), Offset: 10, Size: 6},
+		}}},
+	}
+
+	report, err := VerifySources(context.Background(), layout, SelectedSources(matches))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Passed != report.TotalPieces || UnselectBrokenReassemblies(matches, report) != 0 {
+		t.Fatalf("Expected every piece to pass, got %+v", report)
+	}
+
+	result := RebuildAll(context.Background(), Rebuilds(matches))
+	if result.RebuiltCount != 2 {
+		t.Fatalf("Expected 2 rebuilt files, got %+v", result)
+	}
+	expectContent(t, filepath.Join(dir, "joined", "a.bin"), "0123456789")
+	expectContent(t, filepath.Join(dir, "joined", "b.bin"), "abcdef")
+
+	// Rebuilding again must not overwrite
+	again := RebuildAll(context.Background(), Rebuilds(matches))
+	if again.FailedCount != 2 || UnselectRebuilds(matches, again.Failed) != 2 {
+		t.Errorf("Expected existing targets to fail, got %+v", again)
+	}
+}
+
+func TestUnselectBrokenReassemblies(t *testing.T) {
+	dir := t.TempDir()
+	layout, _ := testLayout(t, dir, 4, "0123456789")
+	writeFiles(t, dir, map[string]string{"a.bin.001": "01234", "a.bin.002": "5678X"})
+	matches := []Match{{TorrentFile: layout.Files[0], Selected: &DiskFile{Path: filepath.Join(dir, "x.bin"), Size: 10, Parts: []FileSegment{
+		{Path: filepath.Join(dir, "a.bin.001"), Size: 5},
+		{Path: filepath.Join(dir, "a.bin.002"), Size: 5},
+	}}}}
+
+	report, err := VerifySources(context.Background(), layout, SelectedSources(matches))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if UnselectBrokenReassemblies(matches, report) != 1 || matches[0].Selected != nil {
+		t.Errorf("Expected the join with a failing piece to be unselected, got %+v", report)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "x.bin")); err == nil {
+		t.Error("Expected nothing to be written")
+	}
+}
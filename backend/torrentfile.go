@@ -0,0 +1,242 @@
+package backend
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Metainfo represents the parts of a .torrent file needed for matching
+type Metainfo struct {
+	InfoHash    string         `json:"infoHash"` // hex-encoded v1 info hash
+	Name        string         `json:"name"`
+	PieceLength int64          `json:"pieceLength"`
+	PieceHashes []string       `json:"pieceHashes"` // hex-encoded SHA-1 piece hashes
+	Files       []MetainfoFile `json:"files"`
+	TotalSize   int64          `json:"totalSize"`
+	MultiFile   bool           `json:"multiFile"`
+}
+
+// MetainfoFile represents a single file entry in a .torrent
+type MetainfoFile struct {
+	Path string `json:"path"` // path inside the torrent, without the torrent name
+	Size int64  `json:"size"`
+	Attr string `json:"attr"` // BEP 47 attributes, e.g. "p" for pad files
+}
+
+// ParseTorrentFile reads and parses a .torrent file
+func ParseTorrentFile(filePath string) (*Metainfo, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return ParseTorrent(data)
+}
+
+// ParseTorrent parses bencoded .torrent data
+func ParseTorrent(data []byte) (*Metainfo, error) {
+	d := &bdecoder{data: data}
+	root, err := d.decode(0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid torrent: %w", err)
+	}
+
+	dict, ok := root.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("invalid torrent: not a dictionary")
+	}
+	info, ok := dict["info"].(map[string]any)
+	if !ok || d.infoEnd == 0 {
+		return nil, fmt.Errorf("invalid torrent: missing info dictionary")
+	}
+
+	hash := sha1.Sum(data[d.infoStart:d.infoEnd])
+	meta := &Metainfo{
+		InfoHash:    hex.EncodeToString(hash[:]),
+		Name:        utf8String(info, "name"),
+		PieceLength: intValue(info["piece length"]),
+	}
+
+	pieces, _ := info["pieces"].(string)
+	if pieces == "" {
+		return nil, fmt.Errorf("v2-only torrents are not supported")
+	}
+	if len(pieces)%sha1.Size != 0 {
+		return nil, fmt.Errorf("invalid torrent: malformed piece hashes")
+	}
+	for i := 0; i < len(pieces); i += sha1.Size {
+		meta.PieceHashes = append(meta.PieceHashes, hex.EncodeToString([]byte(pieces[i:i+sha1.Size])))
+	}
+
+	if files, ok := info["files"].([]any); ok {
+		meta.MultiFile = true
+		for _, f := range files {
+			entry, ok := f.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("invalid torrent: malformed file entry")
+			}
+
+			parts, ok := entry["path.utf-8"].([]any)
+			if !ok {
+				parts, _ = entry["path"].([]any)
+			}
+			segments := make([]string, 0, len(parts))
+			for _, p := range parts {
+				if s, ok := p.(string); ok {
+					segments = append(segments, s)
+				}
+			}
+
+			attr, _ := entry["attr"].(string)
+			file := MetainfoFile{
+				Path: path.Join(segments...),
+				Size: intValue(entry["length"]),
+				Attr: attr,
+			}
+			meta.Files = append(meta.Files, file)
+			meta.TotalSize += file.Size
+		}
+	} else {
+		meta.Files = []MetainfoFile{{Path: meta.Name, Size: intValue(info["length"])}}
+		meta.TotalSize = meta.Files[0].Size
+	}
+
+	return meta, nil
+}
+
+// TorrentFiles returns the files as qBittorrent names them with the
+// "Original" content layout: multi-file torrents keep the torrent name as
+// the root folder
+func (m *Metainfo) TorrentFiles() []TorrentFileInfo {
+	files := make([]TorrentFileInfo, len(m.Files))
+	for i, f := range m.Files {
+		name := f.Path
+		if m.MultiFile {
+			name = m.Name + "/" + f.Path
+		}
+		files[i] = TorrentFileInfo{Index: i, Name: name, Size: f.Size, Pad: strings.Contains(f.Attr, "p")}
+	}
+	return files
+}
+
+// utf8String returns the ".utf-8" variant of a string key when present
+func utf8String(dict map[string]any, key string) string {
+	if s, ok := dict[key+".utf-8"].(string); ok {
+		return s
+	}
+	s, _ := dict[key].(string)
+	return s
+}
+
+func intValue(v any) int64 {
+	i, _ := v.(int64)
+	return i
+}
+
+// bdecoder is a minimal bencode decoder. It records the byte range of the
+// top-level info dictionary so the info hash can be computed over the
+// original encoding.
+type bdecoder struct {
+	data      []byte
+	pos       int
+	infoStart int
+	infoEnd   int
+}
+
+const maxBencodeDepth = 64
+
+func (d *bdecoder) decode(depth int) (any, error) {
+	if depth > maxBencodeDepth {
+		return nil, fmt.Errorf("nesting too deep")
+	}
+	if d.pos >= len(d.data) {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+
+	switch c := d.data[d.pos]; {
+	case c == 'i':
+		d.pos++
+		end := d.indexFrom('e')
+		if end < 0 {
+			return nil, fmt.Errorf("unterminated integer")
+		}
+		n, err := strconv.ParseInt(string(d.data[d.pos:end]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer: %w", err)
+		}
+		d.pos = end + 1
+		return n, nil
+
+	case c == 'l':
+		d.pos++
+		list := []any{}
+		for d.pos < len(d.data) && d.data[d.pos] != 'e' {
+			v, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			list = append(list, v)
+		}
+		if d.pos >= len(d.data) {
+			return nil, fmt.Errorf("unterminated list")
+		}
+		d.pos++
+		return list, nil
+
+	case c == 'd':
+		d.pos++
+		dict := map[string]any{}
+		for d.pos < len(d.data) && d.data[d.pos] != 'e' {
+			key, err := d.decodeString()
+			if err != nil {
+				return nil, err
+			}
+			start := d.pos
+			v, err := d.decode(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			if depth == 0 && key == "info" {
+				d.infoStart, d.infoEnd = start, d.pos
+			}
+			dict[key] = v
+		}
+		if d.pos >= len(d.data) {
+			return nil, fmt.Errorf("unterminated dictionary")
+		}
+		d.pos++
+		return dict, nil
+
+	case c >= '0' && c <= '9':
+		return d.decodeString()
+
+	default:
+		return nil, fmt.Errorf("unexpected byte %q at offset %d", c, d.pos)
+	}
+}
+
+func (d *bdecoder) decodeString() (string, error) {
+	colon := d.indexFrom(':')
+	if colon < 0 {
+		return "", fmt.Errorf("invalid string at offset %d", d.pos)
+	}
+	n, err := strconv.Atoi(string(d.data[d.pos:colon]))
+	if err != nil || n < 0 || colon+1+n > len(d.data) {
+		return "", fmt.Errorf("invalid string length at offset %d", d.pos)
+	}
+	d.pos = colon + 1 + n
+	return string(d.data[colon+1 : d.pos]), nil
+}
+
+func (d *bdecoder) indexFrom(b byte) int {
+	for i := d.pos; i < len(d.data); i++ {
+		if d.data[i] == b {
+			return i
+		}
+	}
+	return -1
+}
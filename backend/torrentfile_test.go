@@ -0,0 +1,88 @@
+package backend
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestParseTorrent_MultiFile(t *testing.T) {
+	pieces := strings.Repeat("a", 20) + strings.Repeat("b", 20)
+	info := "d5:filesld6:lengthi100e4:pathl4:Subs5:a.srteed6:lengthi200e4:pathl5:b.mkveee" +
+		"4:name4:Pack12:piece lengthi16384e6:pieces40:" + pieces + "e"
+	data := "d8:announce3:url4:info" + info + "e"
+
+	meta, err := ParseTorrent([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseTorrent failed: %v", err)
+	}
+
+	hash := sha1.Sum([]byte(info))
+	if meta.InfoHash != hex.EncodeToString(hash[:]) {
+		t.Errorf("Unexpected info hash %s", meta.InfoHash)
+	}
+	if meta.Name != "Pack" || meta.PieceLength != 16384 || meta.TotalSize != 300 {
+		t.Errorf("Unexpected metainfo: %+v", meta)
+	}
+	if len(meta.PieceHashes) != 2 || meta.PieceHashes[0] != hex.EncodeToString([]byte(strings.Repeat("a", 20))) {
+		t.Errorf("Unexpected piece hashes: %v", meta.PieceHashes)
+	}
+
+	files := meta.TorrentFiles()
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 files, got %d", len(files))
+	}
+	if files[0].Name != "Pack/Subs/a.srt" || files[0].Size != 100 {
+		t.Errorf("Unexpected first file: %+v", files[0])
+	}
+	if files[1].Index != 1 || files[1].Name != "Pack/b.mkv" {
+		t.Errorf("Unexpected second file: %+v", files[1])
+	}
+}
+
+func TestParseTorrent_PadFile(t *testing.T) {
+	info := "d5:filesld6:lengthi10e4:pathl5:a.mkveed4:attr1:p6:lengthi6e4:pathl4:.pad1:6eed6:lengthi3e4:pathl5:b.mkveee" +
+		"4:name4:Pack12:piece lengthi16e6:pieces20:" + strings.Repeat("a", 20) + "e"
+
+	meta, err := ParseTorrent([]byte("d4:info" + info + "e"))
+	if err != nil {
+		t.Fatalf("ParseTorrent failed: %v", err)
+	}
+
+	files := meta.TorrentFiles()
+	if len(files) != 3 || !files[1].Pad || files[0].Pad || files[2].Pad {
+		t.Errorf("Expected only the second file to be a pad file, got %+v", files)
+	}
+}
+
+func TestParseTorrent_SingleFile(t *testing.T) {
+	data := "d4:infod6:lengthi42e4:name9:movie.mkv12:piece lengthi16384e6:pieces20:" +
+		strings.Repeat("x", 20) + "ee"
+
+	meta, err := ParseTorrent([]byte(data))
+	if err != nil {
+		t.Fatalf("ParseTorrent failed: %v", err)
+	}
+
+	files := meta.TorrentFiles()
+	if len(files) != 1 || files[0].Name != "movie.mkv" || files[0].Size != 42 {
+		t.Errorf("Unexpected files: %+v", files)
+	}
+}
+
+func TestParseTorrent_Invalid(t *testing.T) {
+	inputs := []string{
+		"",
+		"i42e",
+		"d4:infoi1ee",
+		"d4:infod4:name1:x",
+		"d4:infod4:name1:xee", // no pieces, v2-only
+	}
+
+	for _, input := range inputs {
+		if _, err := ParseTorrent([]byte(input)); err == nil {
+			t.Errorf("Expected error for %q", input)
+		}
+	}
+}
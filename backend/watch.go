@@ -0,0 +1,449 @@
+package backend
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultWatchStates are the torrent states that trigger matching in watch mode
+var DefaultWatchStates = []string{"missingFiles", "error"}
+
+// DefaultReviewTag is added to torrents watch mode could not fix on its own
+const DefaultReviewTag = "needs-review"
+
+// WatchOptions configures which torrents watch mode picks up and what it
+// does with them
+type WatchOptions struct {
+	Roots                []string // directories scanned for files
+	States               []string // torrent states that trigger matching, DefaultWatchStates when empty
+	Categories           []string // categories whose torrents are matched regardless of state
+	RequireSameExtension bool
+	Recheck              bool // recheck applied torrents and wait for the result
+	ReviewTag            string
+	PostProcess          PostProcessOptions
+	DryRun               bool
+	// Rules map torrent paths to disk paths before size matching
+	Rules *RuleSet
+	// ReuseDuplicates selects, for files no disk file was selected for, the
+	// copy another torrent sharing the file has complete on disk
+	ReuseDuplicates bool
+	// PathMap translates qBittorrent's paths to this machine's, e.g. when
+	// qBittorrent runs in a container. Without it both see the same paths.
+	PathMap []PathMapping
+}
+
+// PathMapping is a directory as this machine and as qBittorrent see it
+type PathMapping struct {
+	Local string `json:"local"`
+	QBit  string `json:"qbit"`
+}
+
+// ParsePathMapping parses "<local>=<qbit>"
+func ParsePathMapping(s string) (PathMapping, error) {
+	local, qbit, ok := strings.Cut(s, "=")
+	if !ok || local == "" || qbit == "" {
+		return PathMapping{}, fmt.Errorf("invalid path mapping %q, expected <local>=<qbit>", s)
+	}
+	return PathMapping{Local: filepath.Clean(local), QBit: qbit}, nil
+}
+
+// mapPath moves p from under the from directory to under to. ok is false
+// when p is not inside from.
+func mapPath(p string, from string, to string) (string, bool) {
+	rel, err := relPath(from, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return p, false
+	}
+	if rel == "." {
+		return to, true
+	}
+	return filepath.Join(to, rel), true
+}
+
+// WatchResult represents what watch mode did with a torrent
+type WatchResult struct {
+	Hash         string `json:"hash"`
+	Name         string `json:"name"`
+	Outcome      string `json:"outcome"`
+	Applied      bool   `json:"applied"`
+	RenamedCount int    `json:"renamedCount"`
+	FailedCount  int    `json:"failedCount"`
+	Reason       string `json:"reason"` // why the plan was not applied automatically
+}
+
+// Watcher polls qBittorrent for torrents with missing files and matches them
+// against the configured roots
+type Watcher struct {
+	qbit *QBitService
+	opts WatchOptions
+
+	// Logf, when set, receives progress messages
+	Logf func(format string, args ...any)
+	// Notifier, when set, is told about applied, failed and review results
+	Notifier Notifier
+
+	mu      sync.Mutex
+	handled map[string]bool // hashes processed while they kept triggering
+}
+
+// NewWatcher creates a watcher using a connected QBitService
+func NewWatcher(qbit *QBitService, opts WatchOptions) *Watcher {
+	if len(opts.States) == 0 {
+		opts.States = DefaultWatchStates
+	}
+	if opts.ReviewTag == "" {
+		opts.ReviewTag = DefaultReviewTag
+	}
+	return &Watcher{
+		qbit:    qbit,
+		opts:    opts,
+		handled: make(map[string]bool),
+	}
+}
+
+// Triggers reports whether a torrent should be matched
+func (w *Watcher) Triggers(t TorrentInfo) bool {
+	return slices.Contains(w.opts.States, t.State) ||
+		(t.Category != "" && slices.Contains(w.opts.Categories, t.Category))
+}
+
+// Run polls every interval until ctx is cancelled. Hashes received on hooks
+// are processed right away when they trigger, without waiting for or
+// forcing a poll.
+func (w *Watcher) Run(ctx context.Context, interval time.Duration, hooks <-chan string) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	poll := func() {
+		if _, err := w.Poll(); err != nil {
+			w.logf("Poll failed: %v", err)
+		}
+	}
+	poll()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case hash := <-hooks:
+			if _, err := w.Process(hash); err != nil {
+				w.logf("Processing %s failed: %v", hash, err)
+			}
+		case <-ticker.C:
+			poll()
+		}
+	}
+}
+
+// Poll processes every triggering torrent not handled yet. Torrents that stop
+// triggering are forgotten, so they are processed again if they go missing
+// later.
+func (w *Watcher) Poll() ([]WatchResult, error) {
+	torrents, err := w.qbit.GetTorrents()
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []TorrentInfo
+	w.mu.Lock()
+	triggering := make(map[string]bool)
+	for _, t := range torrents {
+		if !w.Triggers(t) {
+			continue
+		}
+		triggering[t.Hash] = true
+		if !w.handled[t.Hash] {
+			pending = append(pending, t)
+		}
+	}
+	for hash := range w.handled {
+		if !triggering[hash] {
+			delete(w.handled, hash)
+		}
+	}
+	w.mu.Unlock()
+
+	if len(pending) == 0 {
+		return nil, nil
+	}
+
+	// Scan once for all torrents found in this pass
+	diskFiles, err := w.scanRoots()
+	if err != nil {
+		return nil, err
+	}
+	duplicates, err := w.duplicates()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []WatchResult
+	for _, t := range pending {
+		result, err := w.processTorrent(t, diskFiles, duplicates)
+		if err != nil {
+			w.logf("%s: %v", t.Name, err)
+			continue
+		}
+		w.mu.Lock()
+		w.handled[t.Hash] = true
+		w.mu.Unlock()
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// Process matches a single torrent, e.g. one reported by qBittorrent's "run
+// external program on torrent added", if it triggers; other torrents, such
+// as normal downloads, get an empty result. The torrent counts as handled,
+// so polls skip it while it keeps triggering.
+func (w *Watcher) Process(hash string) (WatchResult, error) {
+	torrents, err := w.qbit.GetTorrents()
+	if err != nil {
+		return WatchResult{}, err
+	}
+	i := slices.IndexFunc(torrents, func(t TorrentInfo) bool { return strings.EqualFold(t.Hash, hash) })
+	if i < 0 {
+		return WatchResult{}, fmt.Errorf("torrent %s not found", hash)
+	}
+	if !w.Triggers(torrents[i]) {
+		w.logf("%s: ignored, %s does not trigger matching", torrents[i].Name, torrents[i].State)
+		return WatchResult{}, nil
+	}
+
+	diskFiles, err := w.scanRoots()
+	if err != nil {
+		return WatchResult{}, err
+	}
+	duplicates, err := w.duplicates()
+	if err != nil {
+		return WatchResult{}, err
+	}
+	result, err := w.processTorrent(torrents[i], diskFiles, duplicates)
+	if err != nil {
+		return result, err
+	}
+	w.mu.Lock()
+	w.handled[torrents[i].Hash] = true
+	w.mu.Unlock()
+	return result, nil
+}
+
+func (w *Watcher) processTorrent(t TorrentInfo, diskFiles []DiskFile, duplicates []DuplicateGroup) (WatchResult, error) {
+	result := WatchResult{Hash: t.Hash, Name: t.Name}
+
+	files, err := w.qbit.GetTorrentFiles(t.Hash)
+	if err != nil {
+		return result, fmt.Errorf("failed to get torrent files: %w", err)
+	}
+	torrentFiles := make([]TorrentFileInfo, len(files))
+	for i, f := range files {
+		torrentFiles[i] = TorrentFileInfo{Index: f.Index, Name: f.Name, Size: f.Size}
+	}
+
+	matchResult := FindMatchesWithOptions(torrentFiles, diskFiles, MatchOptions{
+		RequireSameExtension: w.opts.RequireSameExtension,
+		Rules:                w.opts.Rules,
+	})
+	for _, e := range matchResult.RuleErrors {
+		w.logf("%s: rule failed: %s", t.Name, e)
+	}
+	if reused := ReuseDuplicates(&matchResult, t.Hash, duplicates); reused > 0 {
+		w.logf("%s: reused %d files other torrents have complete", t.Name, reused)
+	}
+	result.Outcome = ClassifyOutcome(matchResult.Matches, len(matchResult.Unmatched))
+	w.logf("%s: %d of %d files matched (%s)", t.Name, matchResult.MatchedCount, matchResult.TotalFiles, result.Outcome)
+
+	savePath := w.localPath(t.SavePath)
+	base, reason := AutoApplyBase(matchResult, savePath, w.opts.Roots)
+	if reason != "" {
+		result.Reason = reason
+		w.logf("%s: not applied, %s", t.Name, reason)
+		if w.opts.DryRun {
+			return result, nil
+		}
+		w.notify(result, NotifyNeedsReview, reason)
+		tags := append([]string{w.opts.ReviewTag}, w.opts.PostProcess.TagsFor(result.Outcome)...)
+		return result, w.qbit.AddTags(t.Hash, tags)
+	}
+
+	renames := GenerateRenames(matchResult.Matches, base)
+	renames = CollapseFolderRenames(renames, torrentFiles)
+	if w.opts.DryRun {
+		w.logf("%s: [DRY RUN] would apply %d renames relative to %s", t.Name, len(renames), base)
+		return result, nil
+	}
+
+	if !samePath(base, savePath) {
+		location := w.qbitPath(base)
+		if err := w.qbit.SetTorrentLocation(t.Hash, location); err != nil {
+			return result, fmt.Errorf("failed to set location: %w", err)
+		}
+		w.logf("%s: save path set to %s", t.Name, location)
+	}
+
+	applied := w.qbit.ApplyRenames(t.Hash, renames)
+	result.Applied = true
+	result.RenamedCount = applied.RenamedCount
+	result.FailedCount = applied.FailedCount
+	for _, e := range applied.Errors {
+		w.logf("%s: %s", t.Name, e)
+	}
+	w.logf("%s: renamed %d files, %d failed", t.Name, applied.RenamedCount, applied.FailedCount)
+	if applied.FailedCount > 0 {
+		result.Outcome = OutcomeNeedsReview
+	}
+	w.notify(result, NotifyApplied, strings.Join(applied.Errors, "\n"))
+
+	if w.opts.Recheck && result.Outcome == OutcomeMatched {
+		if err := w.qbit.RecheckTorrent(t.Hash); err != nil {
+			return result, fmt.Errorf("failed to trigger recheck: %w", err)
+		}
+		report, err := w.qbit.WaitForRecheck(t.Hash, 0)
+		if err != nil {
+			return result, err
+		}
+		w.logf("%s: verified %d complete, %d incomplete", t.Name, report.CompleteCount, report.IncompleteCount)
+		if report.TimedOut || report.IncompleteCount > 0 {
+			result.Outcome = OutcomeNeedsReview
+			w.notify(result, NotifyRecheckFailed, recheckSummary(report))
+		}
+	}
+
+	opts := w.opts.PostProcess
+	if result.Outcome != OutcomeMatched {
+		opts.Tags = append([]string{w.opts.ReviewTag}, opts.Tags...)
+	}
+	return result, w.qbit.PostProcess(t.Hash, result.Outcome, opts)
+}
+
+// AutoApplyBase decides whether a match is confident enough to apply without
+// review: every torrent file matched exactly (see Match.Exact) to a distinct
+// disk file, all inside the torrent's save path or a single root. A name
+// that is only similar is not enough. It returns the directory renames are
+// relative to, or the reason the match needs review.
+func AutoApplyBase(result MatchResult, savePath string, roots []string) (string, string) {
+	if len(result.Unmatched) > 0 {
+		return "", fmt.Sprintf("%d files have no match", len(result.Unmatched))
+	}
+	if len(result.Matches) == 0 {
+		return "", "torrent has no files"
+	}
+
+	used := make(map[string]bool)
+	var selected []string
+	for _, m := range result.Matches {
+		if m.Selected == nil || !m.AutoMatched {
+			return "", fmt.Sprintf("%s has %d candidates", m.TorrentFile.Name, len(m.DiskFiles))
+		}
+		if !m.Exact {
+			return "", fmt.Sprintf("%s is only matched by a similar name", m.TorrentFile.Name)
+		}
+		if used[m.Selected.Path] {
+			return "", fmt.Sprintf("%s is matched by more than one torrent file", m.Selected.Path)
+		}
+		used[m.Selected.Path] = true
+		selected = append(selected, m.Selected.Path)
+	}
+
+	for _, base := range append([]string{savePath}, roots...) {
+		if base != "" && allWithin(selected, base) {
+			return base, ""
+		}
+	}
+	return "", "matched files are spread across several roots"
+}
+
+func (w *Watcher) scanRoots() ([]DiskFile, error) {
+	var all []DiskFile
+	seen := make(map[string]bool)
+	for _, root := range w.opts.Roots {
+		files, err := ScanDirectory(root)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan %s: %w", root, err)
+		}
+		for _, f := range files {
+			// Nested roots would list files twice
+			if !seen[f.Path] {
+				seen[f.Path] = true
+				all = append(all, f)
+			}
+		}
+	}
+	return all, nil
+}
+
+// duplicates returns the files torrents share when they are reused, compared
+// by piece hashes so files renamed between torrents are found too
+func (w *Watcher) duplicates() ([]DuplicateGroup, error) {
+	if !w.opts.ReuseDuplicates {
+		return nil, nil
+	}
+	report, err := w.qbit.FindDuplicateFiles(true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate files: %w", err)
+	}
+	// Copies are looked for on this machine
+	for _, g := range report.Groups {
+		for i := range g.Files {
+			g.Files[i].SavePath = w.localPath(g.Files[i].SavePath)
+		}
+	}
+	return report.Groups, nil
+}
+
+// localPath returns where a path qBittorrent reports is on this machine
+func (w *Watcher) localPath(qbitPath string) string {
+	for _, m := range w.opts.PathMap {
+		if local, ok := mapPath(qbitPath, m.QBit, m.Local); ok {
+			return local
+		}
+	}
+	return qbitPath
+}
+
+// qbitPath returns a path on this machine as qBittorrent sees it
+func (w *Watcher) qbitPath(localPath string) string {
+	for _, m := range w.opts.PathMap {
+		if qbit, ok := mapPath(localPath, m.Local, m.QBit); ok {
+			return qbit
+		}
+	}
+	return localPath
+}
+
+func (w *Watcher) notify(result WatchResult, kind string, message string) {
+	err := SendNotification(w.Notifier, Notification{
+		Kind:         kind,
+		Hash:         result.Hash,
+		Name:         result.Name,
+		Outcome:      result.Outcome,
+		RenamedCount: result.RenamedCount,
+		FailedCount:  result.FailedCount,
+		Message:      message,
+	})
+	if err != nil {
+		w.logf("%s: notification failed: %v", result.Name, err)
+	}
+}
+
+func (w *Watcher) logf(format string, args ...any) {
+	if w.Logf != nil {
+		w.Logf(format, args...)
+	}
+}
+
+// allWithin reports whether every path is inside dir
+func allWithin(paths []string, dir string) bool {
+	for _, p := range paths {
+		rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(p))
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return false
+		}
+	}
+	return true
+}
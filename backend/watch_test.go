@@ -0,0 +1,146 @@
+package backend
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestWatcher_Triggers(t *testing.T) {
+	w := NewWatcher(&QBitService{}, WatchOptions{Categories: []string{"fixme"}})
+
+	tests := []struct {
+		torrent  TorrentInfo
+		expected bool
+	}{
+		{TorrentInfo{State: "missingFiles"}, true},
+		{TorrentInfo{State: "error"}, true},
+		{TorrentInfo{State: "stalledUP"}, false},
+		{TorrentInfo{State: "stalledUP", Category: "fixme"}, true},
+		{TorrentInfo{State: "stalledUP", Category: "movies"}, false},
+	}
+
+	for _, tt := range tests {
+		if result := w.Triggers(tt.torrent); result != tt.expected {
+			t.Errorf("Triggers(%+v) = %v, want %v", tt.torrent, result, tt.expected)
+		}
+	}
+}
+
+func TestWatcher_ProcessMarksHandled(t *testing.T) {
+	service := newFakeQBitService(t, fakeTorrent{State: "missingFiles", Files: []TorrentFile{{Index: 0, Name: "Show/E01.mkv", Size: 9, Priority: 1}}})
+	w := NewWatcher(service, WatchOptions{DryRun: true})
+
+	if _, err := w.Process("ABC"); err != nil {
+		t.Fatal(err)
+	}
+	results, err := w.Poll()
+	if err != nil || len(results) != 0 {
+		t.Errorf("Expected the hooked torrent not to be processed again, got %+v (%v)", results, err)
+	}
+}
+
+func TestWatcher_ProcessIgnoresDownloads(t *testing.T) {
+	service := newFakeQBitService(t, fakeTorrent{State: "downloading", Files: []TorrentFile{{Index: 0, Name: "Show/E01.mkv", Size: 9, Priority: 1}}})
+	w := NewWatcher(service, WatchOptions{Roots: []string{"/does/not/exist"}})
+
+	// Scanning the missing root would fail, so the torrent is not processed
+	result, err := w.Process("abc")
+	if err != nil || result != (WatchResult{}) {
+		t.Errorf("Expected a downloading torrent to be ignored, got %+v (%v)", result, err)
+	}
+}
+
+func TestAutoApplyBase(t *testing.T) {
+	root := filepath.FromSlash("/data/library")
+	savePath := filepath.FromSlash("/downloads")
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "Show/e01.mkv", Size: 100},
+		{Index: 1, Name: "Show/e02.mkv", Size: 200},
+	}
+	inRoot := []DiskFile{
+		{Path: filepath.Join(root, "Show", "e01.mkv"), Name: "e01.mkv", Size: 100},
+		{Path: filepath.Join(root, "Show", "e02.mkv"), Name: "e02.mkv", Size: 200},
+	}
+
+	base, reason := AutoApplyBase(FindMatches(torrentFiles, inRoot, true), savePath, []string{"/other", root})
+	if reason != "" || base != root {
+		t.Errorf("Expected base %s, got %q (reason %q)", root, base, reason)
+	}
+
+	inSavePath := []DiskFile{
+		{Path: filepath.Join(savePath, "E01.mkv"), Name: "E01.mkv", Size: 100},
+		{Path: filepath.Join(savePath, "e02.mkv"), Name: "e02.mkv", Size: 200},
+	}
+	base, _ = AutoApplyBase(FindMatches(torrentFiles, inSavePath, true), savePath, []string{root})
+	if base != savePath {
+		t.Errorf("Expected the save path to be preferred, got %q", base)
+	}
+
+	// Unique sizes, but the names are only similar
+	renamed := []DiskFile{
+		{Path: filepath.Join(root, "Show", "Episode 1.mkv"), Name: "Episode 1.mkv", Size: 100},
+		{Path: filepath.Join(root, "Show", "Episode 2.mkv"), Name: "Episode 2.mkv", Size: 200},
+	}
+	if base, reason := AutoApplyBase(FindMatches(torrentFiles, renamed, true), savePath, []string{root}); reason == "" {
+		t.Errorf("Expected review for files matched by size and a similar name, got base %q", base)
+	}
+}
+
+func TestWatcher_PathMap(t *testing.T) {
+	local, err := ParsePathMapping(filepath.FromSlash("/mnt/nas/media") + "=/downloads")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ParsePathMapping("/mnt/nas/media"); err == nil {
+		t.Error("Expected error for a mapping without =")
+	}
+	w := NewWatcher(&QBitService{}, WatchOptions{PathMap: []PathMapping{local}})
+
+	if got := w.localPath("/downloads/Show"); got != filepath.FromSlash("/mnt/nas/media/Show") {
+		t.Errorf("Expected the qBittorrent path mapped to this machine, got %q", got)
+	}
+	if got := w.qbitPath(filepath.FromSlash("/mnt/nas/media/Show")); got != filepath.FromSlash("/downloads/Show") {
+		t.Errorf("Expected the local path mapped to qBittorrent's, got %q", got)
+	}
+	if got := w.qbitPath(filepath.FromSlash("/mnt/nas/mediathek")); got != filepath.FromSlash("/mnt/nas/mediathek") {
+		t.Errorf("Expected paths outside every mapping to be kept, got %q", got)
+	}
+}
+
+func TestAutoApplyBase_NeedsReview(t *testing.T) {
+	torrentFiles := []TorrentFileInfo{
+		{Index: 0, Name: "a.mkv", Size: 100},
+		{Index: 1, Name: "b.mkv", Size: 100},
+	}
+
+	tests := []struct {
+		name      string
+		diskFiles []DiskFile
+	}{
+		{"unmatched", []DiskFile{{Path: "/data/x.mkv", Name: "x.mkv", Size: 100}, {Path: "/data/y.mkv", Name: "y.mkv", Size: 999}}},
+		{"ambiguous", []DiskFile{{Path: "/data/x.mkv", Name: "x.mkv", Size: 100}, {Path: "/data/y.mkv", Name: "y.mkv", Size: 100}}},
+		{"no files", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FindMatches(torrentFiles, tt.diskFiles, true)
+			if base, reason := AutoApplyBase(result, "/data", nil); reason == "" {
+				t.Errorf("Expected review, got base %q", base)
+			}
+		})
+	}
+
+	// One disk file auto-matched by two torrent files is not unique
+	single := []DiskFile{{Path: "/data/x.mkv", Name: "x.mkv", Size: 100}}
+	if _, reason := AutoApplyBase(FindMatches(torrentFiles, single, true), "/data", nil); reason == "" {
+		t.Error("Expected review when one disk file matches two torrent files")
+	}
+
+	// Matched files outside the save path and every root
+	outside := []DiskFile{{Path: "/elsewhere/x.mkv", Name: "x.mkv", Size: 100}}
+	one := []TorrentFileInfo{{Index: 0, Name: "a.mkv", Size: 100}}
+	if _, reason := AutoApplyBase(FindMatches(one, outside, true), "/data", []string{"/library"}); reason == "" {
+		t.Error("Expected review when files are outside every root")
+	}
+}
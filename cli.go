@@ -2,8 +2,10 @@ package main
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 
@@ -12,9 +14,7 @@ import (
 
 // CLI config for match command
 type matchConfig struct {
-	url           string
-	username      string
-	password      string
+	conn          backend.ConnectionConfig
 	hash          string
 	path          string
 	sameExtension bool
@@ -22,43 +22,43 @@ type matchConfig struct {
 	dryRun        bool
 	autoSelect    bool // Auto-select first match without prompting
 	recheck       bool // Trigger recheck after applying renames
+	waitRecheck   bool // Wait for the recheck to finish and report results
+	revertFailed  bool // Revert renames of files that fail verification
+	postProcess   backend.PostProcessOptions
+	notifier      backend.Notifier
+	sizeTolerance int64    // bytes; propose files whose size differs by up to this much
+	verifyPieces  bool     // hash selected files against the torrent's pieces before applying
+	partialFiles  bool     // also offer smaller, partially downloaded files
+	splitJoin     bool     // also rebuild files split into numbered parts or concatenated
+	fingerprints  bool     // sample same-size files to collapse identical copies
+	reference     string   // directory with copies of the torrent's files to compare candidates to
+	archives      bool     // also match files inside zip and tar archives
+	link          bool     // link disk files into the torrent layout instead of renaming
+	linkMethods   []string // link methods to try, in order
+	savePath      string   // the torrent's save path on this machine, for --link and --move
+	move          bool     // move disk files into the torrent layout instead of renaming
+	collision     string   // what --move does with taken targets
+	rulesProfile  string   // rename rules applied before size matching
+	rulesFile     string   // rules file, the default one when empty
+	reuse         bool     // select the complete copy of files other torrents share
 }
 
 func runMatchCommand() {
 	config := matchConfig{
-		sameExtension: true, // default
-	}
-
-	// Load from environment variables first (command line args override)
-	if envPassword := os.Getenv("QBT_PASSWORD"); envPassword != "" {
-		config.password = envPassword
-	}
-	if envUsername := os.Getenv("QBT_USERNAME"); envUsername != "" {
-		config.username = envUsername
-	}
-	if envURL := os.Getenv("QBT_URL"); envURL != "" {
-		config.url = envURL
+		conn:          connectionFromEnv(), // command line args override
+		sameExtension: true,                // default
 	}
+	var notifySpecs []string
 
 	// Parse flags (override environment variables)
 	args := os.Args[2:]
 	for i := 0; i < len(args); i++ {
+		if next, ok := parseConnectionFlag(args, i, &config.conn); ok {
+			i = next
+			continue
+		}
+
 		switch args[i] {
-		case "--url":
-			if i+1 < len(args) {
-				config.url = args[i+1]
-				i++
-			}
-		case "--username", "-u":
-			if i+1 < len(args) {
-				config.username = args[i+1]
-				i++
-			}
-		case "--password", "-p":
-			if i+1 < len(args) {
-				config.password = args[i+1]
-				i++
-			}
 		case "--hash":
 			if i+1 < len(args) {
 				config.hash = args[i+1]
@@ -81,11 +81,104 @@ func runMatchCommand() {
 			config.autoSelect = true
 		case "--recheck", "-r":
 			config.recheck = true
+		case "--wait", "-w":
+			config.recheck = true
+			config.waitRecheck = true
+		case "--revert-failed":
+			config.recheck = true
+			config.waitRecheck = true
+			config.revertFailed = true
+		case "--notify":
+			if i+1 < len(args) {
+				notifySpecs = append(notifySpecs, args[i+1])
+				i++
+			}
+		case "--size-tolerance":
+			if i+1 < len(args) {
+				tolerance, err := parseSize(args[i+1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: invalid --size-tolerance: %v\n", err)
+					os.Exit(1)
+				}
+				config.sizeTolerance = tolerance
+				i++
+			}
+		case "--verify-pieces":
+			config.verifyPieces = true
+		case "--partial-files":
+			config.partialFiles = true
+		case "--split-join":
+			config.splitJoin = true
+		case "--fingerprint":
+			config.fingerprints = true
+		case "--reference":
+			if i+1 < len(args) {
+				config.reference = args[i+1]
+				i++
+			}
+		case "--rules":
+			if i+1 < len(args) {
+				config.rulesProfile = args[i+1]
+				i++
+			}
+		case "--rules-file":
+			if i+1 < len(args) {
+				config.rulesFile = args[i+1]
+				i++
+			}
+		case "--reuse-duplicates":
+			config.reuse = true
+		case "--archives":
+			config.archives = true
+		case "--link":
+			config.link = true
+		case "--link-methods":
+			if i+1 < len(args) {
+				methods, err := backend.ParseLinkMethods(args[i+1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: invalid --link-methods: %v\n", err)
+					os.Exit(1)
+				}
+				config.link = true
+				config.linkMethods = methods
+				i++
+			}
+		case "--move":
+			config.move = true
+		case "--collision":
+			if i+1 < len(args) {
+				policy, err := backend.ParseCollisionPolicy(args[i+1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: invalid --collision: %v\n", err)
+					os.Exit(1)
+				}
+				config.collision = policy
+				i++
+			}
+		case "--save-path":
+			if i+1 < len(args) {
+				config.savePath = args[i+1]
+				i++
+			}
+		case "--tag":
+			config.postProcess.TagOutcome = true
+		case "--add-tags":
+			if i+1 < len(args) {
+				config.postProcess.Tags = splitList(args[i+1])
+				i++
+			}
+		case "--category":
+			if i+1 < len(args) {
+				config.postProcess.Category = args[i+1]
+				i++
+			}
+		case "--resume":
+			config.postProcess.Resume = true
 		}
 	}
 
 	// Validate required flags
-	if config.url == "" {
+	if config.conn.URL == "" {
 		fmt.Fprintln(os.Stderr, "Error: --url is required")
 		os.Exit(1)
 	}
@@ -103,6 +196,11 @@ func runMatchCommand() {
 		fmt.Fprintf(os.Stderr, "Error: path does not exist: %s\n", config.path)
 		os.Exit(1)
 	}
+	if config.link && config.move {
+		fmt.Fprintln(os.Stderr, "Error: --link and --move can't be combined")
+		os.Exit(1)
+	}
+	config.notifier = parseNotifyFlags(notifySpecs)
 
 	// Run the match
 	if execErr := executeMatch(config); execErr != nil {
@@ -112,19 +210,10 @@ func runMatchCommand() {
 }
 
 func executeMatch(config matchConfig) error {
-	// Connect to qBittorrent
-	fmt.Printf("Connecting to qBittorrent at %s...\n", config.url)
-
-	qbitService := &backend.QBitService{}
-	err := qbitService.Connect(backend.ConnectionConfig{
-		URL:      config.url,
-		Username: config.username,
-		Password: config.password,
-	})
+	qbitService, err := connectQBit(config.conn)
 	if err != nil {
-		return fmt.Errorf("failed to connect: %w", err)
+		return err
 	}
-	fmt.Println("Connected!")
 
 	// Get torrent files
 	fmt.Printf("Getting files for torrent %s...\n", config.hash)
@@ -142,6 +231,15 @@ func executeMatch(config matchConfig) error {
 	}
 	fmt.Printf("Found %d files on disk\n", len(diskFiles))
 
+	if config.archives {
+		entries, err := backend.ScanArchives(context.Background(), diskFiles)
+		if err != nil {
+			return fmt.Errorf("failed to scan archives: %w", err)
+		}
+		fmt.Printf("Found %d files in archives\n", len(entries))
+		diskFiles = append(diskFiles, entries...)
+	}
+
 	// Convert to backend types
 	torrentFileInfos := make([]backend.TorrentFileInfo, len(torrentFiles))
 	for i, f := range torrentFiles {
@@ -152,9 +250,55 @@ func executeMatch(config matchConfig) error {
 		}
 	}
 
+	if config.fingerprints || config.reference != "" {
+		fmt.Println("Fingerprinting files of the same size...")
+		if err := backend.FingerprintCandidates(context.Background(), torrentFileInfos, diskFiles, config.reference, backend.DefaultFingerprintCache()); err != nil {
+			return fmt.Errorf("failed to fingerprint files: %w", err)
+		}
+		if config.reference != "" {
+			references := 0
+			for _, tf := range torrentFileInfos {
+				if tf.Fingerprint != "" {
+					references++
+				}
+			}
+			fmt.Printf("Found %d reference copies in %s\n", references, config.reference)
+		}
+	}
+
+	rules, err := loadRulesFlags(config.rulesFile, config.rulesProfile)
+	if err != nil {
+		return err
+	}
+
 	// Find matches
 	fmt.Println("Finding matches...")
-	matchResult := backend.FindMatches(torrentFileInfos, diskFiles, config.sameExtension)
+	matchResult := backend.FindMatchesWithOptions(torrentFileInfos, diskFiles, backend.MatchOptions{
+		RequireSameExtension: config.sameExtension,
+		SizeTolerance:        config.sizeTolerance,
+		PartialFiles:         config.partialFiles,
+		SplitJoin:            config.splitJoin,
+		Rules:                rules,
+	})
+	if rules != nil {
+		mapped := 0
+		for _, m := range matchResult.Matches {
+			if m.Rule != "" {
+				mapped++
+			}
+		}
+		fmt.Printf("Rules profile %q mapped %d files\n", rules.Profile, mapped)
+		for _, e := range matchResult.RuleErrors {
+			fmt.Printf("  Rule error: %s\n", e)
+		}
+	}
+	if config.reuse {
+		report, err := qbitService.FindDuplicateFiles(true)
+		if err != nil {
+			return fmt.Errorf("failed to find duplicate files: %w", err)
+		}
+		fmt.Printf("Reused %d files other torrents have complete\n", backend.ReuseDuplicates(&matchResult, config.hash, report.Groups))
+	}
 
 	// Handle interactive selection for files with multiple candidates
 	if !config.autoSelect && !config.dryRun {
@@ -162,47 +306,95 @@ func executeMatch(config matchConfig) error {
 	}
 
 	fmt.Printf("Matched: %d, Unmatched: %d\n", matchResult.MatchedCount, len(matchResult.Unmatched))
+	if len(matchResult.Skipped) > 0 {
+		fmt.Printf("Skipped %d pad and empty files (qBittorrent creates empty files itself)\n", len(matchResult.Skipped))
+	}
 
-	// Generate renames
-	renames := backend.GenerateRenames(matchResult.Matches, config.path)
+	// Partial downloads are only worth renaming to when pieces verify, and
+	// joins and splits only when they line up with the torrent's pieces
+	if config.verifyPieces || backend.NeedsPieceVerification(matchResult.Matches) {
+		if report, ok := printPieceVerification(qbitService, config.hash, matchResult); ok {
+			if cleared := backend.UnselectUnsalvaged(matchResult.Matches, report); cleared > 0 {
+				fmt.Printf("Unselected %d partial files without a single verified piece\n", cleared)
+				matchResult.MatchedCount -= cleared
+			}
+			if cleared := backend.UnselectBrokenReassemblies(matchResult.Matches, report); cleared > 0 {
+				fmt.Printf("Unselected %d joined or split files with pieces that fail\n", cleared)
+				matchResult.MatchedCount -= cleared
+			}
+		}
+	}
 
-	renamesApplied := false
-	if len(renames) == 0 {
-		fmt.Println("No renames needed - all files already have correct paths")
-	} else {
-		fmt.Printf("\nRenames to apply (%d):\n", len(renames))
-		for _, r := range renames {
-			fmt.Printf("  %s\n    -> %s\n", r.OldPath, r.NewPath)
+	// Selected files inside archives are extracted before renaming to them
+	if extractions := backend.Extractions(matchResult.Matches); len(extractions) > 0 {
+		fmt.Printf("\nFiles to extract (%d):\n", len(extractions))
+		for _, e := range extractions {
+			fmt.Printf("  %s: %s\n    -> %s\n", e.Archive, e.Entry, e.Target)
 		}
 
 		if config.dryRun {
-			fmt.Println("\n[DRY RUN] No changes made")
+			fmt.Println("\n[DRY RUN] Nothing extracted")
 		} else {
-			fmt.Println("\nApplying renames...")
-			successCount := 0
-			errorCount := 0
-
-			for _, r := range renames {
-				err := qbitService.RenameFile(config.hash, r.OldPath, r.NewPath)
-				if err != nil {
-					fmt.Fprintf(os.Stderr, "  Failed to rename %s: %v\n", r.OldPath, err)
-					errorCount++
-				} else {
-					successCount++
-				}
+			fmt.Println("\nExtracting...")
+			result := backend.ExtractAll(context.Background(), extractions)
+			for _, e := range result.Errors {
+				fmt.Fprintf(os.Stderr, "  %s\n", e)
 			}
-
-			fmt.Printf("Renamed %d files successfully", successCount)
-			if errorCount > 0 {
-				fmt.Printf(", %d failed", errorCount)
+			fmt.Printf("Extracted %d files", result.ExtractedCount)
+			if result.FailedCount > 0 {
+				fmt.Printf(", %d failed and won't be renamed to", result.FailedCount)
+				matchResult.MatchedCount -= backend.UnselectExtractions(matchResult.Matches, result.Failed)
 			}
 			fmt.Println()
+		}
+	}
 
-			if successCount > 0 {
-				renamesApplied = true
+	// Selected files made of other disk files are joined or cut out of them
+	// before renaming to them
+	if rebuilds := backend.Rebuilds(matchResult.Matches); len(rebuilds) > 0 {
+		fmt.Printf("\nFiles to join or split (%d):\n", len(rebuilds))
+		for _, r := range rebuilds {
+			fmt.Printf("  %s\n    -> %s\n", describeParts(r.Parts), r.Target)
+		}
+
+		if config.dryRun {
+			fmt.Println("\n[DRY RUN] Nothing written")
+		} else {
+			fmt.Println("\nWriting files...")
+			result := backend.RebuildAll(context.Background(), rebuilds)
+			for _, e := range result.Errors {
+				fmt.Fprintf(os.Stderr, "  %s\n", e)
+			}
+			fmt.Printf("Wrote %d files", result.RebuiltCount)
+			if result.FailedCount > 0 {
+				fmt.Printf(", %d failed and won't be renamed to", result.FailedCount)
+				matchResult.MatchedCount -= backend.UnselectRebuilds(matchResult.Matches, result.Failed)
 			}
+			fmt.Println()
 		}
 	}
+	outcome := backend.ClassifyOutcome(matchResult.Matches, len(matchResult.Unmatched))
+
+	var applied backend.ApplyResult
+	var linked []backend.LinkOperation
+	var moved []backend.RenameOperation
+	failed := 0
+	switch {
+	case config.link:
+		linked, failed, err = linkMatches(qbitService, config, matchResult, outcome)
+		if err != nil {
+			return err
+		}
+	case config.move:
+		moved, failed, err = moveMatches(qbitService, config, matchResult, outcome)
+		if err != nil {
+			return err
+		}
+	default:
+		applied = renameMatches(qbitService, config, matchResult, torrentFileInfos, outcome)
+		failed = applied.FailedCount
+	}
+	outcome = applyOutcome(outcome, failed)
 
 	// Handle unmatched files
 	if len(matchResult.Unmatched) > 0 && config.skipUnmatched {
@@ -240,19 +432,506 @@ func executeMatch(config matchConfig) error {
 	}
 
 	// Trigger recheck if requested and changes were made
-	if config.recheck && renamesApplied && !config.dryRun {
+	if config.recheck && (len(applied.Applied) > 0 || len(linked) > 0 || len(moved) > 0) && !config.dryRun {
 		fmt.Println("\nTriggering torrent recheck...")
 		err := qbitService.RecheckTorrent(config.hash)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Failed to trigger recheck: %v\n", err)
+		} else if config.waitRecheck {
+			fmt.Println("Recheck started - waiting for qBittorrent to verify files...")
+			if !verifyRecheck(qbitService, config, applied, linked) {
+				outcome = backend.OutcomeNeedsReview
+			}
 		} else {
 			fmt.Println("Recheck started - qBittorrent will verify file integrity")
 		}
 	}
 
+	if outcome == backend.OutcomeNeedsReview && !config.dryRun {
+		sendNotification(config.notifier, backend.Notification{
+			Kind:    backend.NotifyNeedsReview,
+			Hash:    config.hash,
+			Outcome: outcome,
+		})
+	}
+
+	postProcessTorrent(qbitService, config, outcome)
+
 	return nil
 }
 
+// renameMatches renames the torrent's files in qBittorrent to the selected
+// disk files and returns what was applied
+func renameMatches(qbitService *backend.QBitService, config matchConfig, matchResult backend.MatchResult, torrentFileInfos []backend.TorrentFileInfo, outcome string) backend.ApplyResult {
+	// Generate renames, moving whole folders in one call where possible
+	renames := backend.GenerateRenames(matchResult.Matches, config.path)
+	renames = backend.CollapseFolderRenames(renames, torrentFileInfos)
+
+	var result backend.ApplyResult
+	if len(renames) == 0 {
+		fmt.Println("No renames needed - all files already have correct paths")
+	} else {
+		fmt.Printf("\nRenames to apply (%d):\n", len(renames))
+		for _, r := range renames {
+			if r.IsFolder {
+				fmt.Printf("  %s/ (folder, %d files)\n    -> %s/\n", r.OldPath, r.FileCount, r.NewPath)
+			} else {
+				fmt.Printf("  %s\n    -> %s\n", r.OldPath, r.NewPath)
+			}
+		}
+
+		if config.dryRun {
+			fmt.Println("\n[DRY RUN] No changes made")
+		} else {
+			fmt.Println("\nApplying renames...")
+			result = qbitService.ApplyRenames(config.hash, renames)
+			for _, e := range result.Errors {
+				fmt.Fprintf(os.Stderr, "  %s\n", e)
+			}
+
+			fmt.Printf("Renamed %d files successfully", result.RenamedCount)
+			if result.FailedCount > 0 {
+				fmt.Printf(", %d failed", result.FailedCount)
+			}
+			fmt.Println()
+			if result.JournalID != "" {
+				fmt.Printf("Undo with: qbt-file-matcher moves undo %s\n", result.JournalID)
+			}
+
+			sendNotification(config.notifier, backend.Notification{
+				Kind:         backend.NotifyApplied,
+				Hash:         config.hash,
+				Outcome:      applyOutcome(outcome, result.FailedCount),
+				RenamedCount: result.RenamedCount,
+				FailedCount:  result.FailedCount,
+				Message:      strings.Join(result.Errors, "\n"),
+			})
+		}
+	}
+
+	return result
+}
+
+// linkMatches links the selected disk files into the torrent's save path
+// under the torrent's own file names, leaving qBittorrent's file names and
+// the disk files untouched. It returns the links that were created and how
+// many failed.
+func linkMatches(qbitService *backend.QBitService, config matchConfig, matchResult backend.MatchResult, outcome string) ([]backend.LinkOperation, int, error) {
+	savePath, err := torrentSavePath(qbitService, config)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	links := backend.GenerateLinks(matchResult.Matches, savePath)
+	if len(links) == 0 {
+		fmt.Println("No links needed - all files are already in place")
+		return nil, 0, nil
+	}
+
+	fmt.Printf("\nLinks to create in %s (%d):\n", savePath, len(links))
+	for _, l := range links {
+		fmt.Printf("  %s\n    <- %s\n", l.TorrentFile.Name, l.Source)
+	}
+
+	if config.dryRun {
+		fmt.Println("\n[DRY RUN] No changes made")
+		return nil, 0, nil
+	}
+
+	fmt.Println("\nLinking files...")
+	result := backend.LinkFiles(context.Background(), links, config.linkMethods)
+	for _, e := range result.Errors {
+		fmt.Fprintf(os.Stderr, "  %s\n", e)
+	}
+
+	byMethod := make(map[string]int)
+	for _, l := range result.Linked {
+		byMethod[l.Method]++
+	}
+	var methods []string
+	for _, m := range append(backend.DefaultLinkMethods, backend.LinkExisting) {
+		if byMethod[m] > 0 {
+			methods = append(methods, fmt.Sprintf("%d %s", byMethod[m], m))
+		}
+	}
+	fmt.Printf("Linked %d files", result.LinkedCount)
+	if len(methods) > 0 {
+		fmt.Printf(" (%s)", strings.Join(methods, ", "))
+	}
+	if result.FailedCount > 0 {
+		fmt.Printf(", %d failed", result.FailedCount)
+	}
+	fmt.Println()
+
+	sendNotification(config.notifier, backend.Notification{
+		Kind:         backend.NotifyApplied,
+		Hash:         config.hash,
+		Outcome:      applyOutcome(outcome, result.FailedCount),
+		RenamedCount: result.LinkedCount,
+		FailedCount:  result.FailedCount,
+		Message:      strings.Join(append([]string{"Linked into " + savePath}, result.Errors...), "\n"),
+	})
+	return result.Linked, result.FailedCount, nil
+}
+
+// moveMatches moves the selected disk files into the torrent's save path
+// under the torrent's own file names, journaling every move so it can be
+// undone with the moves command. It returns the moves that were done and
+// how many failed or were skipped.
+func moveMatches(qbitService *backend.QBitService, config matchConfig, matchResult backend.MatchResult, outcome string) ([]backend.RenameOperation, int, error) {
+	savePath, err := torrentSavePath(qbitService, config)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	moves := backend.GenerateMoves(matchResult.Matches, savePath)
+	if len(moves) == 0 {
+		fmt.Println("No moves needed - all files are already in place")
+		return nil, 0, nil
+	}
+
+	fmt.Printf("\nFiles to move into %s (%d):\n", savePath, len(moves))
+	for _, m := range moves {
+		note := ""
+		if _, err := os.Lstat(m.NewPath); err == nil {
+			note = " (target exists)"
+		}
+		fmt.Printf("  %s\n    -> %s%s\n", m.OldPath, m.NewPath, note)
+	}
+
+	if config.dryRun {
+		fmt.Println("\n[DRY RUN] No changes made")
+		return nil, 0, nil
+	}
+
+	journalDir, err := backend.DefaultJournalDir()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to find journal directory: %w", err)
+	}
+
+	fmt.Println("\nMoving files...")
+	result, err := backend.MoveFiles(context.Background(), moves, backend.MoveOptions{
+		Hash:       config.hash,
+		Collision:  config.collision,
+		JournalDir: journalDir,
+	})
+	for _, e := range result.Errors {
+		fmt.Fprintf(os.Stderr, "  %s\n", e)
+	}
+	notMoved := result.SkippedCount + result.FailedCount
+	if err != nil {
+		return result.Moved, notMoved, err
+	}
+
+	fmt.Printf("Moved %d files", result.MovedCount)
+	if result.SkippedCount > 0 {
+		fmt.Printf(", %d skipped (use --collision backup to move existing files aside)", result.SkippedCount)
+	}
+	if result.FailedCount > 0 {
+		fmt.Printf(", %d failed", result.FailedCount)
+	}
+	fmt.Println()
+	if result.JournalID != "" {
+		fmt.Printf("Undo with: qbt-file-matcher moves undo %s\n", result.JournalID)
+	}
+
+	sendNotification(config.notifier, backend.Notification{
+		Kind:         backend.NotifyApplied,
+		Hash:         config.hash,
+		Outcome:      applyOutcome(outcome, notMoved),
+		RenamedCount: result.MovedCount,
+		FailedCount:  notMoved,
+		Message:      strings.Join(append([]string{"Moved into " + savePath}, result.Errors...), "\n"),
+	})
+	return result.Moved, notMoved, nil
+}
+
+// applyOutcome downgrades a match outcome to needs-review when files failed
+// to be renamed, linked or moved
+func applyOutcome(outcome string, failed int) string {
+	if failed > 0 {
+		return backend.OutcomeNeedsReview
+	}
+	return outcome
+}
+
+// torrentSavePath returns --save-path, or the save path qBittorrent reports
+func torrentSavePath(qbitService *backend.QBitService, config matchConfig) (string, error) {
+	if config.savePath != "" {
+		return config.savePath, nil
+	}
+	torrent, err := qbitService.GetTorrent(config.hash)
+	if err != nil {
+		return "", fmt.Errorf("failed to get torrent: %w", err)
+	}
+	return torrent.SavePath, nil
+}
+
+// postProcessTorrent tags, categorizes and resumes the torrent according to
+// the match outcome
+func postProcessTorrent(qbitService *backend.QBitService, config matchConfig, outcome string) {
+	opts := config.postProcess
+	tags := opts.TagsFor(outcome)
+	applyCategory := outcome == backend.OutcomeMatched && opts.Category != ""
+	applyResume := outcome == backend.OutcomeMatched && opts.Resume
+
+	if len(tags) == 0 && !applyCategory && !applyResume {
+		return
+	}
+
+	fmt.Printf("\nMatch outcome: %s\n", outcome)
+	if config.dryRun {
+		if len(tags) > 0 {
+			fmt.Printf("[DRY RUN] Would add tags: %s\n", strings.Join(tags, ", "))
+		}
+		if applyCategory {
+			fmt.Printf("[DRY RUN] Would set category: %s\n", opts.Category)
+		}
+		if applyResume {
+			fmt.Println("[DRY RUN] Would resume torrent")
+		}
+		return
+	}
+
+	if err := qbitService.PostProcess(config.hash, outcome, opts); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to post-process torrent: %v\n", err)
+		return
+	}
+
+	if len(tags) > 0 {
+		fmt.Printf("Added tags: %s\n", strings.Join(tags, ", "))
+	}
+	if applyCategory {
+		fmt.Printf("Set category: %s\n", opts.Category)
+	}
+	if applyResume {
+		fmt.Println("Resumed torrent")
+	}
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// notifySpecsFromEnv returns the space-separated notifier specs in QBT_NOTIFY
+func notifySpecsFromEnv() []string {
+	return strings.Fields(os.Getenv("QBT_NOTIFY"))
+}
+
+// parseNotifyFlags builds a notifier from --notify flags, falling back to
+// QBT_NOTIFY
+func parseNotifyFlags(specs []string) backend.Notifier {
+	if len(specs) == 0 {
+		specs = notifySpecsFromEnv()
+	}
+	if len(specs) == 0 {
+		return nil
+	}
+	notifiers, err := backend.ParseNotifiers(specs)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	return notifiers
+}
+
+// sendNotification delivers n, reporting failures without aborting
+func sendNotification(notifier backend.Notifier, n backend.Notification) {
+	if err := backend.SendNotification(notifier, n); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to send notification: %v\n", err)
+	}
+}
+
+// verifyRecheck waits for the recheck to finish, reports files that failed
+// verification and offers to revert their renames. It returns false when the
+// torrent did not fully verify.
+func verifyRecheck(qbitService *backend.QBitService, config matchConfig, result backend.ApplyResult, linked []backend.LinkOperation) bool {
+	applied := result.Applied
+	report, err := qbitService.WaitForRecheck(config.hash, 0)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to wait for recheck: %v\n", err)
+		return false
+	}
+	if report.TimedOut {
+		fmt.Fprintf(os.Stderr, "Timed out waiting for recheck (state: %s), results may be incomplete\n", report.State)
+	}
+
+	fmt.Printf("Verified: %d complete, %d incomplete\n", report.CompleteCount, report.IncompleteCount)
+
+	failed := report.Failed()
+	if len(failed) > 0 || report.TimedOut {
+		var names []string
+		for _, f := range failed {
+			names = append(names, f.Name)
+		}
+		sendNotification(config.notifier, backend.Notification{
+			Kind:    backend.NotifyRecheckFailed,
+			Hash:    config.hash,
+			Outcome: backend.OutcomeNeedsReview,
+			Message: strings.Join(names, "\n"),
+		})
+	}
+	if len(failed) == 0 {
+		return !report.TimedOut
+	}
+
+	reverts := backend.RevertRenames(applied, report)
+	reverted := make(map[string]bool, len(reverts))
+	for _, r := range reverts {
+		reverted[r.OldPath] = true
+	}
+	partial := make(map[string]bool)
+	for _, r := range applied {
+		if r.Partial {
+			partial[r.NewPath] = true
+		}
+	}
+
+	fmt.Printf("\nIncomplete files (%d):\n", len(failed))
+	for _, f := range failed {
+		note := ""
+		if reverted[f.Name] {
+			note = " - renamed, likely wrong match"
+		} else if partial[f.Name] {
+			note = " - partial download, resume to download the rest"
+		}
+		fmt.Printf("  %s (%.1f%%)%s\n", f.Name, f.Progress*100, note)
+	}
+
+	if len(linked) > 0 {
+		removeFailedLinks(config, linked, report)
+		return false
+	}
+	if len(reverts) == 0 {
+		return false
+	}
+
+	later := ""
+	if result.JournalID != "" {
+		later = fmt.Sprintf(", or later with: qbt-file-matcher moves revert %s", result.JournalID)
+	}
+	if !config.revertFailed {
+		if config.autoSelect {
+			fmt.Printf("\nUse --revert-failed to undo renames of files that failed verification%s\n", later)
+			return false
+		}
+		fmt.Printf("\nRevert %d rename(s) of files that failed verification? [y/N]: ", len(reverts))
+		input, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil || !strings.EqualFold(strings.TrimSpace(input), "y") {
+			if later != "" {
+				fmt.Printf("Revert them later with: qbt-file-matcher moves revert %s\n", result.JournalID)
+			}
+			return false
+		}
+	}
+
+	revertResult := qbitService.ApplyRenames(config.hash, reverts)
+	for _, e := range revertResult.Errors {
+		fmt.Fprintf(os.Stderr, "  %s\n", e)
+	}
+	fmt.Printf("Reverted %d of %d renames\n", revertResult.RenamedCount, len(reverts))
+	return false
+}
+
+// removeFailedLinks removes the links of files that failed verification
+// with --revert-failed
+func removeFailedLinks(config matchConfig, linked []backend.LinkOperation, report backend.RecheckReport) {
+	if !config.revertFailed {
+		fmt.Println("\nUse --revert-failed to remove links of files that failed verification")
+		return
+	}
+	removed, errs := backend.RemoveFailedLinks(linked, report)
+	for _, err := range errs {
+		fmt.Fprintf(os.Stderr, "  Failed to remove link: %v\n", err)
+	}
+	fmt.Printf("Removed %d links of files that failed verification\n", len(removed))
+}
+
+// connectionFromEnv returns connection settings from the QBT_* environment variables
+func connectionFromEnv() backend.ConnectionConfig {
+	insecure, _ := strconv.ParseBool(os.Getenv("QBT_INSECURE"))
+	return backend.ConnectionConfig{
+		URL:                os.Getenv("QBT_URL"),
+		Username:           os.Getenv("QBT_USERNAME"),
+		Password:           os.Getenv("QBT_PASSWORD"),
+		CACertFile:         os.Getenv("QBT_CA_CERT"),
+		InsecureSkipVerify: insecure,
+		ClientCertFile:     os.Getenv("QBT_CLIENT_CERT"),
+		ClientKeyFile:      os.Getenv("QBT_CLIENT_KEY"),
+		BasicUsername:      os.Getenv("QBT_BASIC_USER"),
+		BasicPassword:      os.Getenv("QBT_BASIC_PASSWORD"),
+	}
+}
+
+// parseConnectionFlag handles a connection flag at args[i]. It returns the
+// index of the last argument consumed and whether args[i] was a connection flag.
+func parseConnectionFlag(args []string, i int, conn *backend.ConnectionConfig) (int, bool) {
+	var target *string
+	switch args[i] {
+	case "--url":
+		target = &conn.URL
+	case "--username", "-u":
+		target = &conn.Username
+	case "--password", "-p":
+		target = &conn.Password
+	case "--ca-cert":
+		target = &conn.CACertFile
+	case "--client-cert":
+		target = &conn.ClientCertFile
+	case "--client-key":
+		target = &conn.ClientKeyFile
+	case "--basic-user":
+		target = &conn.BasicUsername
+	case "--basic-password":
+		target = &conn.BasicPassword
+	case "--insecure", "-k":
+		conn.InsecureSkipVerify = true
+		return i, true
+	case "--header", "-H":
+		if i+1 < len(args) {
+			name, value, err := backend.ParseHeader(args[i+1])
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+			if conn.Headers == nil {
+				conn.Headers = make(map[string]string)
+			}
+			conn.Headers[name] = value
+			i++
+		}
+		return i, true
+	default:
+		return i, false
+	}
+
+	if i+1 < len(args) {
+		*target = args[i+1]
+		i++
+	}
+	return i, true
+}
+
+// connectQBit connects to qBittorrent, reporting progress on stdout
+func connectQBit(conn backend.ConnectionConfig) (*backend.QBitService, error) {
+	fmt.Printf("Connecting to qBittorrent at %s...\n", conn.URL)
+
+	qbitService := &backend.QBitService{JournalDir: renameJournalDir()}
+	if err := qbitService.Connect(conn); err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+	fmt.Println("Connected!")
+
+	return qbitService, nil
+}
+
 // handleInteractiveSelection prompts user to select files when there are multiple candidates
 func handleInteractiveSelection(matchResult backend.MatchResult) backend.MatchResult {
 	reader := bufio.NewReader(os.Stdin)
@@ -260,16 +939,32 @@ func handleInteractiveSelection(matchResult backend.MatchResult) backend.MatchRe
 	for i := range matchResult.Matches {
 		match := &matchResult.Matches[i]
 
-		// Skip if already matched or no candidates
-		if match.Selected != nil || len(match.DiskFiles) <= 1 {
+		// Skip if already matched or there is nothing to choose from.
+		// Candidates that only match within the size tolerance and partial
+		// downloads are always confirmed.
+		if match.Selected != nil || (len(match.DiskFiles) <= 1 && !match.SizeMismatch && !match.Partial) {
 			continue
 		}
 
-		fmt.Printf("\nMultiple matches found for: %s (%s)\n", match.TorrentFile.Name, formatSize(match.TorrentFile.Size))
-		fmt.Println("Select a file:")
+		if match.Partial {
+			fmt.Printf("\nNo file has the size of: %s (%s)\n", match.TorrentFile.Name, formatSize(match.TorrentFile.Size))
+			fmt.Println("Select a partial download to resume:")
+		} else if match.SizeMismatch {
+			fmt.Printf("\nNo exact size match for: %s (%s)\n", match.TorrentFile.Name, formatSize(match.TorrentFile.Size))
+			fmt.Println("Select a file with a similar size (size mismatch):")
+		} else {
+			fmt.Printf("\nMultiple matches found for: %s (%s)\n", match.TorrentFile.Name, formatSize(match.TorrentFile.Size))
+			fmt.Println("Select a file:")
+		}
 
 		for j, df := range match.DiskFiles {
-			fmt.Printf("  [%d] %s\n", j+1, df.Path)
+			if match.Partial {
+				fmt.Printf("  [%d] %s (%s, %.0f%%)\n", j+1, describeDiskFile(df), formatSize(df.Size), percentOf(df.Size, match.TorrentFile.Size))
+			} else if match.SizeMismatch {
+				fmt.Printf("  [%d] %s (%+d bytes)\n", j+1, describeDiskFile(df), df.Size-match.TorrentFile.Size)
+			} else {
+				fmt.Printf("  [%d] %s\n", j+1, describeDiskFile(df))
+			}
 		}
 		fmt.Printf("  [0] Skip this file\n")
 		fmt.Print("Enter choice: ")
@@ -300,6 +995,106 @@ func handleInteractiveSelection(matchResult backend.MatchResult) backend.MatchRe
 	return matchResult
 }
 
+// describeDiskFile returns the path of a disk file, naming the archive it
+// would be extracted from
+func describeDiskFile(df backend.DiskFile) string {
+	if df.IsArchiveEntry() {
+		return fmt.Sprintf("%s (in %s)", df.Path, filepath.Base(df.Archive))
+	}
+	if df.IsReassembled() {
+		return fmt.Sprintf("%s (%s)", df.Path, describeParts(df.Parts))
+	}
+	if len(df.Copies) > 0 {
+		return fmt.Sprintf("%s (and %d identical copies)", df.Path, len(df.Copies))
+	}
+	return df.Path
+}
+
+// describeParts describes the disk file ranges a file is rebuilt from
+func describeParts(parts []backend.FileSegment) string {
+	if len(parts) == 1 {
+		p := parts[0]
+		return fmt.Sprintf("bytes %d-%d of %s", p.Offset, p.Offset+p.Size, p.Path)
+	}
+	return fmt.Sprintf("%s and %d more parts joined", parts[0].Path, len(parts)-1)
+}
+
+// printPieceVerification hashes the selected files against the torrent's
+// pieces and reports how many would pass a recheck
+func printPieceVerification(qbitService *backend.QBitService, hash string, matchResult backend.MatchResult) (backend.PieceReport, bool) {
+	fmt.Println("\nVerifying pieces...")
+	layout, err := qbitService.GetPieceLayout(hash)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to verify pieces: %v\n", err)
+		return backend.PieceReport{}, false
+	}
+	report, err := backend.VerifySources(context.Background(), layout, backend.SelectedSources(matchResult.Matches))
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to verify pieces: %v\n", err)
+		return backend.PieceReport{}, false
+	}
+
+	fmt.Printf("Pieces: %d of %d would pass, %d fail, %d not checked (no file selected)\n",
+		report.Passed, report.TotalPieces, report.Failed, report.Unchecked)
+
+	mismatched := make(map[int]bool)
+	partial := make(map[int]bool)
+	rebuilt := make(map[int]bool)
+	for _, m := range matchResult.Matches {
+		if m.Selected != nil && m.Selected.IsReassembled() {
+			rebuilt[m.TorrentFile.Index] = true
+		}
+		if m.SizeMismatch && m.Selected != nil {
+			mismatched[m.TorrentFile.Index] = true
+		}
+		if m.Partial && m.Selected != nil {
+			partial[m.TorrentFile.Index] = true
+		}
+	}
+	for _, f := range report.Files {
+		switch {
+		case mismatched[f.Index]:
+			fmt.Printf("  %s (size mismatch): %d of %d pieces pass\n", f.Name, f.Passed, f.Pieces)
+		case partial[f.Index]:
+			fmt.Printf("  %s (partial): %d of %d pieces salvaged\n", f.Name, f.Passed, f.Pieces)
+		case rebuilt[f.Index]:
+			fmt.Printf("  %s (joined or split): %d of %d pieces pass\n", f.Name, f.Passed, f.Pieces)
+		}
+	}
+	return report, true
+}
+
+// percentOf returns part as a percentage of total
+func percentOf(part, total int64) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(part) * 100 / float64(total)
+}
+
+// parseSize parses a byte count with an optional K, M or G suffix (powers of 1024)
+func parseSize(value string) (int64, error) {
+	multiplier := int64(1)
+	number := strings.ToUpper(strings.TrimSpace(value))
+	number = strings.TrimSuffix(number, "B")
+	switch {
+	case strings.HasSuffix(number, "K"):
+		multiplier = 1 << 10
+	case strings.HasSuffix(number, "M"):
+		multiplier = 1 << 20
+	case strings.HasSuffix(number, "G"):
+		multiplier = 1 << 30
+	}
+	if multiplier > 1 {
+		number = number[:len(number)-1]
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(number), 10, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%q is not a size", value)
+	}
+	return n * multiplier, nil
+}
+
 func formatSize(bytes int64) string {
 	if bytes == 0 {
 		return "0 B"
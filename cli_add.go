@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"qbt-file-matcher/backend"
+)
+
+// CLI config for add command
+type addConfig struct {
+	conn          backend.ConnectionConfig
+	torrentPath   string
+	path          string
+	sameExtension bool
+	skipUnmatched bool
+	dryRun        bool
+	autoSelect    bool
+	resume        bool // Resume once the recheck verified every wanted file
+	category      string
+	tags          []string
+}
+
+func runAddCommand() {
+	config := addConfig{
+		conn:          connectionFromEnv(),
+		sameExtension: true,
+	}
+
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		if next, ok := parseConnectionFlag(args, i, &config.conn); ok {
+			i = next
+			continue
+		}
+
+		switch args[i] {
+		case "--torrent", "-t":
+			if i+1 < len(args) {
+				config.torrentPath = args[i+1]
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				config.path = args[i+1]
+				i++
+			}
+		case "--category":
+			if i+1 < len(args) {
+				config.category = args[i+1]
+				i++
+			}
+		case "--add-tags":
+			if i+1 < len(args) {
+				config.tags = splitList(args[i+1])
+				i++
+			}
+		case "--same-ext":
+			config.sameExtension = true
+		case "--no-same-ext":
+			config.sameExtension = false
+		case "--skip-unmatched":
+			config.skipUnmatched = true
+		case "--dry-run":
+			config.dryRun = true
+		case "--auto", "-a":
+			config.autoSelect = true
+		case "--resume":
+			config.resume = true
+		default:
+			// Allow the .torrent to be given as a bare argument
+			if config.torrentPath == "" && strings.HasSuffix(strings.ToLower(args[i]), ".torrent") {
+				config.torrentPath = args[i]
+			}
+		}
+	}
+
+	if config.torrentPath == "" {
+		fmt.Fprintln(os.Stderr, "Error: --torrent is required")
+		os.Exit(1)
+	}
+	if config.path == "" {
+		fmt.Fprintln(os.Stderr, "Error: --path is required")
+		os.Exit(1)
+	}
+	if config.conn.URL == "" && !config.dryRun {
+		fmt.Fprintln(os.Stderr, "Error: --url is required")
+		os.Exit(1)
+	}
+	if _, err := os.Stat(config.path); os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error: path does not exist: %s\n", config.path)
+		os.Exit(1)
+	}
+
+	if err := executeAdd(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func executeAdd(config addConfig) error {
+	meta, err := backend.ParseTorrentFile(config.torrentPath)
+	if err != nil {
+		return fmt.Errorf("failed to read torrent: %w", err)
+	}
+	torrentFiles := meta.TorrentFiles()
+	fmt.Printf("Torrent: %s (%s, %d files, hash %s)\n", meta.Name, formatSize(meta.TotalSize), len(torrentFiles), meta.InfoHash)
+
+	fmt.Printf("Scanning directory %s...\n", config.path)
+	diskFiles, err := backend.ScanDirectory(config.path)
+	if err != nil {
+		return fmt.Errorf("failed to scan directory: %w", err)
+	}
+	fmt.Printf("Found %d files on disk\n", len(diskFiles))
+
+	matchResult := backend.FindMatches(torrentFiles, diskFiles, config.sameExtension)
+	if !config.autoSelect && !config.dryRun {
+		matchResult = handleInteractiveSelection(matchResult)
+	}
+	fmt.Printf("Matched: %d, Unmatched: %d\n", matchResult.MatchedCount, len(matchResult.Unmatched))
+	if len(matchResult.Skipped) > 0 {
+		fmt.Printf("Skipped %d pad and empty files (qBittorrent creates empty files itself)\n", len(matchResult.Skipped))
+	}
+
+	if matchResult.MatchedCount == 0 {
+		return fmt.Errorf("no files matched, not adding torrent")
+	}
+
+	renames := backend.GenerateRenames(matchResult.Matches, config.path)
+	renames = backend.CollapseFolderRenames(renames, torrentFiles)
+
+	// Files without a selected match can't be seeded from disk
+	var unwanted []string
+	if config.skipUnmatched {
+		for _, f := range matchResult.Unmatched {
+			unwanted = append(unwanted, f.Name)
+		}
+		for _, m := range matchResult.Matches {
+			if m.Selected == nil {
+				unwanted = append(unwanted, m.TorrentFile.Name)
+			}
+		}
+	}
+
+	fmt.Printf("\nRenames to apply (%d):\n", len(renames))
+	for _, r := range renames {
+		if r.IsFolder {
+			fmt.Printf("  %s/ (folder, %d files)\n    -> %s/\n", r.OldPath, r.FileCount, r.NewPath)
+		} else {
+			fmt.Printf("  %s\n    -> %s\n", r.OldPath, r.NewPath)
+		}
+	}
+	if len(unwanted) > 0 {
+		fmt.Printf("Files to skip (%d):\n", len(unwanted))
+		for _, name := range unwanted {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+
+	if config.dryRun {
+		fmt.Printf("\n[DRY RUN] Would add torrent paused with save path %s\n", config.path)
+		return nil
+	}
+
+	qbitService, err := connectQBit(config.conn)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Adding torrent...")
+	if config.resume {
+		fmt.Println("Waiting for qBittorrent to verify files before resuming...")
+	}
+	result, err := qbitService.AddMatchedTorrent(backend.AddTorrentRequest{
+		TorrentPath: config.torrentPath,
+		SavePath:    config.path,
+		Renames:     renames,
+		Unwanted:    unwanted,
+		Category:    config.category,
+		Tags:        config.tags,
+		Recheck:     true,
+		Resume:      config.resume,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Added torrent %s\n", result.Hash)
+	fmt.Printf("Renamed %d files successfully", result.RenamedCount)
+	if result.FailedCount > 0 {
+		fmt.Printf(", %d failed", result.FailedCount)
+	}
+	fmt.Println()
+	if result.SkippedCount > 0 {
+		fmt.Printf("Set priority to 0 for %d files\n", result.SkippedCount)
+	}
+
+	if result.Report != nil {
+		fmt.Printf("Verified: %d complete, %d incomplete\n", result.Report.CompleteCount, result.Report.IncompleteCount)
+		for _, f := range result.Report.Failed() {
+			fmt.Printf("  %s (%.1f%%)\n", f.Name, f.Progress*100)
+		}
+	}
+
+	switch {
+	case result.Resumed:
+		fmt.Println("Torrent resumed")
+	case config.resume:
+		fmt.Println("Torrent left paused - not every file verified")
+	default:
+		fmt.Println("Recheck started - torrent left paused")
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"qbt-file-matcher/backend"
+)
+
+// CLI config for duplicates command
+type duplicatesConfig struct {
+	conn         backend.ConnectionConfig
+	verifyPieces bool
+}
+
+func runDuplicatesCommand() {
+	config := duplicatesConfig{conn: connectionFromEnv()}
+
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		if next, ok := parseConnectionFlag(args, i, &config.conn); ok {
+			i = next
+			continue
+		}
+
+		switch args[i] {
+		case "--verify-pieces":
+			config.verifyPieces = true
+		}
+	}
+
+	if config.conn.URL == "" {
+		fmt.Fprintln(os.Stderr, "Error: --url is required")
+		os.Exit(1)
+	}
+
+	if err := executeDuplicates(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func executeDuplicates(config duplicatesConfig) error {
+	qbitService, err := connectQBit(config.conn)
+	if err != nil {
+		return err
+	}
+
+	if config.verifyPieces {
+		fmt.Println("Comparing piece hashes of torrents sharing file sizes...")
+	}
+	report, err := qbitService.FindDuplicateFiles(config.verifyPieces)
+	if err != nil {
+		return err
+	}
+
+	if len(report.Groups) == 0 {
+		fmt.Printf("No files shared between %d torrents\n", report.Torrents)
+		return nil
+	}
+
+	torrents := make(map[string]bool)
+	for _, g := range report.Groups {
+		verified := "same name and size"
+		if g.Verified {
+			verified = "identical pieces"
+		}
+		fmt.Printf("\n%s (%s, %s)\n", g.Files[0].Name, formatSize(g.Size), verified)
+		for _, f := range g.Files {
+			torrents[f.Hash] = true
+			status := fmt.Sprintf("%.0f%%", f.Progress*100)
+			if f.Complete() {
+				status = "complete"
+			}
+			fmt.Printf("  %-9s %s: %s\n", status, f.TorrentName, f.Name)
+		}
+	}
+	fmt.Printf("\n%d shared files across %d of %d torrents\n", len(report.Groups), len(torrents), report.Torrents)
+	return nil
+}
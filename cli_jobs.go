@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"qbt-file-matcher/backend"
+)
+
+// newJobManager opens the persisted job store shared by the GUI, the server
+// and the CLI, falling back to keeping jobs in memory
+func newJobManager() *backend.JobManager {
+	dir, err := backend.DefaultJobDir()
+	if err == nil {
+		var manager *backend.JobManager
+		if manager, err = backend.NewJobManager(dir); err == nil {
+			return manager
+		}
+	}
+	log.Printf("Job history unavailable, keeping jobs in memory: %v", err)
+	manager, _ := backend.NewJobManager("")
+	return manager
+}
+
+func runJobsCommand() {
+	args := os.Args[2:]
+	subcommand := "list"
+	if len(args) > 0 {
+		subcommand = args[0]
+	}
+
+	jobs := newJobManager()
+	var err error
+
+	switch subcommand {
+	case "list":
+		listJobs(jobs.List())
+	case "show", "cancel", "remove":
+		if len(args) < 2 {
+			fmt.Fprintf(os.Stderr, "Error: job ID is required\n\n")
+			printJobsHelp()
+			os.Exit(1)
+		}
+		id := args[1]
+		switch subcommand {
+		case "show":
+			err = showJob(jobs, id)
+		case "cancel":
+			if err = jobs.Cancel(id); err == nil {
+				fmt.Printf("Cancellation requested for job %s\n", id)
+			}
+		case "remove":
+			if err = jobs.Remove(id); err == nil {
+				fmt.Printf("Removed job %s\n", id)
+			}
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown jobs command '%s'\n\n", subcommand)
+		printJobsHelp()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func listJobs(jobs []backend.Job) {
+	if len(jobs) == 0 {
+		fmt.Println("No jobs")
+		return
+	}
+
+	fmt.Printf("%-14s %-6s %-12s %-8s %-19s %s\n", "ID", "KIND", "STATUS", "PROGRESS", "CREATED", "DESCRIPTION")
+	for _, job := range jobs {
+		progress := "-"
+		if job.Progress >= 0 {
+			progress = fmt.Sprintf("%.0f%%", job.Progress*100)
+		}
+		fmt.Printf("%-14s %-6s %-12s %-8s %-19s %s\n",
+			job.ID, job.Kind, job.Status, progress, job.Created.Format(time.DateTime), job.Description)
+	}
+}
+
+func showJob(jobs *backend.JobManager, id string) error {
+	job, err := jobs.Get(id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Job %s: %s\n", job.ID, job.Description)
+	fmt.Printf("Status:  %s\n", job.Status)
+	if job.Message != "" {
+		fmt.Printf("Message: %s\n", job.Message)
+	}
+	if job.Error != "" {
+		fmt.Printf("Error:   %s\n", job.Error)
+	}
+	fmt.Printf("Created: %s\n", job.Created.Format(time.DateTime))
+	if job.Finished != nil {
+		fmt.Printf("Took:    %s\n", job.Finished.Sub(job.Created).Round(time.Millisecond))
+	}
+	if len(job.Logs) > 0 {
+		fmt.Println("\nLog:")
+		for _, line := range job.Logs {
+			fmt.Printf("  %s\n", line)
+		}
+	}
+	return nil
+}
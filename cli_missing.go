@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"qbt-file-matcher/backend"
+)
+
+// CLI config for missing command
+type missingConfig struct {
+	conn  backend.ConnectionConfig
+	roots []string // scanned for candidates
+	opts  backend.MatchOptions
+}
+
+func runMissingCommand() {
+	config := missingConfig{
+		conn: connectionFromEnv(),
+		opts: backend.MatchOptions{RequireSameExtension: true},
+	}
+
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		if next, ok := parseConnectionFlag(args, i, &config.conn); ok {
+			i = next
+			continue
+		}
+
+		switch args[i] {
+		case "--root":
+			if i+1 < len(args) {
+				config.roots = append(config.roots, args[i+1])
+				i++
+			}
+		case "--same-ext":
+			config.opts.RequireSameExtension = true
+		case "--no-same-ext":
+			config.opts.RequireSameExtension = false
+		}
+	}
+
+	if config.conn.URL == "" {
+		fmt.Fprintln(os.Stderr, "Error: --url is required")
+		os.Exit(1)
+	}
+	for _, root := range config.roots {
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error: root does not exist: %s\n", root)
+			os.Exit(1)
+		}
+	}
+
+	if err := executeMissing(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func executeMissing(config missingConfig) error {
+	qbitService, err := connectQBit(config.conn)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("Checking every torrent's files on disk...")
+	report, err := qbitService.FindMissingFiles(context.Background(), config.roots, config.opts)
+	if err != nil {
+		return err
+	}
+
+	if report.FileCount == 0 {
+		fmt.Printf("No missing files in %d torrents\n", report.Checked)
+		return nil
+	}
+
+	for _, mt := range report.Torrents {
+		fmt.Printf("\n%s (%s)\n", mt.Torrent.Name, mt.Torrent.Hash)
+		fmt.Printf("  %d of %d files missing in %s\n", len(mt.Files), mt.TotalFiles, mt.Torrent.SavePath)
+		for _, f := range mt.Files {
+			status := "missing"
+			if f.WrongSize() {
+				status = fmt.Sprintf("wrong size, %s on disk", formatSize(f.DiskSize))
+			}
+			fmt.Printf("  %s (%s): %s\n", f.Name, formatSize(f.Size), status)
+			for i, c := range f.Candidates {
+				marker := " "
+				if i == 0 && f.AutoMatch {
+					marker = "*"
+				}
+				fmt.Printf("     %s %s\n", marker, describeDiskFile(c))
+			}
+		}
+		if len(config.roots) == 1 {
+			fmt.Printf("  Fix with: qbt-file-matcher match --hash %s --path %s\n", mt.Torrent.Hash, config.roots[0])
+		}
+	}
+
+	fmt.Printf("\n%d files missing in %d of %d torrents\n", report.FileCount, len(report.Torrents), report.Checked)
+	if len(config.roots) > 0 {
+		fmt.Println("* selected automatically by match")
+	}
+	return nil
+}
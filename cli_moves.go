@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"qbt-file-matcher/backend"
+)
+
+func runMovesCommand() {
+	args := os.Args[2:]
+	subcommand := "list"
+	if len(args) > 0 {
+		subcommand = args[0]
+	}
+
+	dir, err := backend.DefaultJournalDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch subcommand {
+	case "list":
+		err = listMoves(dir)
+	case "show", "undo", "revert":
+		if len(args) < 2 {
+			fmt.Fprintf(os.Stderr, "Error: journal ID is required\n\n")
+			printMovesHelp()
+			os.Exit(1)
+		}
+		var journal *backend.MoveJournal
+		if journal, err = backend.LoadJournal(dir, args[1]); err == nil {
+			switch {
+			case subcommand == "show":
+				showMoves(journal)
+			case journal.IsRenames():
+				err = revertRenames(journal, subcommand == "undo", args[2:])
+			case subcommand == "undo":
+				err = undoMoves(journal)
+			default:
+				err = fmt.Errorf("journal %s holds moves on disk, use 'moves undo'", journal.ID)
+			}
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown moves command '%s'\n\n", subcommand)
+		printMovesHelp()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// renameJournalDir returns where applied renames are journaled, or "" when
+// there is no config directory
+func renameJournalDir() string {
+	dir, err := backend.DefaultJournalDir()
+	if err != nil {
+		log.Printf("Renames won't be journaled: %v", err)
+		return ""
+	}
+	return dir
+}
+
+func listMoves(dir string) error {
+	journals, err := backend.ListJournals(dir)
+	if err != nil {
+		return err
+	}
+	if len(journals) == 0 {
+		fmt.Println("No moves")
+		return nil
+	}
+
+	fmt.Printf("%-24s %-19s %-7s %-6s %-7s %s\n", "ID", "TIME", "KIND", "FILES", "UNDONE", "TORRENT")
+	for _, j := range journals {
+		kind, files := "moves", len(j.Steps)
+		if j.IsRenames() {
+			kind, files = "renames", len(j.Renames)
+		}
+		fmt.Printf("%-24s %-19s %-7s %-6d %-7t %s\n", j.ID, j.Time.Format(time.DateTime), kind, files, j.Undone, j.Hash)
+	}
+	return nil
+}
+
+func showMoves(journal *backend.MoveJournal) {
+	kind := "Moves"
+	if journal.IsRenames() {
+		kind = "Renames"
+	}
+	fmt.Printf("%s %s for torrent %s\n", kind, journal.ID, journal.Hash)
+	fmt.Printf("Time:   %s\n", journal.Time.Format(time.DateTime))
+	fmt.Printf("Undone: %t\n", journal.Undone)
+	fmt.Println("\nSteps:")
+	for _, s := range journal.Steps {
+		fmt.Printf("  %s\n    -> %s\n", s.From, s.To)
+	}
+	for _, r := range journal.Renames {
+		if r.IsFolder {
+			fmt.Printf("  %s/ (folder, %d files)\n    -> %s/\n", r.OldPath, r.FileCount, r.NewPath)
+		} else {
+			fmt.Printf("  %s\n    -> %s\n", r.OldPath, r.NewPath)
+		}
+	}
+}
+
+func undoMoves(journal *backend.MoveJournal) error {
+	total := len(journal.Steps)
+	fmt.Printf("Undoing %d moves of %s...\n", total, journal.ID)
+	undone, errs, err := backend.UndoMoves(context.Background(), journal)
+	for _, e := range errs {
+		fmt.Fprintf(os.Stderr, "  %s\n", e)
+	}
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Moved back %d of %d files\n", undone, total)
+	if undone < total {
+		fmt.Printf("Retry the remaining moves with: qbt-file-matcher moves undo %s\n", journal.ID)
+	}
+	return nil
+}
+
+// revertRenames renames a journal's files back in qBittorrent: all of them
+// with undo, or those that fail verification since the last recheck
+func revertRenames(journal *backend.MoveJournal, all bool, args []string) error {
+	conn := connectionFromEnv()
+	for i := 0; i < len(args); i++ {
+		if next, ok := parseConnectionFlag(args, i, &conn); ok {
+			i = next
+		}
+	}
+	if conn.URL == "" {
+		return fmt.Errorf("--url is required to revert renames in qBittorrent")
+	}
+	qbitService, err := connectQBit(conn)
+	if err != nil {
+		return err
+	}
+
+	if all {
+		fmt.Printf("Undoing %d renames of %s...\n", len(journal.Renames), journal.ID)
+		result, err := qbitService.UndoRenameJournal(journal)
+		for _, e := range result.Errors {
+			fmt.Fprintf(os.Stderr, "  %s\n", e)
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Renamed back %d files, %d failed\n", result.RenamedCount, result.FailedCount)
+		if result.FailedCount > 0 {
+			fmt.Printf("Retry the failed renames with: qbt-file-matcher moves undo %s\n", journal.ID)
+		}
+		return nil
+	}
+
+	reverts, report, err := qbitService.JournalReverts(journal)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Verified: %d complete, %d incomplete\n", report.CompleteCount, report.IncompleteCount)
+	if len(reverts) == 0 {
+		fmt.Println("No renamed files fail verification - recheck the torrent first if it wasn't")
+		return nil
+	}
+
+	fmt.Printf("\nRenames to revert (%d):\n", len(reverts))
+	for _, r := range reverts {
+		fmt.Printf("  %s\n    -> %s\n", r.OldPath, r.NewPath)
+	}
+	result := qbitService.ApplyRenames(journal.Hash, reverts)
+	for _, e := range result.Errors {
+		fmt.Fprintf(os.Stderr, "  %s\n", e)
+	}
+	fmt.Printf("Reverted %d of %d renames\n", result.RenamedCount, len(reverts))
+	if result.JournalID != "" {
+		fmt.Printf("Undo with: qbt-file-matcher moves undo %s\n", result.JournalID)
+	}
+	return nil
+}
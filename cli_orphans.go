@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"qbt-file-matcher/backend"
+)
+
+// CLI config for orphans command
+type orphansConfig struct {
+	conn   backend.ConnectionConfig
+	roots  []string
+	export string // .csv or .json file to save the report to
+	trash  string // directory orphans are moved to
+	dryRun bool
+	yes    bool // move to the trash without asking
+}
+
+func runOrphansCommand() {
+	config := orphansConfig{conn: connectionFromEnv()}
+
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		if next, ok := parseConnectionFlag(args, i, &config.conn); ok {
+			i = next
+			continue
+		}
+
+		switch args[i] {
+		case "--root":
+			if i+1 < len(args) {
+				config.roots = append(config.roots, args[i+1])
+				i++
+			}
+		case "--export":
+			if i+1 < len(args) {
+				config.export = args[i+1]
+				i++
+			}
+		case "--trash":
+			if i+1 < len(args) {
+				config.trash = args[i+1]
+				i++
+			}
+		case "--dry-run":
+			config.dryRun = true
+		case "--yes", "-y":
+			config.yes = true
+		}
+	}
+
+	if config.conn.URL == "" {
+		fmt.Fprintln(os.Stderr, "Error: --url is required")
+		os.Exit(1)
+	}
+	if len(config.roots) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: at least one --root is required")
+		os.Exit(1)
+	}
+	for _, root := range config.roots {
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error: root does not exist: %s\n", root)
+			os.Exit(1)
+		}
+	}
+
+	if err := executeOrphans(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func executeOrphans(config orphansConfig) error {
+	qbitService, err := connectQBit(config.conn)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Comparing %s with every torrent's files...\n", strings.Join(config.roots, ", "))
+	report, err := qbitService.FindOrphanFiles(context.Background(), config.roots, config.trash)
+	if err != nil {
+		return err
+	}
+
+	if report.FileCount == 0 {
+		fmt.Printf("No orphans - every one of the %d files belongs to a torrent\n", report.Referenced)
+	} else {
+		for _, d := range report.Dirs {
+			fmt.Printf("\n%s (%d files, %s)\n", d.Dir, len(d.Files), formatSize(d.Size))
+			for _, f := range d.Files {
+				fmt.Printf("  %s (%s)\n", f.Name, formatSize(f.Size))
+			}
+		}
+		fmt.Printf("\n%d orphans in %d folders, %s; %d files belong to torrents\n",
+			report.FileCount, len(report.Dirs), formatSize(report.TotalSize), report.Referenced)
+	}
+
+	if config.export != "" {
+		if err := backend.ExportOrphans(report, config.export); err != nil {
+			return fmt.Errorf("failed to export report: %w", err)
+		}
+		fmt.Printf("Report saved to %s\n", config.export)
+	}
+
+	if config.trash == "" || report.FileCount == 0 {
+		return nil
+	}
+	return trashOrphans(config, report)
+}
+
+func trashOrphans(config orphansConfig, report backend.OrphanReport) error {
+	moves := backend.TrashMoves(report.Files(), report.Roots, config.trash)
+	if config.dryRun {
+		fmt.Printf("\nFiles to move to %s (%d):\n", config.trash, len(moves))
+		for _, m := range moves {
+			fmt.Printf("  %s\n    -> %s\n", m.OldPath, m.NewPath)
+		}
+		fmt.Println("\n[DRY RUN] No changes made")
+		return nil
+	}
+
+	if !config.yes {
+		fmt.Printf("\nMove %d orphans (%s) to %s? [y/N]: ", len(moves), formatSize(report.TotalSize), config.trash)
+		input, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil || !strings.EqualFold(strings.TrimSpace(input), "y") {
+			fmt.Println("Nothing moved")
+			return nil
+		}
+	}
+
+	journalDir, err := backend.DefaultJournalDir()
+	if err != nil {
+		return fmt.Errorf("failed to find journal directory: %w", err)
+	}
+	result, err := backend.TrashOrphans(context.Background(), report.Files(), report.Roots, config.trash, journalDir)
+	for _, e := range result.Errors {
+		fmt.Fprintf(os.Stderr, "  %s\n", e)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Moved %d files to %s", result.MovedCount, config.trash)
+	if result.SkippedCount > 0 {
+		fmt.Printf(", %d skipped (already in the trash)", result.SkippedCount)
+	}
+	if result.FailedCount > 0 {
+		fmt.Printf(", %d failed", result.FailedCount)
+	}
+	fmt.Println()
+	if result.JournalID != "" {
+		fmt.Printf("Undo with: qbt-file-matcher moves undo %s\n", result.JournalID)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"qbt-file-matcher/backend"
+)
+
+// loadRulesFlags compiles the rules of --rules and --rules-file; nil when
+// neither is given
+func loadRulesFlags(file string, profile string) (*backend.RuleSet, error) {
+	if file == "" && profile == "" {
+		return nil, nil
+	}
+	rules, err := backend.LoadRules(file, profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rules: %w", err)
+	}
+	return rules, nil
+}
+
+func runRulesCommand() {
+	args := os.Args[2:]
+	subcommand := "list"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		subcommand = args[0]
+		args = args[1:]
+	}
+
+	var err error
+	switch subcommand {
+	case "list":
+		err = listRules(args)
+	case "test":
+		err = testRules(args)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown rules command '%s'\n\n", subcommand)
+		printRulesHelp()
+		os.Exit(1)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func rulesFilePath(file string) (string, error) {
+	if file != "" {
+		return file, nil
+	}
+	return backend.DefaultRulesPath()
+}
+
+func listRules(args []string) error {
+	var file string
+	for i := 0; i < len(args); i++ {
+		if args[i] == "--rules-file" && i+1 < len(args) {
+			file = args[i+1]
+			i++
+		}
+	}
+
+	path, err := rulesFilePath(file)
+	if err != nil {
+		return err
+	}
+	config, err := backend.LoadRulesConfig(path)
+	if err != nil {
+		return err
+	}
+	if len(config.Profiles) == 0 {
+		fmt.Printf("No rules in %s\n", path)
+		return nil
+	}
+
+	fmt.Printf("Rules in %s\n", path)
+	for _, name := range config.ProfileNames() {
+		fmt.Printf("\n%s:\n", name)
+		for i, r := range config.Profiles[name] {
+			label := r.Name
+			if label == "" {
+				label = fmt.Sprintf("rule %d", i+1)
+			}
+			transform := r.Replace
+			if r.Template != "" {
+				transform = r.Template
+			}
+			fmt.Printf("  %s\n    %s -> %s\n", label, r.Pattern, transform)
+		}
+		if _, err := config.Compile(name); err != nil {
+			fmt.Printf("  Error: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// testRules prints where a profile maps torrent paths given as arguments,
+// on stdin, or as the files of a torrent
+func testRules(args []string) error {
+	conn := connectionFromEnv()
+	var file, profile, dir, hash string
+	var paths []string
+	for i := 0; i < len(args); i++ {
+		if next, ok := parseConnectionFlag(args, i, &conn); ok {
+			i = next
+			continue
+		}
+		switch args[i] {
+		case "--rules-file":
+			if i+1 < len(args) {
+				file = args[i+1]
+				i++
+			}
+		case "--profile", "--rules":
+			if i+1 < len(args) {
+				profile = args[i+1]
+				i++
+			}
+		case "--path":
+			if i+1 < len(args) {
+				dir = args[i+1]
+				i++
+			}
+		case "--hash":
+			if i+1 < len(args) {
+				hash = args[i+1]
+				i++
+			}
+		default:
+			paths = append(paths, args[i])
+		}
+	}
+
+	rules, err := backend.LoadRules(file, profile)
+	if err != nil {
+		return err
+	}
+
+	if hash != "" {
+		if conn.URL == "" {
+			return fmt.Errorf("--url is required with --hash")
+		}
+		qbitService, err := connectQBit(conn)
+		if err != nil {
+			return err
+		}
+		files, err := qbitService.GetTorrentFiles(hash)
+		if err != nil {
+			return fmt.Errorf("failed to get torrent files: %w", err)
+		}
+		for _, f := range files {
+			paths = append(paths, f.Name)
+		}
+	} else if len(paths) == 0 {
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if line := strings.TrimSpace(scanner.Text()); line != "" {
+				paths = append(paths, line)
+			}
+		}
+	}
+
+	mappedCount := 0
+	for _, p := range paths {
+		fmt.Println(p)
+		mapped, rule, ok, err := rules.Apply(p)
+		switch {
+		case err != nil:
+			fmt.Printf("  Error: %v\n", err)
+		case !ok:
+			fmt.Println("  no rule matches")
+		default:
+			mappedCount++
+			fmt.Printf("  -> %s (%s)%s\n", mapped, rule, describeMappedPath(dir, mapped))
+		}
+	}
+	fmt.Printf("\nProfile %q mapped %d of %d paths\n", rules.Profile, mappedCount, len(paths))
+	return nil
+}
+
+// describeMappedPath notes whether a mapped path exists under dir
+func describeMappedPath(dir string, mapped string) string {
+	if dir == "" {
+		return ""
+	}
+	target := filepath.FromSlash(mapped)
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(dir, target)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return " [not found]"
+	}
+	return fmt.Sprintf(" [exists, %s]", formatSize(info.Size()))
+}
@@ -1,7 +1,12 @@
 package main
 
 import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
 	"testing"
+
+	"qbt-file-matcher/backend"
 )
 
 func TestIsCLICommand(t *testing.T) {
@@ -10,6 +15,14 @@ func TestIsCLICommand(t *testing.T) {
 		expected bool
 	}{
 		{"match", true},
+		{"add", true},
+		{"watch", true},
+		{"jobs", true},
+		{"moves", true},
+		{"duplicates", true},
+		{"orphans", true},
+		{"missing", true},
+		{"rules", true},
 		{"help", true},
 		{"--help", true},
 		{"-h", true},
@@ -76,6 +89,115 @@ func TestMatchConfig_Defaults(t *testing.T) {
 	if config.recheck {
 		t.Error("recheck should default to false")
 	}
+	if config.waitRecheck {
+		t.Error("waitRecheck should default to false")
+	}
+	if config.revertFailed {
+		t.Error("revertFailed should default to false")
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	result := splitList(" matched, ,needs-review,")
+	if len(result) != 2 || result[0] != "matched" || result[1] != "needs-review" {
+		t.Errorf("splitList returned %q", result)
+	}
+}
+
+func TestApplyOutcome(t *testing.T) {
+	if outcome := applyOutcome(backend.OutcomeMatched, 0); outcome != backend.OutcomeMatched {
+		t.Errorf("Expected matched without failures, got %s", outcome)
+	}
+	if outcome := applyOutcome(backend.OutcomeMatched, 1); outcome != backend.OutcomeNeedsReview {
+		t.Errorf("Expected needs-review when a file failed to apply, got %s", outcome)
+	}
+}
+
+func TestHookHandler(t *testing.T) {
+	hooks := make(chan string, 1)
+	handler := hookHandler("secret", hooks)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/hook?hash=abc", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("Expected 401 without token, got %d", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest("POST", "/hook?hash=abc&token=secret", nil))
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("Expected 202, got %d", rec.Code)
+	}
+	if hash := <-hooks; hash != "abc" {
+		t.Errorf("Expected hash abc, got %q", hash)
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/hook?hash=def", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusAccepted || <-hooks != "def" {
+		t.Errorf("Expected a bearer token to be accepted, got %d", rec.Code)
+	}
+}
+
+func TestIsLoopbackAddr(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:9092": true,
+		"[::1]:9092":     true,
+		"localhost:9092": true,
+		":9092":          false,
+		"0.0.0.0:9092":   false,
+		"10.0.0.5:9092":  false,
+		"9092":           false,
+	}
+	for addr, expected := range tests {
+		if result := isLoopbackAddr(addr); result != expected {
+			t.Errorf("isLoopbackAddr(%q) = %v, want %v", addr, result, expected)
+		}
+	}
+}
+
+func TestExtractMetricsAddr(t *testing.T) {
+	tests := []struct {
+		args         []string
+		expectedAddr string
+		expectedRest []string
+	}{
+		{[]string{"app", "watch", "--once"}, "", []string{"app", "watch", "--once"}},
+		{[]string{"app", "--metrics-addr", ":9100", "watch"}, ":9100", []string{"app", "watch"}},
+		{[]string{"app", "match", "--metrics-addr=127.0.0.1:9100"}, "127.0.0.1:9100", []string{"app", "match"}},
+	}
+
+	for _, tt := range tests {
+		addr, rest := extractMetricsAddr(tt.args)
+		if addr != tt.expectedAddr {
+			t.Errorf("extractMetricsAddr(%v) addr = %q, want %q", tt.args, addr, tt.expectedAddr)
+		}
+		if !slices.Equal(rest, tt.expectedRest) {
+			t.Errorf("extractMetricsAddr(%v) rest = %v, want %v", tt.args, rest, tt.expectedRest)
+		}
+	}
+}
+
+func TestParseSize(t *testing.T) {
+	tests := map[string]int64{
+		"4096": 4096,
+		"64K":  64 << 10,
+		"64kb": 64 << 10,
+		"1M":   1 << 20,
+		"2G":   2 << 30,
+	}
+	for value, expected := range tests {
+		if result, err := parseSize(value); err != nil || result != expected {
+			t.Errorf("parseSize(%q) = %d, %v, want %d", value, result, err, expected)
+		}
+	}
+	for _, value := range []string{"", "abc", "-1", "K"} {
+		if _, err := parseSize(value); err == nil {
+			t.Errorf("Expected parseSize(%q) to fail", value)
+		}
+	}
 }
 
 func TestGetAppVersion(t *testing.T) {
@@ -0,0 +1,303 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"qbt-file-matcher/backend"
+)
+
+// CLI config for watch command
+type watchConfig struct {
+	conn      backend.ConnectionConfig
+	opts      backend.WatchOptions
+	interval  time.Duration
+	once      bool   // single pass, e.g. from cron
+	hash      string // process one torrent and exit
+	listen    string // address for the webhook listener
+	hookToken string
+	notifier  backend.Notifier
+
+	rulesProfile string
+	rulesFile    string
+}
+
+func runWatchCommand() {
+	config := watchConfig{
+		conn:     connectionFromEnv(),
+		interval: time.Minute,
+		opts: backend.WatchOptions{
+			RequireSameExtension: true,
+		},
+		hookToken: os.Getenv("QBT_HOOK_TOKEN"),
+	}
+	var notifySpecs []string
+
+	args := os.Args[2:]
+	for i := 0; i < len(args); i++ {
+		if next, ok := parseConnectionFlag(args, i, &config.conn); ok {
+			i = next
+			continue
+		}
+
+		switch args[i] {
+		case "--root":
+			if i+1 < len(args) {
+				config.opts.Roots = append(config.opts.Roots, args[i+1])
+				i++
+			}
+		case "--interval":
+			if i+1 < len(args) {
+				seconds, err := strconv.Atoi(args[i+1])
+				if err != nil || seconds <= 0 {
+					fmt.Fprintf(os.Stderr, "Error: invalid --interval: %s\n", args[i+1])
+					os.Exit(1)
+				}
+				config.interval = time.Duration(seconds) * time.Second
+				i++
+			}
+		case "--state":
+			if i+1 < len(args) {
+				config.opts.States = splitList(args[i+1])
+				i++
+			}
+		case "--watch-category":
+			if i+1 < len(args) {
+				config.opts.Categories = splitList(args[i+1])
+				i++
+			}
+		case "--review-tag":
+			if i+1 < len(args) {
+				config.opts.ReviewTag = args[i+1]
+				i++
+			}
+		case "--tag":
+			config.opts.PostProcess.TagOutcome = true
+		case "--add-tags":
+			if i+1 < len(args) {
+				config.opts.PostProcess.Tags = splitList(args[i+1])
+				i++
+			}
+		case "--category":
+			if i+1 < len(args) {
+				config.opts.PostProcess.Category = args[i+1]
+				i++
+			}
+		case "--resume":
+			config.opts.PostProcess.Resume = true
+		case "--recheck", "-r":
+			config.opts.Recheck = true
+		case "--same-ext":
+			config.opts.RequireSameExtension = true
+		case "--no-same-ext":
+			config.opts.RequireSameExtension = false
+		case "--rules":
+			if i+1 < len(args) {
+				config.rulesProfile = args[i+1]
+				i++
+			}
+		case "--rules-file":
+			if i+1 < len(args) {
+				config.rulesFile = args[i+1]
+				i++
+			}
+		case "--reuse-duplicates":
+			config.opts.ReuseDuplicates = true
+		case "--path-map":
+			if i+1 < len(args) {
+				mapping, err := backend.ParsePathMapping(args[i+1])
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+					os.Exit(1)
+				}
+				config.opts.PathMap = append(config.opts.PathMap, mapping)
+				i++
+			}
+		case "--dry-run":
+			config.opts.DryRun = true
+		case "--once":
+			config.once = true
+		case "--hash":
+			if i+1 < len(args) {
+				config.hash = args[i+1]
+				i++
+			}
+		case "--listen":
+			if i+1 < len(args) {
+				config.listen = args[i+1]
+				i++
+			}
+		case "--notify":
+			if i+1 < len(args) {
+				notifySpecs = append(notifySpecs, args[i+1])
+				i++
+			}
+		case "--hook-token":
+			if i+1 < len(args) {
+				config.hookToken = args[i+1]
+				i++
+			}
+		}
+	}
+
+	if config.conn.URL == "" {
+		fmt.Fprintln(os.Stderr, "Error: --url is required")
+		os.Exit(1)
+	}
+	if len(config.opts.Roots) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: at least one --root is required")
+		os.Exit(1)
+	}
+	for _, root := range config.opts.Roots {
+		if _, err := os.Stat(root); os.IsNotExist(err) {
+			fmt.Fprintf(os.Stderr, "Error: root does not exist: %s\n", root)
+			os.Exit(1)
+		}
+	}
+	if config.listen != "" && config.hookToken == "" && !isLoopbackAddr(config.listen) {
+		fmt.Fprintf(os.Stderr, "Error: --hook-token (or QBT_HOOK_TOKEN) is required to listen on %s, or listen on 127.0.0.1 only\n", config.listen)
+		os.Exit(1)
+	}
+	config.notifier = parseNotifyFlags(notifySpecs)
+
+	rules, err := loadRulesFlags(config.rulesFile, config.rulesProfile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	config.opts.Rules = rules
+
+	if err := executeWatch(config); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func executeWatch(config watchConfig) error {
+	qbitService, err := connectQBit(config.conn)
+	if err != nil {
+		return err
+	}
+
+	watcher := backend.NewWatcher(qbitService, config.opts)
+	watcher.Logf = log.Printf
+	watcher.Notifier = config.notifier
+
+	if config.hash != "" {
+		result, err := watcher.Process(config.hash)
+		if err != nil {
+			return err
+		}
+		if result.Hash == "" {
+			fmt.Println("Torrent is not in a watched state or category, nothing to do")
+			return nil
+		}
+		printWatchResult(result)
+		return nil
+	}
+
+	if config.once {
+		results, err := watcher.Poll()
+		if err != nil {
+			return err
+		}
+		for _, r := range results {
+			printWatchResult(r)
+		}
+		fmt.Printf("Processed %d torrents\n", len(results))
+		return nil
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	hooks := make(chan string, 16)
+	if config.listen != "" {
+		server := &http.Server{Addr: config.listen, Handler: hookHandler(config.hookToken, hooks)}
+		go func() {
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("Webhook listener failed: %v", err)
+				stop()
+			}
+		}()
+		defer server.Close()
+		log.Printf("Listening for webhooks on %s", config.listen)
+	}
+
+	log.Printf("Watching %d roots, polling every %s", len(config.opts.Roots), config.interval)
+	if err := watcher.Run(ctx, config.interval, hooks); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	log.Println("Stopped")
+	return nil
+}
+
+// isLoopbackAddr reports whether a listen address only accepts connections
+// from this machine
+func isLoopbackAddr(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// hookHandler accepts /hook?hash=<hash>, as sent by qBittorrent's "run
+// external program on torrent added" through curl. The token can be given
+// in the query, the X-API-Token header or as a bearer token.
+func hookHandler(token string, hooks chan<- string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/hook", func(w http.ResponseWriter, r *http.Request) {
+		given := r.URL.Query().Get("token")
+		if header := r.Header.Get("X-API-Token"); header != "" {
+			given = header
+		}
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			given = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if token != "" && subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+			http.Error(w, "invalid token", http.StatusUnauthorized)
+			return
+		}
+
+		hash := r.URL.Query().Get("hash")
+		if hash == "" {
+			http.Error(w, "hash is required", http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case hooks <- hash:
+			w.WriteHeader(http.StatusAccepted)
+		default:
+			http.Error(w, "too many pending torrents", http.StatusServiceUnavailable)
+		}
+	})
+	return mux
+}
+
+func printWatchResult(r backend.WatchResult) {
+	switch {
+	case r.Applied:
+		fmt.Printf("%s: applied, renamed %d files (%s)\n", r.Name, r.RenamedCount, r.Outcome)
+	case r.Reason != "":
+		fmt.Printf("%s: needs review, %s\n", r.Name, r.Reason)
+	default:
+		fmt.Printf("%s: %s\n", r.Name, r.Outcome)
+	}
+}
@@ -6,4 +6,19 @@
 // @ts-ignore: Unused imports
 import { Create as $Create } from "@wailsio/runtime";
 
-Object.freeze($Create.Events);
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as backend$0 from "../../../../../qbt-file-matcher/backend/models.js";
+
+function configure() {
+    Object.freeze(Object.assign($Create.Events, {
+        "files-dropped": $$createType0,
+        "job-updated": $$createType1,
+    }));
+}
+
+// Private type creation functions
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = backend$0.Job.createFrom;
+
+configure();
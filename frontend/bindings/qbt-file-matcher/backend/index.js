@@ -2,23 +2,64 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 
+import * as JobService from "./jobservice.js";
 import * as MatcherService from "./matcherservice.js";
+import * as NotifyService from "./notifyservice.js";
 import * as QBitService from "./qbitservice.js";
 export {
+    JobService,
     MatcherService,
+    NotifyService,
     QBitService
 };
 
 export {
+    AddTorrentRequest,
+    AddTorrentResult,
+    ApplyJobRequest,
+    ApplyJobResult,
+    ApplyResult,
     ConnectionConfig,
     DiskFile,
     DiskFileInfo,
+    DuplicateFile,
+    DuplicateGroup,
+    DuplicateReport,
+    Extraction,
+    FilePieces,
+    FileSegment,
+    Job,
+    LinkOperation,
+    LinkResult,
     MatchInfo,
+    MatchOptions,
     MatchRequest,
     MatchResponse,
+    MissingFile,
+    MissingReport,
+    MissingTorrent,
+    MoveJournal,
+    MoveResult,
+    MoveStep,
+    Notification,
+    OrphanDir,
+    OrphanReport,
+    OrphansRequest,
+    PieceLayout,
+    PieceReport,
+    PostProcessOptions,
+    Rebuild,
+    RecheckFile,
+    RecheckReport,
     RenameOp,
+    RenameOperation,
     RenameRequest,
+    RevertRequest,
+    RuleSet,
     TorrentFile,
     TorrentFileInfo,
-    TorrentInfo
+    TorrentInfo,
+    TorrentMetaInfo,
+    TrashOrphansRequest,
+    VerifyPiecesRequest
 } from "./models.js";
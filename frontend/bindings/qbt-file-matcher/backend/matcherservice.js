@@ -15,6 +15,29 @@ import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Cr
 // @ts-ignore: Unused imports
 import * as $models from "./models.js";
 
+/**
+ * ClassifyOutcome summarises the current selection as a match outcome
+ * (matched, partial, needs-review or unmatched)
+ * @param {$models.MatchInfo[]} matches
+ * @param {$models.TorrentFileInfo[]} unmatched
+ * @returns {$CancellablePromise<string>}
+ */
+export function ClassifyOutcome(matches, unmatched) {
+    return $Call.ByID(3148168238, matches, unmatched);
+}
+
+/**
+ * CountPendingRenames returns how many selected files would be renamed, i.e.
+ * whose path under the search path names another file than the torrent's on
+ * this platform. Folder renames are not collapsed, so each file counts.
+ * @param {$models.MatchInfo[]} matches
+ * @param {string} searchPath
+ * @returns {$CancellablePromise<number>}
+ */
+export function CountPendingRenames(matches, searchPath) {
+    return $Call.ByID(1789980569, matches, searchPath);
+}
+
 /**
  * DirExists checks if a directory exists
  * @param {string} path
@@ -24,6 +47,16 @@ export function DirExists(path) {
     return $Call.ByID(92874191, path);
 }
 
+/**
+ * ExportOrphans saves an orphan report as JSON, or as CSV when path ends in .csv
+ * @param {$models.OrphanReport} report
+ * @param {string} path
+ * @returns {$CancellablePromise<void>}
+ */
+export function ExportOrphans(report, path) {
+    return $Call.ByID(3239092577, report, path);
+}
+
 /**
  * FindMatches finds matches between torrent files and disk files
  * @param {$models.MatchRequest} req
@@ -46,6 +79,40 @@ export function GenRenames(req) {
     }));
 }
 
+/**
+ * GenReverts generates the rename operations that undo renames of files
+ * which did not verify after a recheck
+ * @param {$models.RevertRequest} req
+ * @returns {$CancellablePromise<$models.RenameOp[]>}
+ */
+export function GenReverts(req) {
+    return $Call.ByID(3063704405, req).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType2($result);
+    }));
+}
+
+/**
+ * ListRuleProfiles returns the names of the rename rule profiles
+ * @returns {$CancellablePromise<string[]>}
+ */
+export function ListRuleProfiles() {
+    return $Call.ByID(3674717392).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType3($result);
+    }));
+}
+
+/**
+ * ReadTorrent parses a .torrent file and returns its files as qBittorrent
+ * will name them
+ * @param {string} path
+ * @returns {$CancellablePromise<$models.TorrentMetaInfo>}
+ */
+export function ReadTorrent(path) {
+    return $Call.ByID(1892720442, path).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType4($result);
+    }));
+}
+
 /**
  * ScanDir scans a directory and returns all files
  * @param {string} path
@@ -53,7 +120,7 @@ export function GenRenames(req) {
  */
 export function ScanDir(path) {
     return $Call.ByID(3083563120, path).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType4($result);
+        return $$createType6($result);
     }));
 }
 
@@ -61,5 +128,7 @@ export function ScanDir(path) {
 const $$createType0 = $models.MatchResponse.createFrom;
 const $$createType1 = $models.RenameOp.createFrom;
 const $$createType2 = $Create.Array($$createType1);
-const $$createType3 = $models.DiskFileInfo.createFrom;
-const $$createType4 = $Create.Array($$createType3);
+const $$createType3 = $Create.Array($Create.Any);
+const $$createType4 = $models.TorrentMetaInfo.createFrom;
+const $$createType5 = $models.DiskFileInfo.createFrom;
+const $$createType6 = $Create.Array($$createType5);
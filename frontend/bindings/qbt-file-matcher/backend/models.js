@@ -6,118 +6,2472 @@
 // @ts-ignore: Unused imports
 import { Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as json$0 from "../../encoding/json/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as jsontext$0 from "../../encoding/json/jsontext/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as time$0 from "../../time/models.js";
+
+/**
+ * AddTorrentRequest describes a .torrent to add to qBittorrent pre-matched to
+ * files on disk. Renames and Unwanted use the torrent's original file names.
+ */
+export class AddTorrentRequest {
+    /**
+     * Creates a new AddTorrentRequest instance.
+     * @param {Partial<AddTorrentRequest>} [$$source = {}] - The source object to create the AddTorrentRequest.
+     */
+    constructor($$source = {}) {
+        if (!("torrentPath" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["torrentPath"] = "";
+        }
+        if (!("savePath" in $$source)) {
+            /**
+             * the scanned directory renames are relative to
+             * @member
+             * @type {string}
+             */
+            this["savePath"] = "";
+        }
+        if (!("renames" in $$source)) {
+            /**
+             * @member
+             * @type {RenameOperation[]}
+             */
+            this["renames"] = [];
+        }
+        if (!("unwanted" in $$source)) {
+            /**
+             * files to set to "do not download"
+             * @member
+             * @type {string[]}
+             */
+            this["unwanted"] = [];
+        }
+        if (!("category" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["category"] = "";
+        }
+        if (!("tags" in $$source)) {
+            /**
+             * @member
+             * @type {string[]}
+             */
+            this["tags"] = [];
+        }
+        if (!("recheck" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["recheck"] = false;
+        }
+        if (!("resume" in $$source)) {
+            /**
+             * with Recheck, only resumed once every wanted file verified
+             * @member
+             * @type {boolean}
+             */
+            this["resume"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AddTorrentRequest instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {AddTorrentRequest}
+     */
+    static createFrom($$source = {}) {
+        const $$createField2_0 = $$createType1;
+        const $$createField3_0 = $$createType2;
+        const $$createField5_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("renames" in $$parsedSource) {
+            $$parsedSource["renames"] = $$createField2_0($$parsedSource["renames"]);
+        }
+        if ("unwanted" in $$parsedSource) {
+            $$parsedSource["unwanted"] = $$createField3_0($$parsedSource["unwanted"]);
+        }
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField5_0($$parsedSource["tags"]);
+        }
+        return new AddTorrentRequest(/** @type {Partial<AddTorrentRequest>} */($$parsedSource));
+    }
+}
+
+/**
+ * AddTorrentResult represents the outcome of adding a pre-matched torrent
+ */
+export class AddTorrentResult {
+    /**
+     * Creates a new AddTorrentResult instance.
+     * @param {Partial<AddTorrentResult>} [$$source = {}] - The source object to create the AddTorrentResult.
+     */
+    constructor($$source = {}) {
+        if (!("hash" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["hash"] = "";
+        }
+        if (!("renamedCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["renamedCount"] = 0;
+        }
+        if (!("failedCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["failedCount"] = 0;
+        }
+        if (!("skippedCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["skippedCount"] = 0;
+        }
+        if (!("report" in $$source)) {
+            /**
+             * @member
+             * @type {RecheckReport | null}
+             */
+            this["report"] = null;
+        }
+        if (!("resumed" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["resumed"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new AddTorrentResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {AddTorrentResult}
+     */
+    static createFrom($$source = {}) {
+        const $$createField4_0 = $$createType4;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("report" in $$parsedSource) {
+            $$parsedSource["report"] = $$createField4_0($$parsedSource["report"]);
+        }
+        return new AddTorrentResult(/** @type {Partial<AddTorrentResult>} */($$parsedSource));
+    }
+}
+
+/**
+ * ApplyJobRequest describes renames to apply to a torrent as a job.
+ * Extractions and rebuilds are done before any rename; if one fails nothing
+ * is renamed. Links put disk files into the torrent's layout instead of renaming.
+ */
+export class ApplyJobRequest {
+    /**
+     * Creates a new ApplyJobRequest instance.
+     * @param {Partial<ApplyJobRequest>} [$$source = {}] - The source object to create the ApplyJobRequest.
+     */
+    constructor($$source = {}) {
+        if (!("hash" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["hash"] = "";
+        }
+        if (!("extractions" in $$source)) {
+            /**
+             * @member
+             * @type {Extraction[]}
+             */
+            this["extractions"] = [];
+        }
+        if (!("rebuilds" in $$source)) {
+            /**
+             * @member
+             * @type {Rebuild[]}
+             */
+            this["rebuilds"] = [];
+        }
+        if (!("renames" in $$source)) {
+            /**
+             * @member
+             * @type {RenameOp[]}
+             */
+            this["renames"] = [];
+        }
+        if (!("links" in $$source)) {
+            /**
+             * @member
+             * @type {LinkOperation[]}
+             */
+            this["links"] = [];
+        }
+        if (!("linkMethods" in $$source)) {
+            /**
+             * DefaultLinkMethods when empty
+             * @member
+             * @type {string[]}
+             */
+            this["linkMethods"] = [];
+        }
+        if (!("recheck" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["recheck"] = false;
+        }
+        if (!("wait" in $$source)) {
+            /**
+             * wait for the recheck and include its report
+             * @member
+             * @type {boolean}
+             */
+            this["wait"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ApplyJobRequest instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ApplyJobRequest}
+     */
+    static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType6;
+        const $$createField2_0 = $$createType8;
+        const $$createField3_0 = $$createType10;
+        const $$createField4_0 = $$createType12;
+        const $$createField5_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("extractions" in $$parsedSource) {
+            $$parsedSource["extractions"] = $$createField1_0($$parsedSource["extractions"]);
+        }
+        if ("rebuilds" in $$parsedSource) {
+            $$parsedSource["rebuilds"] = $$createField2_0($$parsedSource["rebuilds"]);
+        }
+        if ("renames" in $$parsedSource) {
+            $$parsedSource["renames"] = $$createField3_0($$parsedSource["renames"]);
+        }
+        if ("links" in $$parsedSource) {
+            $$parsedSource["links"] = $$createField4_0($$parsedSource["links"]);
+        }
+        if ("linkMethods" in $$parsedSource) {
+            $$parsedSource["linkMethods"] = $$createField5_0($$parsedSource["linkMethods"]);
+        }
+        return new ApplyJobRequest(/** @type {Partial<ApplyJobRequest>} */($$parsedSource));
+    }
+}
+
+/**
+ * ApplyJobResult is the result of an apply or undo job
+ */
+export class ApplyJobResult {
+    /**
+     * Creates a new ApplyJobResult instance.
+     * @param {Partial<ApplyJobResult>} [$$source = {}] - The source object to create the ApplyJobResult.
+     */
+    constructor($$source = {}) {
+        if (!("renamedCount" in $$source)) {
+            /**
+             * files moved, counting every file of a folder rename
+             * @member
+             * @type {number}
+             */
+            this["renamedCount"] = 0;
+        }
+        if (!("failedCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["failedCount"] = 0;
+        }
+        if (!("applied" in $$source)) {
+            /**
+             * operations that succeeded, in order
+             * @member
+             * @type {RenameOperation[]}
+             */
+            this["applied"] = [];
+        }
+        if (!("errors" in $$source)) {
+            /**
+             * @member
+             * @type {string[]}
+             */
+            this["errors"] = [];
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * journal of the applied renames, when kept
+             * @member
+             * @type {string | undefined}
+             */
+            this["journalId"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {LinkResult | null | undefined}
+             */
+            this["links"] = undefined;
+        }
+        if (!("report" in $$source)) {
+            /**
+             * @member
+             * @type {RecheckReport | null}
+             */
+            this["report"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ApplyJobResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ApplyJobResult}
+     */
+    static createFrom($$source = {}) {
+        const $$createField2_0 = $$createType1;
+        const $$createField3_0 = $$createType2;
+        const $$createField5_0 = $$createType14;
+        const $$createField6_0 = $$createType4;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("applied" in $$parsedSource) {
+            $$parsedSource["applied"] = $$createField2_0($$parsedSource["applied"]);
+        }
+        if ("errors" in $$parsedSource) {
+            $$parsedSource["errors"] = $$createField3_0($$parsedSource["errors"]);
+        }
+        if ("links" in $$parsedSource) {
+            $$parsedSource["links"] = $$createField5_0($$parsedSource["links"]);
+        }
+        if ("report" in $$parsedSource) {
+            $$parsedSource["report"] = $$createField6_0($$parsedSource["report"]);
+        }
+        return new ApplyJobResult(/** @type {Partial<ApplyJobResult>} */($$parsedSource));
+    }
+}
+
+/**
+ * ApplyResult represents the outcome of applying rename operations
+ */
+export class ApplyResult {
+    /**
+     * Creates a new ApplyResult instance.
+     * @param {Partial<ApplyResult>} [$$source = {}] - The source object to create the ApplyResult.
+     */
+    constructor($$source = {}) {
+        if (!("renamedCount" in $$source)) {
+            /**
+             * files moved, counting every file of a folder rename
+             * @member
+             * @type {number}
+             */
+            this["renamedCount"] = 0;
+        }
+        if (!("failedCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["failedCount"] = 0;
+        }
+        if (!("applied" in $$source)) {
+            /**
+             * operations that succeeded, in order
+             * @member
+             * @type {RenameOperation[]}
+             */
+            this["applied"] = [];
+        }
+        if (!("errors" in $$source)) {
+            /**
+             * @member
+             * @type {string[]}
+             */
+            this["errors"] = [];
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * journal of the applied renames, when kept
+             * @member
+             * @type {string | undefined}
+             */
+            this["journalId"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ApplyResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ApplyResult}
+     */
+    static createFrom($$source = {}) {
+        const $$createField2_0 = $$createType1;
+        const $$createField3_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("applied" in $$parsedSource) {
+            $$parsedSource["applied"] = $$createField2_0($$parsedSource["applied"]);
+        }
+        if ("errors" in $$parsedSource) {
+            $$parsedSource["errors"] = $$createField3_0($$parsedSource["errors"]);
+        }
+        return new ApplyResult(/** @type {Partial<ApplyResult>} */($$parsedSource));
+    }
+}
+
+/**
+ * ConnectionConfig represents connection settings
+ */
+export class ConnectionConfig {
+    /**
+     * Creates a new ConnectionConfig instance.
+     * @param {Partial<ConnectionConfig>} [$$source = {}] - The source object to create the ConnectionConfig.
+     */
+    constructor($$source = {}) {
+        if (!("url" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["url"] = "";
+        }
+        if (!("username" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["username"] = "";
+        }
+        if (!("password" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["password"] = "";
+        }
+        if (!("caCertFile" in $$source)) {
+            /**
+             * Reverse proxy settings
+             * PEM bundle trusted in addition to system roots
+             * @member
+             * @type {string}
+             */
+            this["caCertFile"] = "";
+        }
+        if (!("insecureSkipVerify" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["insecureSkipVerify"] = false;
+        }
+        if (!("clientCertFile" in $$source)) {
+            /**
+             * PEM client certificate
+             * @member
+             * @type {string}
+             */
+            this["clientCertFile"] = "";
+        }
+        if (!("clientKeyFile" in $$source)) {
+            /**
+             * PEM key, defaults to ClientCertFile
+             * @member
+             * @type {string}
+             */
+            this["clientKeyFile"] = "";
+        }
+        if (!("basicUsername" in $$source)) {
+            /**
+             * HTTP basic auth
+             * @member
+             * @type {string}
+             */
+            this["basicUsername"] = "";
+        }
+        if (!("basicPassword" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["basicPassword"] = "";
+        }
+        if (!("headers" in $$source)) {
+            /**
+             * extra headers sent with every request
+             * @member
+             * @type {{ [_ in string]?: string }}
+             */
+            this["headers"] = {};
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ConnectionConfig instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {ConnectionConfig}
+     */
+    static createFrom($$source = {}) {
+        const $$createField9_0 = $$createType15;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("headers" in $$parsedSource) {
+            $$parsedSource["headers"] = $$createField9_0($$parsedSource["headers"]);
+        }
+        return new ConnectionConfig(/** @type {Partial<ConnectionConfig>} */($$parsedSource));
+    }
+}
+
+/**
+ * DiskFile represents a file on the disk
+ */
+export class DiskFile {
+    /**
+     * Creates a new DiskFile instance.
+     * @param {Partial<DiskFile>} [$$source = {}] - The source object to create the DiskFile.
+     */
+    constructor($$source = {}) {
+        if (!("path" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["path"] = "";
+        }
+        if (!("name" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("size" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["size"] = 0;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * Archive and Entry locate a file that only exists inside a zip or tar
+             * archive; Path is then where it would be extracted. See ScanArchives.
+             * @member
+             * @type {string | undefined}
+             */
+            this["archive"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["entry"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * Parts are the disk file ranges a file that has to be joined or cut
+             * out of other files is rebuilt from; Path is then where it would be
+             * written. See IsReassembled.
+             * @member
+             * @type {FileSegment[] | undefined}
+             */
+            this["parts"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * Fingerprint samples the file's content, see FingerprintFiles. Copies
+             * lists identical files the matcher collapsed into this one.
+             * @member
+             * @type {string | undefined}
+             */
+            this["fingerprint"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["copies"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DiskFile instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {DiskFile}
+     */
+    static createFrom($$source = {}) {
+        const $$createField5_0 = $$createType17;
+        const $$createField7_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("parts" in $$parsedSource) {
+            $$parsedSource["parts"] = $$createField5_0($$parsedSource["parts"]);
+        }
+        if ("copies" in $$parsedSource) {
+            $$parsedSource["copies"] = $$createField7_0($$parsedSource["copies"]);
+        }
+        return new DiskFile(/** @type {Partial<DiskFile>} */($$parsedSource));
+    }
+}
+
+/**
+ * DiskFileInfo represents a file on disk for the frontend
+ */
+export class DiskFileInfo {
+    /**
+     * Creates a new DiskFileInfo instance.
+     * @param {Partial<DiskFileInfo>} [$$source = {}] - The source object to create the DiskFileInfo.
+     */
+    constructor($$source = {}) {
+        if (!("path" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["path"] = "";
+        }
+        if (!("name" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("size" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["size"] = 0;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["archive"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["entry"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {FileSegment[] | undefined}
+             */
+            this["parts"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["fingerprint"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["copies"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DiskFileInfo instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {DiskFileInfo}
+     */
+    static createFrom($$source = {}) {
+        const $$createField5_0 = $$createType17;
+        const $$createField7_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("parts" in $$parsedSource) {
+            $$parsedSource["parts"] = $$createField5_0($$parsedSource["parts"]);
+        }
+        if ("copies" in $$parsedSource) {
+            $$parsedSource["copies"] = $$createField7_0($$parsedSource["copies"]);
+        }
+        return new DiskFileInfo(/** @type {Partial<DiskFileInfo>} */($$parsedSource));
+    }
+}
+
+/**
+ * DuplicateFile is a torrent file that other torrents share, e.g. when the
+ * same release is cross-seeded from several trackers
+ */
+export class DuplicateFile {
+    /**
+     * Creates a new DuplicateFile instance.
+     * @param {Partial<DuplicateFile>} [$$source = {}] - The source object to create the DuplicateFile.
+     */
+    constructor($$source = {}) {
+        if (!("hash" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["hash"] = "";
+        }
+        if (!("torrentName" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["torrentName"] = "";
+        }
+        if (!("savePath" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["savePath"] = "";
+        }
+        if (!("index" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["index"] = 0;
+        }
+        if (!("name" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("size" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["size"] = 0;
+        }
+        if (!("progress" in $$source)) {
+            /**
+             * 1 once qBittorrent verified the file on disk
+             * @member
+             * @type {number}
+             */
+            this["progress"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DuplicateFile instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {DuplicateFile}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new DuplicateFile(/** @type {Partial<DuplicateFile>} */($$parsedSource));
+    }
+}
+
+/**
+ * DuplicateGroup is a set of files of at least two torrents with the same
+ * content
+ */
+export class DuplicateGroup {
+    /**
+     * Creates a new DuplicateGroup instance.
+     * @param {Partial<DuplicateGroup>} [$$source = {}] - The source object to create the DuplicateGroup.
+     */
+    constructor($$source = {}) {
+        if (!("size" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["size"] = 0;
+        }
+        if (!("files" in $$source)) {
+            /**
+             * @member
+             * @type {DuplicateFile[]}
+             */
+            this["files"] = [];
+        }
+        if (!("verified" in $$source)) {
+            /**
+             * Verified is set when every file is linked to another by identical
+             * piece hashes, not only by name and size
+             * @member
+             * @type {boolean}
+             */
+            this["verified"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DuplicateGroup instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {DuplicateGroup}
+     */
+    static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType19;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("files" in $$parsedSource) {
+            $$parsedSource["files"] = $$createField1_0($$parsedSource["files"]);
+        }
+        return new DuplicateGroup(/** @type {Partial<DuplicateGroup>} */($$parsedSource));
+    }
+}
+
+/**
+ * DuplicateReport lists the files torrents share
+ */
+export class DuplicateReport {
+    /**
+     * Creates a new DuplicateReport instance.
+     * @param {Partial<DuplicateReport>} [$$source = {}] - The source object to create the DuplicateReport.
+     */
+    constructor($$source = {}) {
+        if (!("torrents" in $$source)) {
+            /**
+             * torrents scanned
+             * @member
+             * @type {number}
+             */
+            this["torrents"] = 0;
+        }
+        if (!("groups" in $$source)) {
+            /**
+             * @member
+             * @type {DuplicateGroup[]}
+             */
+            this["groups"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DuplicateReport instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {DuplicateReport}
+     */
+    static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType21;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("groups" in $$parsedSource) {
+            $$parsedSource["groups"] = $$createField1_0($$parsedSource["groups"]);
+        }
+        return new DuplicateReport(/** @type {Partial<DuplicateReport>} */($$parsedSource));
+    }
+}
+
+/**
+ * Extraction extracts a file from an archive to where the renames expect it
+ */
+export class Extraction {
+    /**
+     * Creates a new Extraction instance.
+     * @param {Partial<Extraction>} [$$source = {}] - The source object to create the Extraction.
+     */
+    constructor($$source = {}) {
+        if (!("archive" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["archive"] = "";
+        }
+        if (!("entry" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["entry"] = "";
+        }
+        if (!("target" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["target"] = "";
+        }
+        if (!("size" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["size"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Extraction instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Extraction}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Extraction(/** @type {Partial<Extraction>} */($$parsedSource));
+    }
+}
+
+/**
+ * FilePieces counts the pieces overlapping a single torrent file
+ */
+export class FilePieces {
+    /**
+     * Creates a new FilePieces instance.
+     * @param {Partial<FilePieces>} [$$source = {}] - The source object to create the FilePieces.
+     */
+    constructor($$source = {}) {
+        if (!("index" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["index"] = 0;
+        }
+        if (!("name" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("pieces" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["pieces"] = 0;
+        }
+        if (!("passed" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["passed"] = 0;
+        }
+        if (!("failed" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["failed"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new FilePieces instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {FilePieces}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new FilePieces(/** @type {Partial<FilePieces>} */($$parsedSource));
+    }
+}
+
+/**
+ * FileSegment is a byte range of a disk file
+ */
+export class FileSegment {
+    /**
+     * Creates a new FileSegment instance.
+     * @param {Partial<FileSegment>} [$$source = {}] - The source object to create the FileSegment.
+     */
+    constructor($$source = {}) {
+        if (!("path" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["path"] = "";
+        }
+        if (!("offset" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["offset"] = 0;
+        }
+        if (!("size" in $$source)) {
+            /**
+             * -1 for the rest of the file
+             * @member
+             * @type {number}
+             */
+            this["size"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new FileSegment instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {FileSegment}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new FileSegment(/** @type {Partial<FileSegment>} */($$parsedSource));
+    }
+}
+
+/**
+ * Job represents a long-running scan, match or apply and its persisted result
+ */
+export class Job {
+    /**
+     * Creates a new Job instance.
+     * @param {Partial<Job>} [$$source = {}] - The source object to create the Job.
+     */
+    constructor($$source = {}) {
+        if (!("id" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["id"] = "";
+        }
+        if (!("kind" in $$source)) {
+            /**
+             * scan, match, apply or undo
+             * @member
+             * @type {string}
+             */
+            this["kind"] = "";
+        }
+        if (!("target" in $$source)) {
+            /**
+             * torrent hash or scanned path
+             * @member
+             * @type {string}
+             */
+            this["target"] = "";
+        }
+        if (!("description" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["description"] = "";
+        }
+        if (!("status" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["status"] = "";
+        }
+        if (!("progress" in $$source)) {
+            /**
+             * 0 to 1, or -1 when the total is unknown
+             * @member
+             * @type {number}
+             */
+            this["progress"] = 0;
+        }
+        if (!("message" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["message"] = "";
+        }
+        if (!("logs" in $$source)) {
+            /**
+             * @member
+             * @type {string[]}
+             */
+            this["logs"] = [];
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["error"] = undefined;
+        }
+        if (!("created" in $$source)) {
+            /**
+             * @member
+             * @type {time$0.Time}
+             */
+            this["created"] = null;
+        }
+        if (!("updated" in $$source)) {
+            /**
+             * @member
+             * @type {time$0.Time}
+             */
+            this["updated"] = null;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {time$0.Time | null | undefined}
+             */
+            this["finished"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {json$0.RawMessage | undefined}
+             */
+            this["result"] = undefined;
+        }
+        if (!("pid" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["pid"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Job instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Job}
+     */
+    static createFrom($$source = {}) {
+        const $$createField7_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("logs" in $$parsedSource) {
+            $$parsedSource["logs"] = $$createField7_0($$parsedSource["logs"]);
+        }
+        return new Job(/** @type {Partial<Job>} */($$parsedSource));
+    }
+}
+
+/**
+ * LinkOperation places a matched disk file where the torrent expects it,
+ * leaving both the disk file and the torrent's file names untouched
+ */
+export class LinkOperation {
+    /**
+     * Creates a new LinkOperation instance.
+     * @param {Partial<LinkOperation>} [$$source = {}] - The source object to create the LinkOperation.
+     */
+    constructor($$source = {}) {
+        if (!("source" in $$source)) {
+            /**
+             * the matched disk file
+             * @member
+             * @type {string}
+             */
+            this["source"] = "";
+        }
+        if (!("target" in $$source)) {
+            /**
+             * save path joined with the torrent file name
+             * @member
+             * @type {string}
+             */
+            this["target"] = "";
+        }
+        if (!("torrentFile" in $$source)) {
+            /**
+             * @member
+             * @type {TorrentFileInfo}
+             */
+            this["torrentFile"] = (new TorrentFileInfo());
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * how it was linked, once done
+             * @member
+             * @type {string | undefined}
+             */
+            this["method"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new LinkOperation instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {LinkOperation}
+     */
+    static createFrom($$source = {}) {
+        const $$createField2_0 = $$createType22;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("torrentFile" in $$parsedSource) {
+            $$parsedSource["torrentFile"] = $$createField2_0($$parsedSource["torrentFile"]);
+        }
+        return new LinkOperation(/** @type {Partial<LinkOperation>} */($$parsedSource));
+    }
+}
+
+/**
+ * LinkResult summarises a batch of links
+ */
+export class LinkResult {
+    /**
+     * Creates a new LinkResult instance.
+     * @param {Partial<LinkResult>} [$$source = {}] - The source object to create the LinkResult.
+     */
+    constructor($$source = {}) {
+        if (!("linkedCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["linkedCount"] = 0;
+        }
+        if (!("failedCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["failedCount"] = 0;
+        }
+        if (!("linked" in $$source)) {
+            /**
+             * with the method used
+             * @member
+             * @type {LinkOperation[]}
+             */
+            this["linked"] = [];
+        }
+        if (!("errors" in $$source)) {
+            /**
+             * @member
+             * @type {string[]}
+             */
+            this["errors"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new LinkResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {LinkResult}
+     */
+    static createFrom($$source = {}) {
+        const $$createField2_0 = $$createType12;
+        const $$createField3_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("linked" in $$parsedSource) {
+            $$parsedSource["linked"] = $$createField2_0($$parsedSource["linked"]);
+        }
+        if ("errors" in $$parsedSource) {
+            $$parsedSource["errors"] = $$createField3_0($$parsedSource["errors"]);
+        }
+        return new LinkResult(/** @type {Partial<LinkResult>} */($$parsedSource));
+    }
+}
+
+/**
+ * MatchInfo represents a single match for the frontend
+ */
+export class MatchInfo {
+    /**
+     * Creates a new MatchInfo instance.
+     * @param {Partial<MatchInfo>} [$$source = {}] - The source object to create the MatchInfo.
+     */
+    constructor($$source = {}) {
+        if (!("torrentFile" in $$source)) {
+            /**
+             * @member
+             * @type {TorrentFileInfo}
+             */
+            this["torrentFile"] = (new TorrentFileInfo());
+        }
+        if (!("diskFiles" in $$source)) {
+            /**
+             * @member
+             * @type {DiskFile[]}
+             */
+            this["diskFiles"] = [];
+        }
+        if (!("selected" in $$source)) {
+            /**
+             * @member
+             * @type {DiskFile | null}
+             */
+            this["selected"] = null;
+        }
+        if (!("autoMatched" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["autoMatched"] = false;
+        }
+        if (!("score" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["score"] = 0;
+        }
+        if (!("sizeMismatch" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["sizeMismatch"] = false;
+        }
+        if (!("partial" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["partial"] = false;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * @member
+             * @type {string | undefined}
+             */
+            this["rule"] = undefined;
+        }
+        if (!("exact" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["exact"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MatchInfo instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {MatchInfo}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType22;
+        const $$createField1_0 = $$createType24;
+        const $$createField2_0 = $$createType25;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("torrentFile" in $$parsedSource) {
+            $$parsedSource["torrentFile"] = $$createField0_0($$parsedSource["torrentFile"]);
+        }
+        if ("diskFiles" in $$parsedSource) {
+            $$parsedSource["diskFiles"] = $$createField1_0($$parsedSource["diskFiles"]);
+        }
+        if ("selected" in $$parsedSource) {
+            $$parsedSource["selected"] = $$createField2_0($$parsedSource["selected"]);
+        }
+        return new MatchInfo(/** @type {Partial<MatchInfo>} */($$parsedSource));
+    }
+}
+
+/**
+ * MatchOptions controls how candidates are found
+ */
+export class MatchOptions {
+    /**
+     * Creates a new MatchOptions instance.
+     * @param {Partial<MatchOptions>} [$$source = {}] - The source object to create the MatchOptions.
+     */
+    constructor($$source = {}) {
+        if (!("RequireSameExtension" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["RequireSameExtension"] = false;
+        }
+        if (!("SizeTolerance" in $$source)) {
+            /**
+             * SizeTolerance, when above 0, proposes files whose size differs by at
+             * most this many bytes when no file has the exact size, e.g. files whose
+             * tags were rewritten in place
+             * @member
+             * @type {number}
+             */
+            this["SizeTolerance"] = 0;
+        }
+        if (!("PartialFiles" in $$source)) {
+            /**
+             * Partial proposes files smaller than the torrent file, with the same
+             * extension and a similar name, when nothing else matched
+             * @member
+             * @type {boolean}
+             */
+            this["PartialFiles"] = false;
+        }
+        if (!("SplitJoin" in $$source)) {
+            /**
+             * SplitJoin rebuilds unmatched torrent files from numbered parts on
+             * disk, or cuts them out of a disk file that holds several of them
+             * back to back, see matchReassemblies
+             * @member
+             * @type {boolean}
+             */
+            this["SplitJoin"] = false;
+        }
+        if (!("Rules" in $$source)) {
+            /**
+             * Rules, when set, map torrent paths to expected disk paths before
+             * size matching. A file of the right size at the mapped path is
+             * selected without ranking.
+             * @member
+             * @type {RuleSet | null}
+             */
+            this["Rules"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MatchOptions instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {MatchOptions}
+     */
+    static createFrom($$source = {}) {
+        const $$createField4_0 = $$createType27;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("Rules" in $$parsedSource) {
+            $$parsedSource["Rules"] = $$createField4_0($$parsedSource["Rules"]);
+        }
+        return new MatchOptions(/** @type {Partial<MatchOptions>} */($$parsedSource));
+    }
+}
+
+/**
+ * MatchRequest represents a request to find matches
+ */
+export class MatchRequest {
+    /**
+     * Creates a new MatchRequest instance.
+     * @param {Partial<MatchRequest>} [$$source = {}] - The source object to create the MatchRequest.
+     */
+    constructor($$source = {}) {
+        if (!("torrentFiles" in $$source)) {
+            /**
+             * @member
+             * @type {TorrentFileInfo[]}
+             */
+            this["torrentFiles"] = [];
+        }
+        if (!("diskFiles" in $$source)) {
+            /**
+             * @member
+             * @type {DiskFile[]}
+             */
+            this["diskFiles"] = [];
+        }
+        if (!("requireSameExtension" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["requireSameExtension"] = false;
+        }
+        if (!("sizeTolerance" in $$source)) {
+            /**
+             * bytes, 0 for exact sizes only
+             * @member
+             * @type {number}
+             */
+            this["sizeTolerance"] = 0;
+        }
+        if (!("partialFiles" in $$source)) {
+            /**
+             * also offer smaller, partially downloaded files
+             * @member
+             * @type {boolean}
+             */
+            this["partialFiles"] = false;
+        }
+        if (!("fingerprints" in $$source)) {
+            /**
+             * sample same-size files to collapse identical copies
+             * @member
+             * @type {boolean}
+             */
+            this["fingerprints"] = false;
+        }
+        if (!("rulesProfile" in $$source)) {
+            /**
+             * rename rules to apply first, none when empty
+             * @member
+             * @type {string}
+             */
+            this["rulesProfile"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MatchRequest instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {MatchRequest}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType28;
+        const $$createField1_0 = $$createType24;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("torrentFiles" in $$parsedSource) {
+            $$parsedSource["torrentFiles"] = $$createField0_0($$parsedSource["torrentFiles"]);
+        }
+        if ("diskFiles" in $$parsedSource) {
+            $$parsedSource["diskFiles"] = $$createField1_0($$parsedSource["diskFiles"]);
+        }
+        return new MatchRequest(/** @type {Partial<MatchRequest>} */($$parsedSource));
+    }
+}
+
+/**
+ * MatchResponse represents the match results
+ */
+export class MatchResponse {
+    /**
+     * Creates a new MatchResponse instance.
+     * @param {Partial<MatchResponse>} [$$source = {}] - The source object to create the MatchResponse.
+     */
+    constructor($$source = {}) {
+        if (!("matches" in $$source)) {
+            /**
+             * @member
+             * @type {MatchInfo[]}
+             */
+            this["matches"] = [];
+        }
+        if (!("unmatched" in $$source)) {
+            /**
+             * @member
+             * @type {TorrentFileInfo[]}
+             */
+            this["unmatched"] = [];
+        }
+        if (!("skipped" in $$source)) {
+            /**
+             * @member
+             * @type {TorrentFileInfo[]}
+             */
+            this["skipped"] = [];
+        }
+        if (!("totalFiles" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["totalFiles"] = 0;
+        }
+        if (!("matchedCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["matchedCount"] = 0;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * torrent files a rule failed to map
+             * @member
+             * @type {string[] | undefined}
+             */
+            this["ruleErrors"] = undefined;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MatchResponse instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {MatchResponse}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType30;
+        const $$createField1_0 = $$createType28;
+        const $$createField2_0 = $$createType28;
+        const $$createField5_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("matches" in $$parsedSource) {
+            $$parsedSource["matches"] = $$createField0_0($$parsedSource["matches"]);
+        }
+        if ("unmatched" in $$parsedSource) {
+            $$parsedSource["unmatched"] = $$createField1_0($$parsedSource["unmatched"]);
+        }
+        if ("skipped" in $$parsedSource) {
+            $$parsedSource["skipped"] = $$createField2_0($$parsedSource["skipped"]);
+        }
+        if ("ruleErrors" in $$parsedSource) {
+            $$parsedSource["ruleErrors"] = $$createField5_0($$parsedSource["ruleErrors"]);
+        }
+        return new MatchResponse(/** @type {Partial<MatchResponse>} */($$parsedSource));
+    }
+}
+
+/**
+ * MissingFile is a torrent file that is not where qBittorrent expects it, or
+ * has the wrong size there
+ */
+export class MissingFile {
+    /**
+     * Creates a new MissingFile instance.
+     * @param {Partial<MissingFile>} [$$source = {}] - The source object to create the MissingFile.
+     */
+    constructor($$source = {}) {
+        if (!("index" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["index"] = 0;
+        }
+        if (!("name" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("size" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["size"] = 0;
+        }
+        if (!("path" in $$source)) {
+            /**
+             * where qBittorrent expects it
+             * @member
+             * @type {string}
+             */
+            this["path"] = "";
+        }
+        if (!("diskSize" in $$source)) {
+            /**
+             * size found at Path, -1 when missing
+             * @member
+             * @type {number}
+             */
+            this["diskSize"] = 0;
+        }
+        if (!("candidates" in $$source)) {
+            /**
+             * @member
+             * @type {DiskFile[]}
+             */
+            this["candidates"] = [];
+        }
+        if (!("autoMatch" in $$source)) {
+            /**
+             * the first candidate would be selected by match
+             * @member
+             * @type {boolean}
+             */
+            this["autoMatch"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MissingFile instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {MissingFile}
+     */
+    static createFrom($$source = {}) {
+        const $$createField5_0 = $$createType24;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("candidates" in $$parsedSource) {
+            $$parsedSource["candidates"] = $$createField5_0($$parsedSource["candidates"]);
+        }
+        return new MissingFile(/** @type {Partial<MissingFile>} */($$parsedSource));
+    }
+}
+
+/**
+ * MissingReport lists every torrent with missing or wrong-sized files
+ */
+export class MissingReport {
+    /**
+     * Creates a new MissingReport instance.
+     * @param {Partial<MissingReport>} [$$source = {}] - The source object to create the MissingReport.
+     */
+    constructor($$source = {}) {
+        if (!("checked" in $$source)) {
+            /**
+             * torrents checked
+             * @member
+             * @type {number}
+             */
+            this["checked"] = 0;
+        }
+        if (!("torrents" in $$source)) {
+            /**
+             * @member
+             * @type {MissingTorrent[]}
+             */
+            this["torrents"] = [];
+        }
+        if (!("fileCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["fileCount"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MissingReport instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {MissingReport}
+     */
+    static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType32;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("torrents" in $$parsedSource) {
+            $$parsedSource["torrents"] = $$createField1_0($$parsedSource["torrents"]);
+        }
+        return new MissingReport(/** @type {Partial<MissingReport>} */($$parsedSource));
+    }
+}
+
+/**
+ * MissingTorrent lists the missing files of a torrent
+ */
+export class MissingTorrent {
+    /**
+     * Creates a new MissingTorrent instance.
+     * @param {Partial<MissingTorrent>} [$$source = {}] - The source object to create the MissingTorrent.
+     */
+    constructor($$source = {}) {
+        if (!("torrent" in $$source)) {
+            /**
+             * @member
+             * @type {TorrentInfo}
+             */
+            this["torrent"] = (new TorrentInfo());
+        }
+        if (!("files" in $$source)) {
+            /**
+             * @member
+             * @type {MissingFile[]}
+             */
+            this["files"] = [];
+        }
+        if (!("totalFiles" in $$source)) {
+            /**
+             * files checked
+             * @member
+             * @type {number}
+             */
+            this["totalFiles"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MissingTorrent instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {MissingTorrent}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType33;
+        const $$createField1_0 = $$createType35;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("torrent" in $$parsedSource) {
+            $$parsedSource["torrent"] = $$createField0_0($$parsedSource["torrent"]);
+        }
+        if ("files" in $$parsedSource) {
+            $$parsedSource["files"] = $$createField1_0($$parsedSource["files"]);
+        }
+        return new MissingTorrent(/** @type {Partial<MissingTorrent>} */($$parsedSource));
+    }
+}
+
+/**
+ * MoveJournal records every step of a batch of moves as soon as it is done,
+ * so the batch can be undone later, even after a crash. Journals of renames
+ * in qBittorrent record the applied renames instead of disk moves.
+ */
+export class MoveJournal {
+    /**
+     * Creates a new MoveJournal instance.
+     * @param {Partial<MoveJournal>} [$$source = {}] - The source object to create the MoveJournal.
+     */
+    constructor($$source = {}) {
+        if (!("id" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["id"] = "";
+        }
+        if (!("hash" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["hash"] = "";
+        }
+        if (!("time" in $$source)) {
+            /**
+             * @member
+             * @type {time$0.Time}
+             */
+            this["time"] = null;
+        }
+        if (!("steps" in $$source)) {
+            /**
+             * in the order they were done
+             * @member
+             * @type {MoveStep[]}
+             */
+            this["steps"] = [];
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * qBittorrent renames, in the order they were applied
+             * @member
+             * @type {RenameOperation[] | undefined}
+             */
+            this["renames"] = undefined;
+        }
+        if (!("undone" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["undone"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MoveJournal instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {MoveJournal}
+     */
+    static createFrom($$source = {}) {
+        const $$createField3_0 = $$createType37;
+        const $$createField4_0 = $$createType1;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("steps" in $$parsedSource) {
+            $$parsedSource["steps"] = $$createField3_0($$parsedSource["steps"]);
+        }
+        if ("renames" in $$parsedSource) {
+            $$parsedSource["renames"] = $$createField4_0($$parsedSource["renames"]);
+        }
+        return new MoveJournal(/** @type {Partial<MoveJournal>} */($$parsedSource));
+    }
+}
+
+/**
+ * MoveResult summarises a batch of moves
+ */
+export class MoveResult {
+    /**
+     * Creates a new MoveResult instance.
+     * @param {Partial<MoveResult>} [$$source = {}] - The source object to create the MoveResult.
+     */
+    constructor($$source = {}) {
+        if (!("movedCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["movedCount"] = 0;
+        }
+        if (!("skippedCount" in $$source)) {
+            /**
+             * targets taken, with CollisionSkip
+             * @member
+             * @type {number}
+             */
+            this["skippedCount"] = 0;
+        }
+        if (!("failedCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["failedCount"] = 0;
+        }
+        if (!("moved" in $$source)) {
+            /**
+             * @member
+             * @type {RenameOperation[]}
+             */
+            this["moved"] = [];
+        }
+        if (!("errors" in $$source)) {
+            /**
+             * @member
+             * @type {string[]}
+             */
+            this["errors"] = [];
+        }
+        if (!("journalId" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["journalId"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MoveResult instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {MoveResult}
+     */
+    static createFrom($$source = {}) {
+        const $$createField3_0 = $$createType1;
+        const $$createField4_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("moved" in $$parsedSource) {
+            $$parsedSource["moved"] = $$createField3_0($$parsedSource["moved"]);
+        }
+        if ("errors" in $$parsedSource) {
+            $$parsedSource["errors"] = $$createField4_0($$parsedSource["errors"]);
+        }
+        return new MoveResult(/** @type {Partial<MoveResult>} */($$parsedSource));
+    }
+}
+
+/**
+ * MoveStep is a single file move on disk
+ */
+export class MoveStep {
+    /**
+     * Creates a new MoveStep instance.
+     * @param {Partial<MoveStep>} [$$source = {}] - The source object to create the MoveStep.
+     */
+    constructor($$source = {}) {
+        if (!("from" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["from"] = "";
+        }
+        if (!("to" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["to"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new MoveStep instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {MoveStep}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new MoveStep(/** @type {Partial<MoveStep>} */($$parsedSource));
+    }
+}
+
+/**
+ * Notification describes a match result worth telling someone about
+ */
+export class Notification {
+    /**
+     * Creates a new Notification instance.
+     * @param {Partial<Notification>} [$$source = {}] - The source object to create the Notification.
+     */
+    constructor($$source = {}) {
+        if (!("kind" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["kind"] = "";
+        }
+        if (!("hash" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["hash"] = "";
+        }
+        if (!("name" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("outcome" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["outcome"] = "";
+        }
+        if (!("renamedCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["renamedCount"] = 0;
+        }
+        if (!("failedCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["failedCount"] = 0;
+        }
+        if (!("message" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["message"] = "";
+        }
+        if (!("time" in $$source)) {
+            /**
+             * @member
+             * @type {time$0.Time}
+             */
+            this["time"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Notification instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {Notification}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Notification(/** @type {Partial<Notification>} */($$parsedSource));
+    }
+}
+
+/**
+ * OrphanDir is a directory with files no torrent points at
+ */
+export class OrphanDir {
+    /**
+     * Creates a new OrphanDir instance.
+     * @param {Partial<OrphanDir>} [$$source = {}] - The source object to create the OrphanDir.
+     */
+    constructor($$source = {}) {
+        if (!("dir" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["dir"] = "";
+        }
+        if (!("files" in $$source)) {
+            /**
+             * @member
+             * @type {DiskFile[]}
+             */
+            this["files"] = [];
+        }
+        if (!("size" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["size"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new OrphanDir instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {OrphanDir}
+     */
+    static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType24;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("files" in $$parsedSource) {
+            $$parsedSource["files"] = $$createField1_0($$parsedSource["files"]);
+        }
+        return new OrphanDir(/** @type {Partial<OrphanDir>} */($$parsedSource));
+    }
+}
+
+/**
+ * OrphanReport lists the disk files under the scanned roots that no torrent
+ * in qBittorrent points at, grouped by directory
+ */
+export class OrphanReport {
+    /**
+     * Creates a new OrphanReport instance.
+     * @param {Partial<OrphanReport>} [$$source = {}] - The source object to create the OrphanReport.
+     */
+    constructor($$source = {}) {
+        if (!("roots" in $$source)) {
+            /**
+             * @member
+             * @type {string[]}
+             */
+            this["roots"] = [];
+        }
+        if (!("dirs" in $$source)) {
+            /**
+             * @member
+             * @type {OrphanDir[]}
+             */
+            this["dirs"] = [];
+        }
+        if (!("fileCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["fileCount"] = 0;
+        }
+        if (!("totalSize" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["totalSize"] = 0;
+        }
+        if (!("referenced" in $$source)) {
+            /**
+             * scanned files some torrent points at
+             * @member
+             * @type {number}
+             */
+            this["referenced"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new OrphanReport instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {OrphanReport}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType2;
+        const $$createField1_0 = $$createType39;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("roots" in $$parsedSource) {
+            $$parsedSource["roots"] = $$createField0_0($$parsedSource["roots"]);
+        }
+        if ("dirs" in $$parsedSource) {
+            $$parsedSource["dirs"] = $$createField1_0($$parsedSource["dirs"]);
+        }
+        return new OrphanReport(/** @type {Partial<OrphanReport>} */($$parsedSource));
+    }
+}
+
 /**
- * ConnectionConfig represents connection settings
+ * OrphansRequest describes roots to search for files no torrent points at
  */
-export class ConnectionConfig {
+export class OrphansRequest {
     /**
-     * Creates a new ConnectionConfig instance.
-     * @param {Partial<ConnectionConfig>} [$$source = {}] - The source object to create the ConnectionConfig.
+     * Creates a new OrphansRequest instance.
+     * @param {Partial<OrphansRequest>} [$$source = {}] - The source object to create the OrphansRequest.
      */
     constructor($$source = {}) {
-        if (!("url" in $$source)) {
+        if (!("roots" in $$source)) {
             /**
              * @member
-             * @type {string}
+             * @type {string[]}
              */
-            this["url"] = "";
+            this["roots"] = [];
         }
-        if (!("username" in $$source)) {
+        if (!("trash" in $$source)) {
             /**
+             * left out of the report
              * @member
              * @type {string}
              */
-            this["username"] = "";
+            this["trash"] = "";
         }
-        if (!("password" in $$source)) {
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new OrphansRequest instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {OrphansRequest}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("roots" in $$parsedSource) {
+            $$parsedSource["roots"] = $$createField0_0($$parsedSource["roots"]);
+        }
+        return new OrphansRequest(/** @type {Partial<OrphansRequest>} */($$parsedSource));
+    }
+}
+
+/**
+ * PieceLayout describes how a torrent's pieces span its files
+ */
+export class PieceLayout {
+    /**
+     * Creates a new PieceLayout instance.
+     * @param {Partial<PieceLayout>} [$$source = {}] - The source object to create the PieceLayout.
+     */
+    constructor($$source = {}) {
+        if (!("pieceLength" in $$source)) {
             /**
              * @member
-             * @type {string}
+             * @type {number}
              */
-            this["password"] = "";
+            this["pieceLength"] = 0;
+        }
+        if (!("pieceHashes" in $$source)) {
+            /**
+             * hex-encoded SHA-1
+             * @member
+             * @type {string[]}
+             */
+            this["pieceHashes"] = [];
+        }
+        if (!("files" in $$source)) {
+            /**
+             * in torrent order
+             * @member
+             * @type {TorrentFileInfo[]}
+             */
+            this["files"] = [];
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new ConnectionConfig instance from a string or object.
+     * Creates a new PieceLayout instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {ConnectionConfig}
+     * @returns {PieceLayout}
      */
     static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType2;
+        const $$createField2_0 = $$createType28;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new ConnectionConfig(/** @type {Partial<ConnectionConfig>} */($$parsedSource));
+        if ("pieceHashes" in $$parsedSource) {
+            $$parsedSource["pieceHashes"] = $$createField1_0($$parsedSource["pieceHashes"]);
+        }
+        if ("files" in $$parsedSource) {
+            $$parsedSource["files"] = $$createField2_0($$parsedSource["files"]);
+        }
+        return new PieceLayout(/** @type {Partial<PieceLayout>} */($$parsedSource));
     }
 }
 
 /**
- * DiskFile represents a file on the disk
+ * PieceReport summarises which pieces would pass verification
  */
-export class DiskFile {
+export class PieceReport {
     /**
-     * Creates a new DiskFile instance.
-     * @param {Partial<DiskFile>} [$$source = {}] - The source object to create the DiskFile.
+     * Creates a new PieceReport instance.
+     * @param {Partial<PieceReport>} [$$source = {}] - The source object to create the PieceReport.
      */
     constructor($$source = {}) {
-        if (!("path" in $$source)) {
+        if (!("totalPieces" in $$source)) {
             /**
              * @member
-             * @type {string}
+             * @type {number}
              */
-            this["path"] = "";
+            this["totalPieces"] = 0;
         }
-        if (!("name" in $$source)) {
+        if (!("passed" in $$source)) {
             /**
              * @member
-             * @type {string}
+             * @type {number}
              */
-            this["name"] = "";
+            this["passed"] = 0;
         }
-        if (!("size" in $$source)) {
+        if (!("failed" in $$source)) {
             /**
              * @member
              * @type {number}
              */
-            this["size"] = 0;
+            this["failed"] = 0;
+        }
+        if (!("unchecked" in $$source)) {
+            /**
+             * pieces touching files without a disk file
+             * @member
+             * @type {number}
+             */
+            this["unchecked"] = 0;
+        }
+        if (!("files" in $$source)) {
+            /**
+             * @member
+             * @type {FilePieces[]}
+             */
+            this["files"] = [];
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new DiskFile instance from a string or object.
+     * Creates a new PieceReport instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {DiskFile}
+     * @returns {PieceReport}
      */
     static createFrom($$source = {}) {
+        const $$createField4_0 = $$createType41;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new DiskFile(/** @type {Partial<DiskFile>} */($$parsedSource));
+        if ("files" in $$parsedSource) {
+            $$parsedSource["files"] = $$createField4_0($$parsedSource["files"]);
+        }
+        return new PieceReport(/** @type {Partial<PieceReport>} */($$parsedSource));
     }
 }
 
 /**
- * DiskFileInfo represents a file on disk for the frontend
+ * PostProcessOptions controls what happens to a torrent after it was matched
  */
-export class DiskFileInfo {
+export class PostProcessOptions {
     /**
-     * Creates a new DiskFileInfo instance.
-     * @param {Partial<DiskFileInfo>} [$$source = {}] - The source object to create the DiskFileInfo.
+     * Creates a new PostProcessOptions instance.
+     * @param {Partial<PostProcessOptions>} [$$source = {}] - The source object to create the PostProcessOptions.
      */
     constructor($$source = {}) {
-        if (!("path" in $$source)) {
+        if (!("tagOutcome" in $$source)) {
+            /**
+             * add the outcome as a tag
+             * @member
+             * @type {boolean}
+             */
+            this["tagOutcome"] = false;
+        }
+        if (!("tags" in $$source)) {
+            /**
+             * extra tags added regardless of outcome
+             * @member
+             * @type {string[]}
+             */
+            this["tags"] = [];
+        }
+        if (!("category" in $$source)) {
             /**
+             * set only when every file matched
              * @member
              * @type {string}
              */
-            this["path"] = "";
+            this["category"] = "";
         }
-        if (!("name" in $$source)) {
+        if (!("resume" in $$source)) {
+            /**
+             * resume only when every file matched
+             * @member
+             * @type {boolean}
+             */
+            this["resume"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PostProcessOptions instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {PostProcessOptions}
+     */
+    static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField1_0($$parsedSource["tags"]);
+        }
+        return new PostProcessOptions(/** @type {Partial<PostProcessOptions>} */($$parsedSource));
+    }
+}
+
+/**
+ * Rebuild writes a torrent file made of parts of other disk files to where
+ * the renames expect it
+ */
+export class Rebuild {
+    /**
+     * Creates a new Rebuild instance.
+     * @param {Partial<Rebuild>} [$$source = {}] - The source object to create the Rebuild.
+     */
+    constructor($$source = {}) {
+        if (!("target" in $$source)) {
             /**
              * @member
              * @type {string}
              */
-            this["name"] = "";
+            this["target"] = "";
+        }
+        if (!("parts" in $$source)) {
+            /**
+             * @member
+             * @type {FileSegment[]}
+             */
+            this["parts"] = [];
         }
         if (!("size" in $$source)) {
             /**
@@ -131,200 +2485,246 @@ export class DiskFileInfo {
     }
 
     /**
-     * Creates a new DiskFileInfo instance from a string or object.
+     * Creates a new Rebuild instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {DiskFileInfo}
+     * @returns {Rebuild}
      */
     static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType17;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        return new DiskFileInfo(/** @type {Partial<DiskFileInfo>} */($$parsedSource));
+        if ("parts" in $$parsedSource) {
+            $$parsedSource["parts"] = $$createField1_0($$parsedSource["parts"]);
+        }
+        return new Rebuild(/** @type {Partial<Rebuild>} */($$parsedSource));
     }
 }
 
 /**
- * MatchInfo represents a single match for the frontend
+ * RecheckFile represents the verification outcome of a single torrent file
  */
-export class MatchInfo {
+export class RecheckFile {
     /**
-     * Creates a new MatchInfo instance.
-     * @param {Partial<MatchInfo>} [$$source = {}] - The source object to create the MatchInfo.
+     * Creates a new RecheckFile instance.
+     * @param {Partial<RecheckFile>} [$$source = {}] - The source object to create the RecheckFile.
      */
     constructor($$source = {}) {
-        if (!("torrentFile" in $$source)) {
+        if (!("index" in $$source)) {
             /**
              * @member
-             * @type {TorrentFileInfo}
+             * @type {number}
              */
-            this["torrentFile"] = (new TorrentFileInfo());
+            this["index"] = 0;
         }
-        if (!("diskFiles" in $$source)) {
+        if (!("name" in $$source)) {
             /**
              * @member
-             * @type {DiskFile[]}
+             * @type {string}
              */
-            this["diskFiles"] = [];
+            this["name"] = "";
         }
-        if (!("selected" in $$source)) {
+        if (!("size" in $$source)) {
             /**
              * @member
-             * @type {DiskFile | null}
+             * @type {number}
              */
-            this["selected"] = null;
+            this["size"] = 0;
         }
-        if (!("autoMatched" in $$source)) {
+        if (!("progress" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["progress"] = 0;
+        }
+        if (!("complete" in $$source)) {
             /**
              * @member
              * @type {boolean}
              */
-            this["autoMatched"] = false;
+            this["complete"] = false;
+        }
+        if (!("skipped" in $$source)) {
+            /**
+             * priority 0 or a pad file, not expected to verify
+             * @member
+             * @type {boolean}
+             */
+            this["skipped"] = false;
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new MatchInfo instance from a string or object.
+     * Creates a new RecheckFile instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {MatchInfo}
+     * @returns {RecheckFile}
      */
     static createFrom($$source = {}) {
-        const $$createField0_0 = $$createType0;
-        const $$createField1_0 = $$createType2;
-        const $$createField2_0 = $$createType3;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        if ("torrentFile" in $$parsedSource) {
-            $$parsedSource["torrentFile"] = $$createField0_0($$parsedSource["torrentFile"]);
-        }
-        if ("diskFiles" in $$parsedSource) {
-            $$parsedSource["diskFiles"] = $$createField1_0($$parsedSource["diskFiles"]);
-        }
-        if ("selected" in $$parsedSource) {
-            $$parsedSource["selected"] = $$createField2_0($$parsedSource["selected"]);
-        }
-        return new MatchInfo(/** @type {Partial<MatchInfo>} */($$parsedSource));
+        return new RecheckFile(/** @type {Partial<RecheckFile>} */($$parsedSource));
     }
 }
 
 /**
- * MatchRequest represents a request to find matches
+ * RecheckReport represents the result of waiting for a torrent recheck
  */
-export class MatchRequest {
+export class RecheckReport {
     /**
-     * Creates a new MatchRequest instance.
-     * @param {Partial<MatchRequest>} [$$source = {}] - The source object to create the MatchRequest.
+     * Creates a new RecheckReport instance.
+     * @param {Partial<RecheckReport>} [$$source = {}] - The source object to create the RecheckReport.
      */
     constructor($$source = {}) {
-        if (!("torrentFiles" in $$source)) {
+        if (!("hash" in $$source)) {
             /**
              * @member
-             * @type {TorrentFileInfo[]}
+             * @type {string}
              */
-            this["torrentFiles"] = [];
+            this["hash"] = "";
         }
-        if (!("diskFiles" in $$source)) {
+        if (!("state" in $$source)) {
             /**
              * @member
-             * @type {DiskFile[]}
+             * @type {string}
              */
-            this["diskFiles"] = [];
+            this["state"] = "";
         }
-        if (!("requireSameExtension" in $$source)) {
+        if (!("timedOut" in $$source)) {
             /**
              * @member
              * @type {boolean}
              */
-            this["requireSameExtension"] = false;
+            this["timedOut"] = false;
+        }
+        if (!("files" in $$source)) {
+            /**
+             * @member
+             * @type {RecheckFile[]}
+             */
+            this["files"] = [];
+        }
+        if (!("completeCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["completeCount"] = 0;
+        }
+        if (!("incompleteCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["incompleteCount"] = 0;
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new MatchRequest instance from a string or object.
+     * Creates a new RecheckReport instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {MatchRequest}
+     * @returns {RecheckReport}
      */
     static createFrom($$source = {}) {
-        const $$createField0_0 = $$createType4;
-        const $$createField1_0 = $$createType2;
+        const $$createField3_0 = $$createType43;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        if ("torrentFiles" in $$parsedSource) {
-            $$parsedSource["torrentFiles"] = $$createField0_0($$parsedSource["torrentFiles"]);
+        if ("files" in $$parsedSource) {
+            $$parsedSource["files"] = $$createField3_0($$parsedSource["files"]);
         }
-        if ("diskFiles" in $$parsedSource) {
-            $$parsedSource["diskFiles"] = $$createField1_0($$parsedSource["diskFiles"]);
+        return new RecheckReport(/** @type {Partial<RecheckReport>} */($$parsedSource));
+    }
+}
+
+/**
+ * RenameOp represents a single rename operation
+ */
+export class RenameOp {
+    /**
+     * Creates a new RenameOp instance.
+     * @param {Partial<RenameOp>} [$$source = {}] - The source object to create the RenameOp.
+     */
+    constructor($$source = {}) {
+        if (!("oldPath" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["oldPath"] = "";
+        }
+        if (!("newPath" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["newPath"] = "";
+        }
+        if (!("torrentFile" in $$source)) {
+            /**
+             * @member
+             * @type {TorrentFileInfo}
+             */
+            this["torrentFile"] = (new TorrentFileInfo());
         }
-        return new MatchRequest(/** @type {Partial<MatchRequest>} */($$parsedSource));
-    }
-}
-
-/**
- * MatchResponse represents the match results
- */
-export class MatchResponse {
-    /**
-     * Creates a new MatchResponse instance.
-     * @param {Partial<MatchResponse>} [$$source = {}] - The source object to create the MatchResponse.
-     */
-    constructor($$source = {}) {
-        if (!("matches" in $$source)) {
+        if (!("diskFile" in $$source)) {
             /**
              * @member
-             * @type {MatchInfo[]}
+             * @type {DiskFile}
              */
-            this["matches"] = [];
+            this["diskFile"] = (new DiskFile());
         }
-        if (!("unmatched" in $$source)) {
+        if (!("isFolder" in $$source)) {
             /**
              * @member
-             * @type {TorrentFileInfo[]}
+             * @type {boolean}
              */
-            this["unmatched"] = [];
+            this["isFolder"] = false;
         }
-        if (!("totalFiles" in $$source)) {
+        if (!("fileCount" in $$source)) {
             /**
              * @member
              * @type {number}
              */
-            this["totalFiles"] = 0;
+            this["fileCount"] = 0;
         }
-        if (!("matchedCount" in $$source)) {
+        if (/** @type {any} */(false)) {
             /**
              * @member
-             * @type {number}
+             * @type {boolean | undefined}
              */
-            this["matchedCount"] = 0;
+            this["partial"] = undefined;
         }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new MatchResponse instance from a string or object.
+     * Creates a new RenameOp instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {MatchResponse}
+     * @returns {RenameOp}
      */
     static createFrom($$source = {}) {
-        const $$createField0_0 = $$createType6;
-        const $$createField1_0 = $$createType4;
+        const $$createField2_0 = $$createType22;
+        const $$createField3_0 = $$createType23;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
-        if ("matches" in $$parsedSource) {
-            $$parsedSource["matches"] = $$createField0_0($$parsedSource["matches"]);
+        if ("torrentFile" in $$parsedSource) {
+            $$parsedSource["torrentFile"] = $$createField2_0($$parsedSource["torrentFile"]);
         }
-        if ("unmatched" in $$parsedSource) {
-            $$parsedSource["unmatched"] = $$createField1_0($$parsedSource["unmatched"]);
+        if ("diskFile" in $$parsedSource) {
+            $$parsedSource["diskFile"] = $$createField3_0($$parsedSource["diskFile"]);
         }
-        return new MatchResponse(/** @type {Partial<MatchResponse>} */($$parsedSource));
+        return new RenameOp(/** @type {Partial<RenameOp>} */($$parsedSource));
     }
 }
 
 /**
- * RenameOp represents a single rename operation
+ * RenameOperation represents a single rename operation
+ * Folder operations rename a whole torrent folder and carry no file details
  */
-export class RenameOp {
+export class RenameOperation {
     /**
-     * Creates a new RenameOp instance.
-     * @param {Partial<RenameOp>} [$$source = {}] - The source object to create the RenameOp.
+     * Creates a new RenameOperation instance.
+     * @param {Partial<RenameOperation>} [$$source = {}] - The source object to create the RenameOperation.
      */
     constructor($$source = {}) {
         if (!("oldPath" in $$source)) {
@@ -355,18 +2755,40 @@ export class RenameOp {
              */
             this["diskFile"] = (new DiskFile());
         }
+        if (!("isFolder" in $$source)) {
+            /**
+             * @member
+             * @type {boolean}
+             */
+            this["isFolder"] = false;
+        }
+        if (!("fileCount" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["fileCount"] = 0;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * the disk file is a partial download
+             * @member
+             * @type {boolean | undefined}
+             */
+            this["partial"] = undefined;
+        }
 
         Object.assign(this, $$source);
     }
 
     /**
-     * Creates a new RenameOp instance from a string or object.
+     * Creates a new RenameOperation instance from a string or object.
      * @param {any} [$$source = {}]
-     * @returns {RenameOp}
+     * @returns {RenameOperation}
      */
     static createFrom($$source = {}) {
-        const $$createField2_0 = $$createType0;
-        const $$createField3_0 = $$createType1;
+        const $$createField2_0 = $$createType22;
+        const $$createField3_0 = $$createType23;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("torrentFile" in $$parsedSource) {
             $$parsedSource["torrentFile"] = $$createField2_0($$parsedSource["torrentFile"]);
@@ -374,12 +2796,14 @@ export class RenameOp {
         if ("diskFile" in $$parsedSource) {
             $$parsedSource["diskFile"] = $$createField3_0($$parsedSource["diskFile"]);
         }
-        return new RenameOp(/** @type {Partial<RenameOp>} */($$parsedSource));
+        return new RenameOperation(/** @type {Partial<RenameOperation>} */($$parsedSource));
     }
 }
 
 /**
  * RenameRequest represents a rename operation request
+ * When TorrentFiles lists every file of the torrent, renames that move a whole
+ * folder are collapsed into a single folder rename
  */
 export class RenameRequest {
     /**
@@ -401,6 +2825,13 @@ export class RenameRequest {
              */
             this["searchPath"] = "";
         }
+        if (!("torrentFiles" in $$source)) {
+            /**
+             * @member
+             * @type {TorrentFileInfo[]}
+             */
+            this["torrentFiles"] = [];
+        }
 
         Object.assign(this, $$source);
     }
@@ -411,15 +2842,96 @@ export class RenameRequest {
      * @returns {RenameRequest}
      */
     static createFrom($$source = {}) {
-        const $$createField0_0 = $$createType6;
+        const $$createField0_0 = $$createType30;
+        const $$createField2_0 = $$createType28;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("matches" in $$parsedSource) {
             $$parsedSource["matches"] = $$createField0_0($$parsedSource["matches"]);
         }
+        if ("torrentFiles" in $$parsedSource) {
+            $$parsedSource["torrentFiles"] = $$createField2_0($$parsedSource["torrentFiles"]);
+        }
         return new RenameRequest(/** @type {Partial<RenameRequest>} */($$parsedSource));
     }
 }
 
+/**
+ * RevertRequest represents a request to undo renames that failed verification
+ */
+export class RevertRequest {
+    /**
+     * Creates a new RevertRequest instance.
+     * @param {Partial<RevertRequest>} [$$source = {}] - The source object to create the RevertRequest.
+     */
+    constructor($$source = {}) {
+        if (!("renames" in $$source)) {
+            /**
+             * @member
+             * @type {RenameOp[]}
+             */
+            this["renames"] = [];
+        }
+        if (!("report" in $$source)) {
+            /**
+             * @member
+             * @type {RecheckReport}
+             */
+            this["report"] = (new RecheckReport());
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new RevertRequest instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {RevertRequest}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType10;
+        const $$createField1_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("renames" in $$parsedSource) {
+            $$parsedSource["renames"] = $$createField0_0($$parsedSource["renames"]);
+        }
+        if ("report" in $$parsedSource) {
+            $$parsedSource["report"] = $$createField1_0($$parsedSource["report"]);
+        }
+        return new RevertRequest(/** @type {Partial<RevertRequest>} */($$parsedSource));
+    }
+}
+
+/**
+ * RuleSet is a compiled list of rules, tried in order
+ */
+export class RuleSet {
+    /**
+     * Creates a new RuleSet instance.
+     * @param {Partial<RuleSet>} [$$source = {}] - The source object to create the RuleSet.
+     */
+    constructor($$source = {}) {
+        if (!("Profile" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["Profile"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new RuleSet instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {RuleSet}
+     */
+    static createFrom($$source = {}) {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new RuleSet(/** @type {Partial<RuleSet>} */($$parsedSource));
+    }
+}
+
 /**
  * TorrentFileInfo represents a file in a torrent for the frontend
  */
@@ -457,6 +2969,13 @@ export class TorrentFile {
              */
             this["progress"] = 0;
         }
+        if (!("priority" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["priority"] = 0;
+        }
 
         Object.assign(this, $$source);
     }
@@ -502,6 +3021,23 @@ export class TorrentFileInfo {
              */
             this["size"] = 0;
         }
+        if (/** @type {any} */(false)) {
+            /**
+             * BEP 47 pad file, see IsPad
+             * @member
+             * @type {boolean | undefined}
+             */
+            this["pad"] = undefined;
+        }
+        if (/** @type {any} */(false)) {
+            /**
+             * Fingerprint of a reference copy, see ReferenceFingerprints. Candidates
+             * with another fingerprint are dropped.
+             * @member
+             * @type {string | undefined}
+             */
+            this["fingerprint"] = undefined;
+        }
 
         Object.assign(this, $$source);
     }
@@ -561,6 +3097,13 @@ export class TorrentInfo {
              */
             this["state"] = "";
         }
+        if (!("category" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["category"] = "";
+        }
         if (!("savePath" in $$source)) {
             /**
              * @member
@@ -575,6 +3118,15 @@ export class TorrentInfo {
              */
             this["contentPath"] = "";
         }
+        if (/** @type {any} */(false)) {
+            /**
+             * DownloadPath is where incomplete files are kept, when qBittorrent
+             * keeps them apart from the save path
+             * @member
+             * @type {string | undefined}
+             */
+            this["downloadPath"] = undefined;
+        }
 
         Object.assign(this, $$source);
     }
@@ -590,11 +3142,200 @@ export class TorrentInfo {
     }
 }
 
+/**
+ * TorrentMetaInfo represents a parsed .torrent file for the frontend
+ */
+export class TorrentMetaInfo {
+    /**
+     * Creates a new TorrentMetaInfo instance.
+     * @param {Partial<TorrentMetaInfo>} [$$source = {}] - The source object to create the TorrentMetaInfo.
+     */
+    constructor($$source = {}) {
+        if (!("hash" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["hash"] = "";
+        }
+        if (!("name" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["name"] = "";
+        }
+        if (!("size" in $$source)) {
+            /**
+             * @member
+             * @type {number}
+             */
+            this["size"] = 0;
+        }
+        if (!("files" in $$source)) {
+            /**
+             * @member
+             * @type {TorrentFileInfo[]}
+             */
+            this["files"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TorrentMetaInfo instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {TorrentMetaInfo}
+     */
+    static createFrom($$source = {}) {
+        const $$createField3_0 = $$createType28;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("files" in $$parsedSource) {
+            $$parsedSource["files"] = $$createField3_0($$parsedSource["files"]);
+        }
+        return new TorrentMetaInfo(/** @type {Partial<TorrentMetaInfo>} */($$parsedSource));
+    }
+}
+
+/**
+ * TrashOrphansRequest describes orphans to move to a trash directory
+ */
+export class TrashOrphansRequest {
+    /**
+     * Creates a new TrashOrphansRequest instance.
+     * @param {Partial<TrashOrphansRequest>} [$$source = {}] - The source object to create the TrashOrphansRequest.
+     */
+    constructor($$source = {}) {
+        if (!("files" in $$source)) {
+            /**
+             * @member
+             * @type {DiskFile[]}
+             */
+            this["files"] = [];
+        }
+        if (!("roots" in $$source)) {
+            /**
+             * @member
+             * @type {string[]}
+             */
+            this["roots"] = [];
+        }
+        if (!("trash" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["trash"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrashOrphansRequest instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {TrashOrphansRequest}
+     */
+    static createFrom($$source = {}) {
+        const $$createField0_0 = $$createType24;
+        const $$createField1_0 = $$createType2;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("files" in $$parsedSource) {
+            $$parsedSource["files"] = $$createField0_0($$parsedSource["files"]);
+        }
+        if ("roots" in $$parsedSource) {
+            $$parsedSource["roots"] = $$createField1_0($$parsedSource["roots"]);
+        }
+        return new TrashOrphansRequest(/** @type {Partial<TrashOrphansRequest>} */($$parsedSource));
+    }
+}
+
+/**
+ * VerifyPiecesRequest asks which pieces of a torrent would pass with the
+ * selected disk files
+ */
+export class VerifyPiecesRequest {
+    /**
+     * Creates a new VerifyPiecesRequest instance.
+     * @param {Partial<VerifyPiecesRequest>} [$$source = {}] - The source object to create the VerifyPiecesRequest.
+     */
+    constructor($$source = {}) {
+        if (!("hash" in $$source)) {
+            /**
+             * @member
+             * @type {string}
+             */
+            this["hash"] = "";
+        }
+        if (!("matches" in $$source)) {
+            /**
+             * @member
+             * @type {MatchInfo[]}
+             */
+            this["matches"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new VerifyPiecesRequest instance from a string or object.
+     * @param {any} [$$source = {}]
+     * @returns {VerifyPiecesRequest}
+     */
+    static createFrom($$source = {}) {
+        const $$createField1_0 = $$createType30;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("matches" in $$parsedSource) {
+            $$parsedSource["matches"] = $$createField1_0($$parsedSource["matches"]);
+        }
+        return new VerifyPiecesRequest(/** @type {Partial<VerifyPiecesRequest>} */($$parsedSource));
+    }
+}
+
 // Private type creation functions
-const $$createType0 = TorrentFileInfo.createFrom;
-const $$createType1 = DiskFile.createFrom;
-const $$createType2 = $Create.Array($$createType1);
-const $$createType3 = $Create.Nullable($$createType1);
-const $$createType4 = $Create.Array($$createType0);
-const $$createType5 = MatchInfo.createFrom;
+const $$createType0 = RenameOperation.createFrom;
+const $$createType1 = $Create.Array($$createType0);
+const $$createType2 = $Create.Array($Create.Any);
+const $$createType3 = RecheckReport.createFrom;
+const $$createType4 = $Create.Nullable($$createType3);
+const $$createType5 = Extraction.createFrom;
 const $$createType6 = $Create.Array($$createType5);
+const $$createType7 = Rebuild.createFrom;
+const $$createType8 = $Create.Array($$createType7);
+const $$createType9 = RenameOp.createFrom;
+const $$createType10 = $Create.Array($$createType9);
+const $$createType11 = LinkOperation.createFrom;
+const $$createType12 = $Create.Array($$createType11);
+const $$createType13 = LinkResult.createFrom;
+const $$createType14 = $Create.Nullable($$createType13);
+const $$createType15 = $Create.Map($Create.Any, $Create.Any);
+const $$createType16 = FileSegment.createFrom;
+const $$createType17 = $Create.Array($$createType16);
+const $$createType18 = DuplicateFile.createFrom;
+const $$createType19 = $Create.Array($$createType18);
+const $$createType20 = DuplicateGroup.createFrom;
+const $$createType21 = $Create.Array($$createType20);
+const $$createType22 = TorrentFileInfo.createFrom;
+const $$createType23 = DiskFile.createFrom;
+const $$createType24 = $Create.Array($$createType23);
+const $$createType25 = $Create.Nullable($$createType23);
+const $$createType26 = RuleSet.createFrom;
+const $$createType27 = $Create.Nullable($$createType26);
+const $$createType28 = $Create.Array($$createType22);
+const $$createType29 = MatchInfo.createFrom;
+const $$createType30 = $Create.Array($$createType29);
+const $$createType31 = MissingTorrent.createFrom;
+const $$createType32 = $Create.Array($$createType31);
+const $$createType33 = TorrentInfo.createFrom;
+const $$createType34 = MissingFile.createFrom;
+const $$createType35 = $Create.Array($$createType34);
+const $$createType36 = MoveStep.createFrom;
+const $$createType37 = $Create.Array($$createType36);
+const $$createType38 = OrphanDir.createFrom;
+const $$createType39 = $Create.Array($$createType38);
+const $$createType40 = FilePieces.createFrom;
+const $$createType41 = $Create.Array($$createType40);
+const $$createType42 = RecheckFile.createFrom;
+const $$createType43 = $Create.Array($$createType42);
@@ -15,6 +15,43 @@ import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Cr
 // @ts-ignore: Unused imports
 import * as $models from "./models.js";
 
+/**
+ * AddMatchedTorrent adds a .torrent paused with the given save path, applies
+ * renames and unwanted-file priorities, then optionally rechecks and resumes it
+ * @param {$models.AddTorrentRequest} req
+ * @returns {$CancellablePromise<$models.AddTorrentResult>}
+ */
+export function AddMatchedTorrent(req) {
+    return $Call.ByID(741127719, req).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType0($result);
+    }));
+}
+
+/**
+ * AddTags adds tags to a torrent, creating any that don't exist yet
+ * @param {string} hash
+ * @param {string[]} tags
+ * @returns {$CancellablePromise<void>}
+ */
+export function AddTags(hash, tags) {
+    return $Call.ByID(4201227340, hash, tags);
+}
+
+/**
+ * ApplyRenames renames files and folders of a torrent in qBittorrent. A
+ * failed operation is recorded and the remaining ones are still applied.
+ * With a JournalDir, applied renames are journaled so they can be reverted
+ * later.
+ * @param {string} hash
+ * @param {$models.RenameOperation[]} renames
+ * @returns {$CancellablePromise<$models.ApplyResult>}
+ */
+export function ApplyRenames(hash, renames) {
+    return $Call.ByID(433369501, hash, renames).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType1($result);
+    }));
+}
+
 /**
  * Connect connects to qBittorrent
  * @param {$models.ConnectionConfig} config
@@ -32,6 +69,69 @@ export function Disconnect() {
     return $Call.ByID(268041660);
 }
 
+/**
+ * FindDuplicateFiles reports the files torrents in qBittorrent share. With
+ * verifyPieces, the piece hashes of torrents sharing a file size are fetched
+ * so files are also grouped, and verified, by content.
+ * @param {boolean} verifyPieces
+ * @returns {$CancellablePromise<$models.DuplicateReport>}
+ */
+export function FindDuplicateFiles(verifyPieces) {
+    return $Call.ByID(2681265649, verifyPieces).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType2($result);
+    }));
+}
+
+/**
+ * FindMissingFiles checks every torrent's files on disk and, when roots are
+ * given, scans them once for candidates for the files that are missing.
+ * Save paths must be paths on this machine.
+ * @param {string[]} roots
+ * @param {$models.MatchOptions} opts
+ * @returns {$CancellablePromise<$models.MissingReport>}
+ */
+export function FindMissingFiles(roots, opts) {
+    return $Call.ByID(531786070, roots, opts).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType3($result);
+    }));
+}
+
+/**
+ * FindOrphanFiles scans roots and reports the files no torrent in
+ * qBittorrent points at. Files under exclude, e.g. the trash directory, are
+ * left out. Save paths must be paths on this machine.
+ * @param {string[]} roots
+ * @param {string} exclude
+ * @returns {$CancellablePromise<$models.OrphanReport>}
+ */
+export function FindOrphanFiles(roots, exclude) {
+    return $Call.ByID(547196154, roots, exclude).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType4($result);
+    }));
+}
+
+/**
+ * GetPieceLayout returns the piece layout of a torrent in qBittorrent
+ * @param {string} hash
+ * @returns {$CancellablePromise<$models.PieceLayout>}
+ */
+export function GetPieceLayout(hash) {
+    return $Call.ByID(795407166, hash).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType5($result);
+    }));
+}
+
+/**
+ * GetTorrent returns a single torrent
+ * @param {string} hash
+ * @returns {$CancellablePromise<$models.TorrentInfo>}
+ */
+export function GetTorrent(hash) {
+    return $Call.ByID(4147646696, hash).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType6($result);
+    }));
+}
+
 /**
  * GetTorrentFiles returns files for a specific torrent
  * @param {string} hash
@@ -39,7 +139,7 @@ export function Disconnect() {
  */
 export function GetTorrentFiles(hash) {
     return $Call.ByID(3253337623, hash).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType1($result);
+        return $$createType8($result);
     }));
 }
 
@@ -49,7 +149,7 @@ export function GetTorrentFiles(hash) {
  */
 export function GetTorrents() {
     return $Call.ByID(3359777793).then(/** @type {($result: any) => any} */(($result) => {
-        return $$createType3($result);
+        return $$createType9($result);
     }));
 }
 
@@ -69,6 +169,32 @@ export function IsConnected() {
     return $Call.ByID(901761037);
 }
 
+/**
+ * JournalReverts returns the operations that undo the journaled renames of
+ * files which fail verification now, as RevertRenames does. The torrent
+ * should have been rechecked since the renames were applied.
+ * @param {$models.MoveJournal | null} journal
+ * @returns {$CancellablePromise<[$models.RenameOperation[], $models.RecheckReport]>}
+ */
+export function JournalReverts(journal) {
+    return $Call.ByID(756299816, journal).then(/** @type {($result: any) => any} */(($result) => {
+        $result[0] = $$createType11($result[0]);
+        $result[1] = $$createType12($result[1]);
+        return $result;
+    }));
+}
+
+/**
+ * PostProcess tags, categorizes and resumes a torrent based on its match outcome
+ * @param {string} hash
+ * @param {string} outcome
+ * @param {$models.PostProcessOptions} opts
+ * @returns {$CancellablePromise<void>}
+ */
+export function PostProcess(hash, outcome, opts) {
+    return $Call.ByID(706663937, hash, outcome, opts);
+}
+
 /**
  * RecheckTorrent triggers a hash recheck for the torrent
  * @param {string} hash
@@ -89,6 +215,36 @@ export function RenameFile(hash, oldPath, newPath) {
     return $Call.ByID(1796283442, hash, oldPath, newPath);
 }
 
+/**
+ * RenameFolder renames a folder in qBittorrent, moving every file beneath it
+ * @param {string} hash
+ * @param {string} oldPath
+ * @param {string} newPath
+ * @returns {$CancellablePromise<void>}
+ */
+export function RenameFolder(hash, oldPath, newPath) {
+    return $Call.ByID(1710428464, hash, oldPath, newPath);
+}
+
+/**
+ * ResumeTorrent resumes (starts) a paused torrent
+ * @param {string} hash
+ * @returns {$CancellablePromise<void>}
+ */
+export function ResumeTorrent(hash) {
+    return $Call.ByID(2006274389, hash);
+}
+
+/**
+ * SetCategory sets the category of a torrent
+ * @param {string} hash
+ * @param {string} category
+ * @returns {$CancellablePromise<void>}
+ */
+export function SetCategory(hash, category) {
+    return $Call.ByID(3504412306, hash, category);
+}
+
 /**
  * SetFilePriority sets the priority for files in a torrent
  * IDs is a comma-separated list of file indices (e.g., "0,1,2")
@@ -112,8 +268,56 @@ export function SetTorrentLocation(hash, location) {
     return $Call.ByID(3908991547, hash, location);
 }
 
+/**
+ * UndoRenameJournal renames a journal's renames back, newest first. Renames
+ * that fail to undo stay in the journal so undoing again retries them; the
+ * journal is marked undone once all of them were.
+ * @param {$models.MoveJournal | null} journal
+ * @returns {$CancellablePromise<$models.ApplyResult>}
+ */
+export function UndoRenameJournal(journal) {
+    return $Call.ByID(638220477, journal).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType1($result);
+    }));
+}
+
+/**
+ * VerifyMatchPieces reports how many pieces of a torrent in qBittorrent would
+ * pass a recheck with the selected disk files, without renaming anything
+ * @param {$models.VerifyPiecesRequest} req
+ * @returns {$CancellablePromise<$models.PieceReport>}
+ */
+export function VerifyMatchPieces(req) {
+    return $Call.ByID(912091887, req).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType13($result);
+    }));
+}
+
+/**
+ * WaitForRecheck polls the torrent until qBittorrent finishes checking it,
+ * then reports per-file progress. A timeout of 0 uses DefaultRecheckTimeout.
+ * @param {string} hash
+ * @param {number} timeoutSeconds
+ * @returns {$CancellablePromise<$models.RecheckReport>}
+ */
+export function WaitForRecheck(hash, timeoutSeconds) {
+    return $Call.ByID(3737248217, hash, timeoutSeconds).then(/** @type {($result: any) => any} */(($result) => {
+        return $$createType12($result);
+    }));
+}
+
 // Private type creation functions
-const $$createType0 = $models.TorrentFile.createFrom;
-const $$createType1 = $Create.Array($$createType0);
-const $$createType2 = $models.TorrentInfo.createFrom;
-const $$createType3 = $Create.Array($$createType2);
+const $$createType0 = $models.AddTorrentResult.createFrom;
+const $$createType1 = $models.ApplyResult.createFrom;
+const $$createType2 = $models.DuplicateReport.createFrom;
+const $$createType3 = $models.MissingReport.createFrom;
+const $$createType4 = $models.OrphanReport.createFrom;
+const $$createType5 = $models.PieceLayout.createFrom;
+const $$createType6 = $models.TorrentInfo.createFrom;
+const $$createType7 = $models.TorrentFile.createFrom;
+const $$createType8 = $Create.Array($$createType7);
+const $$createType9 = $Create.Array($$createType6);
+const $$createType10 = $models.RenameOperation.createFrom;
+const $$createType11 = $Create.Array($$createType10);
+const $$createType12 = $models.RecheckReport.createFrom;
+const $$createType13 = $models.PieceReport.createFrom;
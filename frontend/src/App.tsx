@@ -1,7 +1,9 @@
-import { useState, useCallback } from 'react'
+import { useState, useCallback, useEffect } from 'react'
+import { Events } from '@wailsio/runtime'
 import { ConnectionPanel } from './components/ConnectionPanel'
 import { TorrentList } from './components/TorrentList'
 import { MatchingPanel } from './components/MatchingPanel'
+import { AddTorrentPanel } from './components/AddTorrentPanel'
 import { StatusBar } from './components/StatusBar'
 import { Toaster } from './components/ui/sonner'
 
@@ -25,6 +27,7 @@ function App() {
   const [isConnected, setIsConnected] = useState(false)
   const [connectionInfo, setConnectionInfo] = useState<ConnectionInfo | null>(null)
   const [selectedTorrent, setSelectedTorrent] = useState<TorrentInfo | null>(null)
+  const [addTorrentPath, setAddTorrentPath] = useState<string | null>(null)
 
   const handleConnect = useCallback((info: ConnectionInfo) => {
     setConnectionInfo(info)
@@ -35,6 +38,7 @@ function App() {
     setIsConnected(false)
     setConnectionInfo(null)
     setSelectedTorrent(null)
+    setAddTorrentPath(null)
   }, [])
 
   const handleSelectTorrent = useCallback((torrent: TorrentInfo) => {
@@ -43,8 +47,24 @@ function App() {
 
   const handleBack = useCallback(() => {
     setSelectedTorrent(null)
+    setAddTorrentPath(null)
   }, [])
 
+  const handleAddTorrent = useCallback((path: string) => {
+    setSelectedTorrent(null)
+    setAddTorrentPath(path)
+  }, [])
+
+  // .torrent files dropped onto the torrent list open the add panel
+  useEffect(() => {
+    return Events.On('files-dropped', (event) => {
+      const torrentFile = event.data.find(path => path.toLowerCase().endsWith('.torrent'))
+      if (torrentFile && isConnected) {
+        handleAddTorrent(torrentFile)
+      }
+    })
+  }, [isConnected, handleAddTorrent])
+
   return (
     <div className="h-screen flex flex-col bg-background">
       {!isConnected ? (
@@ -52,8 +72,10 @@ function App() {
       ) : (
         <>
           <div className="flex-1 flex flex-col min-h-0 p-4">
-            {!selectedTorrent ? (
-            <TorrentList onSelectTorrent={handleSelectTorrent} />
+            {addTorrentPath ? (
+              <AddTorrentPanel torrentPath={addTorrentPath} onBack={handleBack} />
+            ) : !selectedTorrent ? (
+              <TorrentList onSelectTorrent={handleSelectTorrent} onAddTorrent={handleAddTorrent} />
             ) : (
               <MatchingPanel 
                 torrent={selectedTorrent} 
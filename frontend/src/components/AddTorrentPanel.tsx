@@ -0,0 +1,301 @@
+import { useState, useEffect } from 'react'
+import { Button } from '@/components/ui/button'
+import { Input } from '@/components/ui/input'
+import { Card, CardContent, CardHeader, CardTitle, CardDescription } from '@/components/ui/card'
+import { ScrollArea } from '@/components/ui/scroll-area'
+import { Badge } from '@/components/ui/badge'
+import { Spinner } from '@/components/ui/spinner'
+import { Checkbox } from '@/components/ui/checkbox'
+import {
+  Item,
+  ItemContent,
+  ItemTitle,
+  ItemDescription,
+  ItemActions,
+  ItemGroup,
+} from '@/components/ui/item'
+import { toast } from 'sonner'
+import { Dialogs } from '@wailsio/runtime'
+import { QBitService, MatcherService } from '../../bindings/qbt-file-matcher/backend'
+import type { MatchInfo, TorrentFileInfo, TorrentMetaInfo } from '../../bindings/qbt-file-matcher/backend/models'
+import { formatSize, getErrorMessage } from '@/lib/utils'
+
+interface AddTorrentPanelProps {
+  torrentPath: string
+  onBack: () => void
+}
+
+export function AddTorrentPanel({ torrentPath, onBack }: AddTorrentPanelProps) {
+  const [meta, setMeta] = useState<TorrentMetaInfo | null>(null)
+  const [searchPath, setSearchPath] = useState('')
+  const [matches, setMatches] = useState<MatchInfo[]>([])
+  const [unmatched, setUnmatched] = useState<TorrentFileInfo[]>([])
+  const [hasScanned, setHasScanned] = useState(false)
+  const [isScanning, setIsScanning] = useState(false)
+  const [isAdding, setIsAdding] = useState(false)
+  const [requireSameExtension, setRequireSameExtension] = useState(true)
+  const [skipUnmatched, setSkipUnmatched] = useState(true)
+  const [resume, setResume] = useState(false)
+  const [category, setCategory] = useState('')
+
+  useEffect(() => {
+    MatcherService.ReadTorrent(torrentPath)
+      .then(setMeta)
+      .catch((error) => {
+        toast.error(`Failed to read torrent: ${getErrorMessage(error)}`)
+        onBack()
+      })
+  }, [torrentPath, onBack])
+
+  const handleScan = async () => {
+    if (!meta || !searchPath) {
+      toast.error('Please enter a directory path')
+      return
+    }
+
+    setIsScanning(true)
+    try {
+      const exists = await MatcherService.DirExists(searchPath)
+      if (!exists) {
+        toast.error('Directory not found')
+        return
+      }
+
+      const diskFiles = await MatcherService.ScanDir(searchPath)
+      const result = await MatcherService.FindMatches({
+        torrentFiles: meta.files,
+        diskFiles: diskFiles,
+        requireSameExtension: requireSameExtension,
+      })
+
+      setMatches(result.matches)
+      setUnmatched(result.unmatched)
+      setHasScanned(true)
+      toast.info(`Matched ${result.matchedCount} of ${result.totalFiles} files`)
+    } catch (error) {
+      toast.error(`Scan failed: ${getErrorMessage(error)}`)
+    } finally {
+      setIsScanning(false)
+    }
+  }
+
+  const handleAdd = async () => {
+    if (!meta) return
+
+    const selected = matches.filter(m => m.selected !== null)
+    if (selected.length === 0) {
+      toast.error('No files matched')
+      return
+    }
+
+    setIsAdding(true)
+    try {
+      const renames = await MatcherService.GenRenames({
+        matches: selected,
+        searchPath: searchPath,
+        torrentFiles: meta.files,
+      })
+
+      // Ambiguous matches without a selection can't be seeded either
+      const unwanted = skipUnmatched
+        ? [
+            ...unmatched.map(f => f.name),
+            ...matches.filter(m => m.selected === null).map(m => m.torrentFile.name),
+          ]
+        : []
+
+      const result = await QBitService.AddMatchedTorrent({
+        torrentPath,
+        savePath: searchPath,
+        renames,
+        unwanted,
+        category,
+        tags: [],
+        recheck: true,
+        resume,
+      })
+
+      toast.success(`Added ${meta.name} - renamed ${result.renamedCount} files`)
+      if (result.failedCount > 0) {
+        toast.error(`Failed to rename ${result.failedCount} files`)
+      }
+      if (result.report && result.report.incompleteCount > 0) {
+        toast.warning(`${result.report.incompleteCount} files failed verification - torrent left paused`)
+      } else if (result.resumed) {
+        toast.success('All files verified - torrent resumed')
+      }
+      onBack()
+    } catch (error) {
+      toast.error(`Failed to add torrent: ${getErrorMessage(error)}`)
+    } finally {
+      setIsAdding(false)
+    }
+  }
+
+  const selectedCount = matches.filter(m => m.selected !== null).length
+
+  if (!meta) {
+    return (
+      <Card className="flex-1 flex flex-col items-center justify-center gap-3">
+        <Spinner className="size-6" />
+        <p className="text-sm text-muted-foreground">Reading torrent...</p>
+      </Card>
+    )
+  }
+
+  return (
+    <Card className="flex-1 flex flex-col min-h-0">
+      <CardHeader className="shrink-0">
+        <div className="flex items-start justify-between gap-4">
+          <div className="min-w-0 flex-1">
+            <Button variant="ghost" size="sm" onClick={onBack} className="mb-2 -ml-2">
+              ← Back
+            </Button>
+            <CardTitle className="truncate">Add {meta.name}</CardTitle>
+            <CardDescription>
+              {meta.files.length} files • {formatSize(meta.size)}
+            </CardDescription>
+          </div>
+          {selectedCount > 0 && (
+            <Button onClick={handleAdd} disabled={isAdding || isScanning} className="shrink-0">
+              {isAdding ? (
+                <>
+                  <Spinner className="mr-2" />
+                  {resume ? 'Adding & verifying...' : 'Adding...'}
+                </>
+              ) : (
+                'Add to qBittorrent'
+              )}
+            </Button>
+          )}
+        </div>
+      </CardHeader>
+
+      <CardContent className="flex-1 flex flex-col min-h-0 gap-4">
+        <div className="shrink-0 space-y-3">
+          <div className="flex gap-2">
+            <Input
+              value={searchPath}
+              onChange={(e) => setSearchPath(e.target.value)}
+              placeholder="Directory with the files (becomes the save path)..."
+              className="flex-1"
+            />
+            <Button
+              onClick={async () => {
+                try {
+                  const path = await Dialogs.OpenFile({
+                    CanChooseDirectories: true,
+                    CanChooseFiles: false,
+                    Title: 'Select Directory',
+                  })
+                  if (path) setSearchPath(path as string)
+                } catch {
+                  // User cancelled
+                }
+              }}
+              variant="secondary"
+              disabled={isScanning || isAdding}
+            >
+              Browse
+            </Button>
+            <Button onClick={handleScan} disabled={isScanning || isAdding} variant="secondary">
+              {isScanning ? <Spinner /> : 'Scan'}
+            </Button>
+          </div>
+
+          <div className="flex flex-wrap items-center gap-4">
+            <div className="flex items-center gap-2">
+              <Checkbox
+                id="addRequireExt"
+                checked={requireSameExtension}
+                onCheckedChange={(checked) => setRequireSameExtension(checked === true)}
+              />
+              <label htmlFor="addRequireExt" className="text-sm text-muted-foreground cursor-pointer">
+                Require same file extension
+              </label>
+            </div>
+            <div className="flex items-center gap-2">
+              <Checkbox
+                id="addSkipUnmatched"
+                checked={skipUnmatched}
+                onCheckedChange={(checked) => setSkipUnmatched(checked === true)}
+              />
+              <label htmlFor="addSkipUnmatched" className="text-sm text-muted-foreground cursor-pointer">
+                Don't download unmatched files
+              </label>
+            </div>
+            <div className="flex items-center gap-2">
+              <Checkbox
+                id="addResume"
+                checked={resume}
+                onCheckedChange={(checked) => setResume(checked === true)}
+              />
+              <label htmlFor="addResume" className="text-sm text-muted-foreground cursor-pointer">
+                Resume once verified
+              </label>
+            </div>
+            <Input
+              value={category}
+              onChange={(e) => setCategory(e.target.value)}
+              placeholder="Category"
+              className="h-8 w-40"
+            />
+          </div>
+
+          <p className="text-xs text-muted-foreground">
+            The torrent is added paused with this directory as its save path, renamed to point
+            at the matched files and rechecked.
+          </p>
+        </div>
+
+        <ScrollArea className="flex-1 min-h-0">
+          <ItemGroup>
+            {hasScanned ? (
+              <>
+                {matches.map((match) => (
+                  <Item key={match.torrentFile.index} variant="outline" size="sm" className="mb-2">
+                    <ItemContent>
+                      <ItemTitle className="truncate text-sm">{match.torrentFile.name}</ItemTitle>
+                      <ItemDescription className="truncate">
+                        {match.selected ? `→ ${match.selected.path}` : formatSize(match.torrentFile.size)}
+                      </ItemDescription>
+                    </ItemContent>
+                    <ItemActions>
+                      {match.selected ? (
+                        <Badge className="bg-success text-success-foreground">Matched</Badge>
+                      ) : (
+                        <Badge variant="outline" className="border-warning text-warning">
+                          {match.diskFiles.length} candidates
+                        </Badge>
+                      )}
+                    </ItemActions>
+                  </Item>
+                ))}
+                {unmatched.map((file) => (
+                  <Item key={file.index} variant="outline" size="sm" className="mb-2">
+                    <ItemContent>
+                      <ItemTitle className="truncate text-sm text-muted-foreground">{file.name}</ItemTitle>
+                      <ItemDescription>{formatSize(file.size)}</ItemDescription>
+                    </ItemContent>
+                    <ItemActions>
+                      <Badge variant="destructive">No match</Badge>
+                    </ItemActions>
+                  </Item>
+                ))}
+              </>
+            ) : (
+              meta.files.map((file) => (
+                <Item key={file.index} variant="muted" size="sm" className="mb-1">
+                  <ItemContent>
+                    <ItemTitle className="truncate text-sm">{file.name}</ItemTitle>
+                    <ItemDescription>{formatSize(file.size)}</ItemDescription>
+                  </ItemContent>
+                </Item>
+              ))
+            )}
+          </ItemGroup>
+        </ScrollArea>
+      </CardContent>
+    </Card>
+  )
+}
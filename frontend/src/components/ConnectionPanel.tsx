@@ -3,6 +3,7 @@ import { Button } from '@/components/ui/button'
 import { Input } from '@/components/ui/input'
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from '@/components/ui/card'
 import { Spinner } from '@/components/ui/spinner'
+import { Checkbox } from '@/components/ui/checkbox'
 import { toast } from 'sonner'
 import { QBitService } from '../../bindings/qbt-file-matcher/backend'
 import { getErrorMessage, isValidUrl } from '@/lib/utils'
@@ -17,6 +18,30 @@ export function ConnectionPanel({ onConnect }: ConnectionPanelProps) {
   const [username, setUsername] = useState('admin')
   const [password, setPassword] = useState('')
   const [isConnecting, setIsConnecting] = useState(false)
+  const [showAdvanced, setShowAdvanced] = useState(false)
+  const [insecureSkipVerify, setInsecureSkipVerify] = useState(false)
+  const [caCertFile, setCaCertFile] = useState('')
+  const [clientCertFile, setClientCertFile] = useState('')
+  const [clientKeyFile, setClientKeyFile] = useState('')
+  const [basicUsername, setBasicUsername] = useState('')
+  const [basicPassword, setBasicPassword] = useState('')
+  const [headerLines, setHeaderLines] = useState('')
+
+  // Parses one "Name: value" header per line
+  const parseHeaders = (): Record<string, string> | null => {
+    const headers: Record<string, string> = {}
+    for (const line of headerLines.split('\n')) {
+      if (!line.trim()) continue
+      const colon = line.indexOf(':')
+      const name = colon > 0 ? line.slice(0, colon).trim() : ''
+      if (!name) {
+        toast.error(`Invalid header "${line.trim()}", expected "Name: value"`)
+        return null
+      }
+      headers[name] = line.slice(colon + 1).trim()
+    }
+    return headers
+  }
 
   const handleConnect = async () => {
     if (!url || !username) {
@@ -29,9 +54,23 @@ export function ConnectionPanel({ onConnect }: ConnectionPanelProps) {
       return
     }
 
+    const headers = parseHeaders()
+    if (!headers) return
+
     setIsConnecting(true)
     try {
-      await QBitService.Connect({ url, username, password })
+      await QBitService.Connect({
+        url,
+        username,
+        password,
+        caCertFile,
+        insecureSkipVerify,
+        clientCertFile,
+        clientKeyFile,
+        basicUsername,
+        basicPassword,
+        headers,
+      })
       const version = await QBitService.GetVersion()
       toast.success(`Connected to qBittorrent ${version}`)
       onConnect({ url, username, version })
@@ -81,6 +120,67 @@ export function ConnectionPanel({ onConnect }: ConnectionPanelProps) {
             />
           </div>
 
+          <Button
+            variant="ghost"
+            size="sm"
+            className="-ml-2"
+            onClick={() => setShowAdvanced(!showAdvanced)}
+          >
+            {showAdvanced ? '▾' : '▸'} Advanced (reverse proxy)
+          </Button>
+
+          {showAdvanced && (
+            <div className="space-y-3">
+              <div className="flex items-center gap-2">
+                <Checkbox
+                  id="insecureSkipVerify"
+                  checked={insecureSkipVerify}
+                  onCheckedChange={(checked) => setInsecureSkipVerify(checked === true)}
+                />
+                <label htmlFor="insecureSkipVerify" className="text-sm text-muted-foreground cursor-pointer">
+                  Skip TLS certificate verification
+                </label>
+              </div>
+              <Input
+                value={caCertFile}
+                onChange={(e) => setCaCertFile(e.target.value)}
+                placeholder="CA certificate file (PEM)"
+              />
+              <div className="flex gap-2">
+                <Input
+                  value={clientCertFile}
+                  onChange={(e) => setClientCertFile(e.target.value)}
+                  placeholder="Client certificate (PEM)"
+                />
+                <Input
+                  value={clientKeyFile}
+                  onChange={(e) => setClientKeyFile(e.target.value)}
+                  placeholder="Client key (PEM)"
+                />
+              </div>
+              <div className="flex gap-2">
+                <Input
+                  value={basicUsername}
+                  onChange={(e) => setBasicUsername(e.target.value)}
+                  placeholder="Basic auth user"
+                />
+                <Input
+                  type="password"
+                  value={basicPassword}
+                  onChange={(e) => setBasicPassword(e.target.value)}
+                  placeholder="Basic auth password"
+                />
+              </div>
+              <textarea
+                value={headerLines}
+                onChange={(e) => setHeaderLines(e.target.value)}
+                placeholder={'Extra headers, one per line\nX-Api-Key: secret'}
+                rows={3}
+                className="w-full rounded-md border border-input bg-transparent px-3 py-2 text-sm shadow-xs placeholder:text-muted-foreground focus-visible:outline-none focus-visible:ring-[3px] focus-visible:ring-ring/50"
+              />
+            </div>
+          )}
+
           <Button 
             onClick={handleConnect} 
             className="w-full"
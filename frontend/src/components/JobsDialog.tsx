@@ -0,0 +1,141 @@
+import { useState, useEffect, useCallback } from 'react'
+import { Button } from '@/components/ui/button'
+import { Badge } from '@/components/ui/badge'
+import { Progress } from '@/components/ui/progress'
+import { ScrollArea } from '@/components/ui/scroll-area'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import {
+  Item,
+  ItemContent,
+  ItemTitle,
+  ItemDescription,
+  ItemActions,
+  ItemGroup,
+} from '@/components/ui/item'
+import { toast } from 'sonner'
+import { Events } from '@wailsio/runtime'
+import { JobService } from '../../bindings/qbt-file-matcher/backend'
+import type { Job } from '../../bindings/qbt-file-matcher/backend/models'
+import { getErrorMessage } from '@/lib/utils'
+
+interface JobsDialogProps {
+  open: boolean
+  onOpenChange: (open: boolean) => void
+}
+
+function statusBadge(status: string) {
+  switch (status) {
+    case 'running':
+      return <Badge variant="secondary">Running</Badge>
+    case 'completed':
+      return <Badge className="bg-success text-success-foreground">Completed</Badge>
+    case 'failed':
+      return <Badge variant="destructive">Failed</Badge>
+    default:
+      return <Badge variant="outline" className="capitalize">{status}</Badge>
+  }
+}
+
+export function JobsDialog({ open, onOpenChange }: JobsDialogProps) {
+  const [jobs, setJobs] = useState<Job[]>([])
+  const [expanded, setExpanded] = useState<string | null>(null)
+
+  const loadJobs = useCallback(async () => {
+    try {
+      setJobs(await JobService.ListJobs())
+    } catch (error) {
+      toast.error(`Failed to load jobs: ${getErrorMessage(error)}`)
+    }
+  }, [])
+
+  useEffect(() => {
+    if (!open) return
+    loadJobs()
+    return Events.On('job-updated', (event) => {
+      const updated = event.data
+      setJobs(prev => {
+        const index = prev.findIndex(j => j.id === updated.id)
+        if (index === -1) return [updated, ...prev]
+        const next = [...prev]
+        next[index] = updated
+        return next
+      })
+    })
+  }, [open, loadJobs])
+
+  const handleCancel = async (id: string) => {
+    try {
+      await JobService.CancelJob(id)
+    } catch (error) {
+      toast.error(`Failed to cancel job: ${getErrorMessage(error)}`)
+    }
+  }
+
+  const handleRemove = async (id: string) => {
+    try {
+      await JobService.RemoveJob(id)
+      setJobs(prev => prev.filter(j => j.id !== id))
+    } catch (error) {
+      toast.error(`Failed to remove job: ${getErrorMessage(error)}`)
+    }
+  }
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent className="max-w-2xl">
+        <DialogHeader>
+          <DialogTitle>Background Jobs</DialogTitle>
+          <DialogDescription>
+            Scans and applies keep running, and their results are kept, across reloads
+          </DialogDescription>
+        </DialogHeader>
+        <ScrollArea className="max-h-[60vh]">
+          <ItemGroup>
+            {jobs.length === 0 && (
+              <p className="text-sm text-muted-foreground text-center py-8">No jobs yet</p>
+            )}
+            {jobs.map((job) => (
+              <Item key={job.id} variant="outline" size="sm" className="mb-2 flex-wrap">
+                <ItemContent
+                  className="cursor-pointer"
+                  onClick={() => setExpanded(expanded === job.id ? null : job.id)}
+                >
+                  <ItemTitle className="truncate text-sm">{job.description}</ItemTitle>
+                  <ItemDescription className="truncate">
+                    {job.error || job.message || new Date(job.created).toLocaleString()}
+                  </ItemDescription>
+                  {job.status === 'running' && job.progress >= 0 && (
+                    <Progress value={job.progress * 100} className="h-1 mt-1" />
+                  )}
+                </ItemContent>
+                <ItemActions>
+                  {statusBadge(job.status)}
+                  {job.status === 'running' ? (
+                    <Button variant="ghost" size="sm" onClick={() => handleCancel(job.id)}>
+                      Cancel
+                    </Button>
+                  ) : (
+                    <Button variant="ghost" size="sm" onClick={() => handleRemove(job.id)}>
+                      Remove
+                    </Button>
+                  )}
+                </ItemActions>
+                {expanded === job.id && job.logs.length > 0 && (
+                  <pre className="w-full text-xs text-muted-foreground whitespace-pre-wrap mt-2">
+                    {job.logs.join('\n')}
+                  </pre>
+                )}
+              </Item>
+            ))}
+          </ItemGroup>
+        </ScrollArea>
+      </DialogContent>
+    </Dialog>
+  )
+}
@@ -29,9 +29,11 @@ import {
 } from '@/components/ui/tooltip'
 import { toast } from 'sonner'
 import { Dialogs } from '@wailsio/runtime'
-import { QBitService, MatcherService } from '../../bindings/qbt-file-matcher/backend'
-import type { TorrentFile, DiskFile, MatchInfo } from '../../bindings/qbt-file-matcher/backend/models'
+import { QBitService, MatcherService, JobService, NotifyService } from '../../bindings/qbt-file-matcher/backend'
+import { Notification } from '../../bindings/qbt-file-matcher/backend/models'
+import type { TorrentFile, DiskFile, MatchInfo, RenameOp, RecheckReport, PieceReport } from '../../bindings/qbt-file-matcher/backend/models'
 import { formatSize, getErrorMessage } from '@/lib/utils'
+import { waitForJob } from '@/lib/jobs'
 import type { TorrentInfo } from '../App'
 
 interface MatchingPanelProps {
@@ -44,13 +46,31 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
   const [torrentFiles, setTorrentFiles] = useState<TorrentFile[]>([])
   const [matches, setMatches] = useState<MatchInfo[]>([])
   const [unmatched, setUnmatched] = useState<{ index: number; name: string; size: number }[]>([])
+  const [skippedCount, setSkippedCount] = useState(0)
   const [isLoading, setIsLoading] = useState(true)
   const [isScanning, setIsScanning] = useState(false)
+  const [scanJobId, setScanJobId] = useState<string | null>(null)
+  const [scanMessage, setScanMessage] = useState('')
   const [isApplying, setIsApplying] = useState(false)
   const [isSkipping, setIsSkipping] = useState(false)
   const [isRechecking, setIsRechecking] = useState(false)
   const [showRecheckButton, setShowRecheckButton] = useState(false)
+  const [appliedRenames, setAppliedRenames] = useState<RenameOp[]>([])
+  const [recheckReport, setRecheckReport] = useState<RecheckReport | null>(null)
+  const [revertOps, setRevertOps] = useState<RenameOp[]>([])
+  const [isReverting, setIsReverting] = useState(false)
   const [requireSameExtension, setRequireSameExtension] = useState(true)
+  const [sizeToleranceKB, setSizeToleranceKB] = useState('')
+  const [partialFiles, setPartialFiles] = useState(false)
+  const [fingerprints, setFingerprints] = useState(false)
+  const [pendingRenamesCount, setPendingRenamesCount] = useState(0)
+  const [ruleProfiles, setRuleProfiles] = useState<string[]>([])
+  const [rulesProfile, setRulesProfile] = useState('')
+  const [pieceReport, setPieceReport] = useState<PieceReport | null>(null)
+  const [isVerifyingPieces, setIsVerifyingPieces] = useState(false)
+  const [tagOutcome, setTagOutcome] = useState(false)
+  const [category, setCategory] = useState('')
+  const [resumeWhenMatched, setResumeWhenMatched] = useState(false)
   const [selectDialogOpen, setSelectDialogOpen] = useState(false)
   const [currentMatchIndex, setCurrentMatchIndex] = useState<number | null>(null)
 
@@ -70,6 +90,22 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
     loadTorrentFiles()
   }, [loadTorrentFiles])
 
+  useEffect(() => {
+    MatcherService.ListRuleProfiles()
+      .then(setRuleProfiles)
+      .catch((error) => toast.error(`Failed to load rename rules: ${getErrorMessage(error)}`))
+  }, [])
+
+  // Count how many selected matches would actually result in a rename; the
+  // backend compares paths the way this platform's file systems do
+  useEffect(() => {
+    let current = true
+    MatcherService.CountPendingRenames(matches, searchPath)
+      .then((count) => current && setPendingRenamesCount(count))
+      .catch(() => current && setPendingRenamesCount(0))
+    return () => { current = false }
+  }, [matches, searchPath])
+
   const handleScan = async () => {
     if (!searchPath) {
       toast.error('Please enter a directory path')
@@ -77,6 +113,7 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
     }
 
     setIsScanning(true)
+    setScanMessage('')
     try {
       const exists = await MatcherService.DirExists(searchPath)
       if (!exists) {
@@ -85,7 +122,19 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
         return
       }
 
-      const diskFiles = await MatcherService.ScanDir(searchPath)
+      // Large directories can take a while, so scan as a cancellable job
+      const started = await JobService.StartScan(searchPath)
+      setScanJobId(started.id)
+      const job = await waitForJob(started.id, (update) => setScanMessage(update.message))
+      if (job.status === 'cancelled') {
+        toast.info('Scan cancelled')
+        return
+      }
+      if (job.status !== 'completed') {
+        throw new Error(job.error || `scan ${job.status}`)
+      }
+
+      const diskFiles = await JobService.GetScanResult(started.id)
       toast.info(`Found ${diskFiles.length} files on disk`)
 
       const torrentFileInfos = torrentFiles.map(f => ({
@@ -98,20 +147,40 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
         torrentFiles: torrentFileInfos,
         diskFiles: diskFiles,
         requireSameExtension: requireSameExtension,
+        sizeTolerance: Math.max(0, Number(sizeToleranceKB) || 0) * 1024,
+        partialFiles: partialFiles,
+        fingerprints: fingerprints,
+        rulesProfile: rulesProfile,
       })
 
+      setPieceReport(null)
       setMatches(result.matches)
       setUnmatched(result.unmatched)
+      setSkippedCount(result.skipped.length)
 
       if (result.matchedCount > 0) {
         toast.success(`Matched ${result.matchedCount} of ${result.totalFiles} files`)
       } else {
         toast.warning('No automatic matches found')
       }
+      if (result.ruleErrors?.length) {
+        toast.warning(`Rules failed for ${result.ruleErrors.length} files: ${result.ruleErrors[0]}`)
+      }
     } catch (error) {
       toast.error(`Scan failed: ${getErrorMessage(error)}`)
     } finally {
       setIsScanning(false)
+      setScanJobId(null)
+      setScanMessage('')
+    }
+  }
+
+  const handleCancelScan = async () => {
+    if (!scanJobId) return
+    try {
+      await JobService.CancelJob(scanJobId)
+    } catch (error) {
+      toast.error(`Failed to cancel scan: ${getErrorMessage(error)}`)
     }
   }
 
@@ -129,13 +198,27 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
         ...updated[currentMatchIndex],
         selected: diskFile,
         autoMatched: false,
+        exact: false,
       }
       return updated
     })
+    setPieceReport(null)
     setSelectDialogOpen(false)
     setCurrentMatchIndex(null)
   }
 
+  const handleVerifyPieces = async () => {
+    setIsVerifyingPieces(true)
+    try {
+      const report = await QBitService.VerifyMatchPieces({ hash: torrent.hash, matches })
+      setPieceReport(report)
+    } catch (error) {
+      toast.error(`Piece verification failed: ${getErrorMessage(error)}`)
+    } finally {
+      setIsVerifyingPieces(false)
+    }
+  }
+
   const handleClearSelection = (matchIndex: number, e: React.MouseEvent) => {
     e.stopPropagation()
     setMatches(prev => {
@@ -144,6 +227,7 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
         ...updated[matchIndex],
         selected: null,
         autoMatched: false,
+        exact: false,
       }
       return updated
     })
@@ -159,34 +243,53 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
     setIsApplying(true)
     let successCount = 0
     let errorCount = 0
+    const applied: RenameOp[] = []
 
     try {
+      // Classify what is applied: files left without a selection stay unmatched
+      const unselected = matches.filter(m => m.selected === null).map(m => m.torrentFile)
+      const outcome = await MatcherService.ClassifyOutcome(matchesWithSelection, [...unmatched, ...unselected])
       const renames = await MatcherService.GenRenames({
         matches: matchesWithSelection,
         searchPath: searchPath,
+        torrentFiles: torrentFiles.map(f => ({
+          index: f.index,
+          name: f.name,
+          size: f.size,
+        })),
       })
 
-      for (const rename of renames) {
-        try {
-          await QBitService.RenameFile(torrent.hash, rename.oldPath, rename.newPath)
-          successCount++
-        } catch (error) {
-          errorCount++
-          console.error(`Failed to rename ${rename.oldPath}:`, error)
-        }
+      // Applied renames are journaled, so they can be reverted after a restart
+      const result = await QBitService.ApplyRenames(torrent.hash, renames)
+      result.errors.forEach(error => console.error(error))
+      successCount = result.renamedCount
+      errorCount = result.failedCount
+      applied.push(...result.applied)
+
+      const finalOutcome = errorCount > 0 ? 'needs-review' : outcome
+      if (successCount > 0) {
+        notify('applied', { outcome: finalOutcome, renamedCount: successCount, failedCount: errorCount })
       }
 
       if (successCount > 0) {
-        toast.success(`Renamed ${successCount} files successfully`)
+        toast.success(`Renamed ${successCount} files successfully`, {
+          description: result.journalId ? `Undo with: qbt-file-matcher moves undo ${result.journalId}` : undefined,
+        })
+        setAppliedRenames(applied)
+        setRecheckReport(null)
+        setRevertOps([])
         setShowRecheckButton(true)
       }
       if (errorCount > 0) {
         toast.error(`Failed to rename ${errorCount} files`)
       }
 
+      await reportOutcome(finalOutcome)
+
       await loadTorrentFiles()
       setMatches([])
       setUnmatched([])
+      setSkippedCount(0)
     } catch (error) {
       toast.error(`Apply failed: ${getErrorMessage(error)}`)
     } finally {
@@ -194,6 +297,32 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
     }
   }
 
+  // Notifies about torrents needing review and tags, categorizes or resumes
+  // the torrent as configured for its outcome
+  const reportOutcome = async (outcome: string) => {
+    if (outcome === 'needs-review') {
+      notify('needs-review', { outcome })
+    }
+    if (tagOutcome || category || resumeWhenMatched) {
+      try {
+        await QBitService.PostProcess(torrent.hash, outcome, {
+          tagOutcome,
+          tags: [],
+          category,
+          resume: resumeWhenMatched,
+        })
+      } catch (error) {
+        toast.error(`Failed to update torrent: ${getErrorMessage(error)}`)
+      }
+    }
+  }
+
+  // Desktop (and configured) notifications; failures shouldn't interrupt the user
+  const notify = (kind: string, fields: Partial<Notification> = {}) => {
+    NotifyService.Notify(new Notification({ kind, hash: torrent.hash, name: torrent.name, ...fields }))
+      .catch((error) => console.error('Notification failed:', error))
+  }
+
   const handleSkipUnmatched = async () => {
     if (unmatched.length === 0) {
       toast.error('No unmatched files to skip')
@@ -223,38 +352,64 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
     setIsRechecking(true)
     try {
       await QBitService.RecheckTorrent(torrent.hash)
-      toast.success('Recheck started - qBittorrent will verify file integrity')
+      toast.info('Recheck started - waiting for qBittorrent to verify files')
       setShowRecheckButton(false)
+
+      const report = await QBitService.WaitForRecheck(torrent.hash, 0)
+      setRecheckReport(report)
+      await loadTorrentFiles()
+
+      if (report.timedOut) {
+        toast.warning('Timed out waiting for recheck, results may be incomplete')
+      }
+      if (report.timedOut || report.incompleteCount > 0) {
+        notify('recheck-failed', {
+          message: `${report.completeCount} files complete, ${report.incompleteCount} incomplete`,
+        })
+      }
+      if (report.incompleteCount === 0) {
+        toast.success(`All ${report.completeCount} files verified`)
+        return
+      }
+
+      const reverts = await MatcherService.GenReverts({ renames: appliedRenames, report })
+      setRevertOps(reverts)
+      await reportOutcome('needs-review')
+      toast.warning(`${report.incompleteCount} file${report.incompleteCount !== 1 ? 's' : ''} failed verification`)
     } catch (error) {
-      toast.error(`Failed to start recheck: ${getErrorMessage(error)}`)
+      toast.error(`Recheck failed: ${getErrorMessage(error)}`)
     } finally {
       setIsRechecking(false)
     }
   }
 
+  const handleRevertFailed = async () => {
+    setIsReverting(true)
+    try {
+      const result = await QBitService.ApplyRenames(torrent.hash, revertOps)
+      result.errors.forEach(error => console.error(error))
+      const revertedCount = result.renamedCount
+      if (revertedCount > 0) {
+        toast.success(`Reverted ${revertedCount} rename${revertedCount !== 1 ? 's' : ''}`)
+      }
+      if (revertedCount < revertOps.length) {
+        toast.error(`Failed to revert ${revertOps.length - revertedCount} renames`)
+      }
+      setRevertOps([])
+      setRecheckReport(null)
+      await loadTorrentFiles()
+    } catch (error) {
+      toast.error(`Revert failed: ${getErrorMessage(error)}`)
+    } finally {
+      setIsReverting(false)
+    }
+  }
+
+  const failedFiles = recheckReport?.files.filter(f => !f.complete && !f.skipped) ?? []
+  const revertPaths = new Set(revertOps.map(op => op.oldPath))
+
   const selectedCount = matches.filter(m => m.selected !== null).length
   const hasResults = matches.length > 0 || unmatched.length > 0
-  
-  // Count how many selected matches would actually result in a rename
-  // (i.e., the relative path of the disk file differs from the torrent file name)
-  const pendingRenamesCount = matches.filter(m => {
-    if (!m.selected) return false
-    // Compute relative path from searchPath to disk file
-    // The disk file path starts with searchPath, so we need to extract the relative part
-    const diskPath = m.selected.path
-    const searchPathNormalized = searchPath.replace(/\\/g, '/')
-    const diskPathNormalized = diskPath.replace(/\\/g, '/')
-    
-    // Get relative path
-    let relativePath = diskPathNormalized
-    if (diskPathNormalized.toLowerCase().startsWith(searchPathNormalized.toLowerCase())) {
-      relativePath = diskPathNormalized.slice(searchPathNormalized.length)
-      if (relativePath.startsWith('/')) relativePath = relativePath.slice(1)
-    }
-    
-    // Compare with torrent file name
-    return relativePath !== m.torrentFile.name
-  }).length
 
   return (
     <>
@@ -283,7 +438,7 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
                         {isRechecking ? (
                           <>
                             <Spinner className="mr-2" />
-                            Rechecking...
+                            Verifying...
                           </>
                         ) : (
                           'Recheck Torrent'
@@ -321,6 +476,27 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
                   </Tooltip>
                 </TooltipProvider>
               )}
+              {selectedCount > 0 && (
+                <TooltipProvider disableHoverableContent>
+                  <Tooltip>
+                    <TooltipTrigger asChild>
+                      <Button onClick={handleVerifyPieces} disabled={isVerifyingPieces || isApplying} variant="outline">
+                        {isVerifyingPieces ? (
+                          <>
+                            <Spinner className="mr-2" />
+                            Verifying...
+                          </>
+                        ) : (
+                          'Verify Pieces'
+                        )}
+                      </Button>
+                    </TooltipTrigger>
+                    <TooltipContent>
+                      <p>Hash the selected files and count the pieces that would pass a recheck</p>
+                    </TooltipContent>
+                  </Tooltip>
+                </TooltipProvider>
+              )}
               {pendingRenamesCount > 0 && (
                 <Button onClick={handleApplyRenames} disabled={isApplying || isSkipping}>
                   {isApplying ? (
@@ -368,20 +544,103 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
               >
                 Browse
               </Button>
-              <Button onClick={handleScan} disabled={isScanning || isLoading} variant="secondary">
-                {isScanning ? <Spinner /> : 'Scan'}
-              </Button>
+              {isScanning && scanJobId ? (
+                <Button onClick={handleCancelScan} variant="secondary">
+                  <Spinner className="mr-2" />
+                  Cancel
+                </Button>
+              ) : (
+                <Button onClick={handleScan} disabled={isScanning || isLoading} variant="secondary">
+                  {isScanning ? <Spinner /> : 'Scan'}
+                </Button>
+              )}
             </div>
 
-            <div className="flex items-center gap-2">
-              <Checkbox
-                id="requireExt"
-                checked={requireSameExtension}
-                onCheckedChange={(checked) => setRequireSameExtension(checked === true)}
+            {isScanning && scanMessage && (
+              <p className="text-xs text-muted-foreground">{scanMessage}</p>
+            )}
+
+            <div className="flex flex-wrap items-center gap-4">
+              <div className="flex items-center gap-2">
+                <Checkbox
+                  id="requireExt"
+                  checked={requireSameExtension}
+                  onCheckedChange={(checked) => setRequireSameExtension(checked === true)}
+                />
+                <label htmlFor="requireExt" className="text-sm text-muted-foreground cursor-pointer">
+                  Require same file extension
+                </label>
+              </div>
+              <Input
+                value={sizeToleranceKB}
+                onChange={(e) => setSizeToleranceKB(e.target.value)}
+                placeholder="Size tolerance (KB)"
+                type="number"
+                min={0}
+                className="h-8 w-44"
+              />
+              <div className="flex items-center gap-2">
+                <Checkbox
+                  id="partialFiles"
+                  checked={partialFiles}
+                  onCheckedChange={(checked) => setPartialFiles(checked === true)}
+                />
+                <label htmlFor="partialFiles" className="text-sm text-muted-foreground cursor-pointer">
+                  Offer partial downloads
+                </label>
+              </div>
+              <div className="flex items-center gap-2">
+                <Checkbox
+                  id="fingerprints"
+                  checked={fingerprints}
+                  onCheckedChange={(checked) => setFingerprints(checked === true)}
+                />
+                <label htmlFor="fingerprints" className="text-sm text-muted-foreground cursor-pointer">
+                  Collapse identical copies
+                </label>
+              </div>
+              {ruleProfiles.length > 0 && (
+                <select
+                  value={rulesProfile}
+                  onChange={(e) => setRulesProfile(e.target.value)}
+                  className="h-8 rounded-md border border-input bg-background px-2 text-sm text-muted-foreground"
+                  title="Rename rules tried before size matching"
+                >
+                  <option value="">No rename rules</option>
+                  {ruleProfiles.map(name => (
+                    <option key={name} value={name}>Rules: {name}</option>
+                  ))}
+                </select>
+              )}
+            </div>
+
+            <div className="flex flex-wrap items-center gap-4">
+              <div className="flex items-center gap-2">
+                <Checkbox
+                  id="tagOutcome"
+                  checked={tagOutcome}
+                  onCheckedChange={(checked) => setTagOutcome(checked === true)}
+                />
+                <label htmlFor="tagOutcome" className="text-sm text-muted-foreground cursor-pointer">
+                  Tag with match outcome
+                </label>
+              </div>
+              <div className="flex items-center gap-2">
+                <Checkbox
+                  id="resumeWhenMatched"
+                  checked={resumeWhenMatched}
+                  onCheckedChange={(checked) => setResumeWhenMatched(checked === true)}
+                />
+                <label htmlFor="resumeWhenMatched" className="text-sm text-muted-foreground cursor-pointer">
+                  Resume when fully matched
+                </label>
+              </div>
+              <Input
+                value={category}
+                onChange={(e) => setCategory(e.target.value)}
+                placeholder="Category when fully matched"
+                className="h-8 w-56"
               />
-              <label htmlFor="requireExt" className="text-sm text-muted-foreground cursor-pointer">
-                Require same file extension
-              </label>
             </div>
 
             <p className="text-xs text-muted-foreground">
@@ -390,6 +649,39 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
             </p>
           </div>
 
+          {/* Recheck results */}
+          {failedFiles.length > 0 && (
+            <div className="shrink-0 rounded-md border border-destructive/50 bg-destructive-muted/30 p-3 space-y-2">
+              <div className="flex items-center justify-between gap-2">
+                <p className="text-sm font-medium">
+                  {failedFiles.length} file{failedFiles.length !== 1 ? 's' : ''} failed verification
+                </p>
+                {revertOps.length > 0 && (
+                  <Button size="sm" variant="outline" onClick={handleRevertFailed} disabled={isReverting}>
+                    {isReverting ? <Spinner /> : `Revert ${revertOps.length} Rename${revertOps.length !== 1 ? 's' : ''}`}
+                  </Button>
+                )}
+              </div>
+              <ScrollArea className="max-h-32">
+                {failedFiles.map((file) => (
+                  <p key={file.index} className="text-xs text-muted-foreground truncate">
+                    {file.name} ({Math.round(file.progress * 100)}%)
+                    {revertPaths.has(file.name) && ' - likely wrong match'}
+                  </p>
+                ))}
+              </ScrollArea>
+            </div>
+          )}
+
+          {/* Piece verification */}
+          {pieceReport && (
+            <p className="shrink-0 text-sm text-muted-foreground">
+              {pieceReport.passed} of {pieceReport.totalPieces} pieces would pass
+              {pieceReport.failed > 0 && `, ${pieceReport.failed} fail`}
+              {pieceReport.unchecked > 0 && `, ${pieceReport.unchecked} not checked (no file selected)`}
+            </p>
+          )}
+
           {/* Content area */}
           {isLoading ? (
             <div className="flex-1 flex flex-col items-center justify-center gap-3">
@@ -429,6 +721,7 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
                 <p className="text-sm font-medium">Match Results</p>
                 <p className="text-xs text-muted-foreground">
                   {selectedCount} of {matches.length + unmatched.length} matched
+                  {skippedCount > 0 && ` · ${skippedCount} pad/empty skipped`}
                 </p>
               </div>
 
@@ -452,11 +745,31 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
                         <ItemDescription>
                           {formatSize(match.torrentFile.size)}
                           {match.selected && (
-                            <span className="block mt-1 truncate">→ {match.selected.name}</span>
+                            <span className="block mt-1 truncate">
+                              → {match.selected.name}
+                              {(match.selected.copies?.length ?? 0) > 0 && ` (+${match.selected.copies?.length} identical)`}
+                            </span>
                           )}
+                          {pieceReport && match.selected && (() => {
+                            const filePieces = pieceReport.files.find(f => f.index === match.torrentFile.index)
+                            return filePieces && (
+                              <span className="block">
+                                {filePieces.passed} of {filePieces.pieces} pieces {match.partial ? 'salvaged' : 'pass'}
+                              </span>
+                            )
+                          })()}
                         </ItemDescription>
                       </ItemContent>
                       <ItemActions>
+                        {match.sizeMismatch && (
+                          <Badge variant="outline" className="border-warning text-warning">Size mismatch</Badge>
+                        )}
+                        {match.partial && (
+                          <Badge variant="outline" className="border-warning text-warning">Partial</Badge>
+                        )}
+                        {match.rule && (
+                          <Badge variant="outline" title={`Mapped by rename rule "${match.rule}"`}>Rule</Badge>
+                        )}
                         {match.selected ? (
                           <>
                             <Badge className="bg-success text-success-foreground">Matched</Badge>
@@ -511,7 +824,7 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
             <DialogTitle>Select Matching File</DialogTitle>
           </DialogHeader>
           <p className="text-sm text-muted-foreground">
-            Choose the file on disk that matches this torrent file
+            Choose the file on disk that matches this torrent file. Candidates are sorted by name similarity.
           </p>
           <ScrollArea className="max-h-[350px]">
             <ItemGroup>
@@ -524,9 +837,27 @@ export function MatchingPanel({ torrent, onBack }: MatchingPanelProps) {
                   onClick={() => handleChooseFile(file)}
                 >
                   <ItemContent>
-                    <ItemTitle className="truncate text-sm">{file.name}</ItemTitle>
+                    <ItemTitle className="truncate text-sm">
+                      {file.name}
+                      {i === 0 && matches[currentMatchIndex].score > 0 && (
+                        <Badge variant="secondary" className="ml-2">
+                          {Math.round(matches[currentMatchIndex].score * 100)}% similar
+                        </Badge>
+                      )}
+                    </ItemTitle>
                     <ItemDescription className="truncate">{file.path}</ItemDescription>
-                    <ItemDescription>{formatSize(file.size)}</ItemDescription>
+                    {(file.copies?.length ?? 0) > 0 && (
+                      <ItemDescription className="truncate">
+                        Identical to {file.copies?.join(', ')}
+                      </ItemDescription>
+                    )}
+                    <ItemDescription>
+                      {formatSize(file.size)}
+                      {matches[currentMatchIndex].sizeMismatch &&
+                        ` (${file.size >= matches[currentMatchIndex].torrentFile.size ? '+' : ''}${file.size - matches[currentMatchIndex].torrentFile.size} bytes, size mismatch)`}
+                      {matches[currentMatchIndex].partial &&
+                        ` (${Math.round((file.size / matches[currentMatchIndex].torrentFile.size) * 100)}% downloaded)`}
+                    </ItemDescription>
                   </ItemContent>
                 </Item>
               ))}
@@ -0,0 +1,245 @@
+import { useState } from 'react'
+import { Button } from '@/components/ui/button'
+import { Badge } from '@/components/ui/badge'
+import { Input } from '@/components/ui/input'
+import { ScrollArea } from '@/components/ui/scroll-area'
+import { Spinner } from '@/components/ui/spinner'
+import {
+  Dialog,
+  DialogContent,
+  DialogDescription,
+  DialogHeader,
+  DialogTitle,
+} from '@/components/ui/dialog'
+import {
+  Item,
+  ItemContent,
+  ItemTitle,
+  ItemDescription,
+  ItemActions,
+  ItemGroup,
+} from '@/components/ui/item'
+import { toast } from 'sonner'
+import { Dialogs } from '@wailsio/runtime'
+import { JobService, MatcherService } from '../../bindings/qbt-file-matcher/backend'
+import type { OrphanReport } from '../../bindings/qbt-file-matcher/backend/models'
+import { waitForJob } from '@/lib/jobs'
+import { formatSize, getErrorMessage } from '@/lib/utils'
+
+interface OrphansDialogProps {
+  open: boolean
+  onOpenChange: (open: boolean) => void
+}
+
+async function browseDirectory(title: string): Promise<string | null> {
+  try {
+    const path = await Dialogs.OpenFile({
+      CanChooseDirectories: true,
+      CanChooseFiles: false,
+      Title: title,
+    })
+    return path ? (path as string) : null
+  } catch {
+    // User cancelled
+    return null
+  }
+}
+
+export function OrphansDialog({ open, onOpenChange }: OrphansDialogProps) {
+  const [roots, setRoots] = useState<string[]>([])
+  const [rootInput, setRootInput] = useState('')
+  const [trash, setTrash] = useState('')
+  const [report, setReport] = useState<OrphanReport | null>(null)
+  const [expanded, setExpanded] = useState<string | null>(null)
+  const [busy, setBusy] = useState<string | null>(null)
+
+  const addRoot = (root: string) => {
+    const trimmed = root.trim()
+    if (trimmed && !roots.includes(trimmed)) setRoots([...roots, trimmed])
+    setRootInput('')
+  }
+
+  const handleFind = async () => {
+    setBusy('Comparing files with torrents...')
+    setReport(null)
+    try {
+      const started = await JobService.StartOrphans({ roots, trash })
+      const job = await waitForJob(started.id, (update) => update.message && setBusy(update.message))
+      if (job.status !== 'completed') {
+        throw new Error(job.error || `search ${job.status}`)
+      }
+      const result = await JobService.GetOrphanResult(started.id)
+      setReport(result)
+      toast.info(`Found ${result.fileCount} orphans (${formatSize(result.totalSize)})`)
+    } catch (error) {
+      toast.error(`Failed to find orphans: ${getErrorMessage(error)}`)
+    } finally {
+      setBusy(null)
+    }
+  }
+
+  const handleExport = async () => {
+    if (!report) return
+    try {
+      const path = await Dialogs.SaveFile({
+        Title: 'Export Orphans',
+        Filename: 'orphans.csv',
+        Filters: [
+          { DisplayName: 'CSV', Pattern: '*.csv' },
+          { DisplayName: 'JSON', Pattern: '*.json' },
+        ],
+      })
+      if (!path) return
+      await MatcherService.ExportOrphans(report, path as string)
+      toast.success(`Report saved to ${path}`)
+    } catch (error) {
+      toast.error(`Failed to export report: ${getErrorMessage(error)}`)
+    }
+  }
+
+  const handleTrash = async () => {
+    if (!report || !trash) return
+    setBusy(`Moving ${report.fileCount} files to ${trash}...`)
+    try {
+      const files = report.dirs.flatMap(d => d.files)
+      const started = await JobService.StartTrashOrphans({ files, roots: report.roots, trash })
+      const job = await waitForJob(started.id)
+      if (job.status !== 'completed') {
+        throw new Error(job.error || `move ${job.status}`)
+      }
+      const result = await JobService.GetMoveResult(started.id)
+      const skipped = result.skippedCount + result.failedCount
+      toast.success(`Moved ${result.movedCount} files to the trash${skipped ? `, ${skipped} not moved` : ''}`, {
+        description: result.journalId ? `Undo with: qbt-file-matcher moves undo ${result.journalId}` : undefined,
+      })
+      setReport(null)
+    } catch (error) {
+      toast.error(`Failed to move orphans: ${getErrorMessage(error)}`)
+    } finally {
+      setBusy(null)
+    }
+  }
+
+  return (
+    <Dialog open={open} onOpenChange={onOpenChange}>
+      <DialogContent className="max-w-3xl">
+        <DialogHeader>
+          <DialogTitle>Orphan Files</DialogTitle>
+          <DialogDescription>
+            Files under these folders that no torrent in qBittorrent points at
+          </DialogDescription>
+        </DialogHeader>
+
+        <div className="space-y-2">
+          <div className="flex gap-2">
+            <Input
+              value={rootInput}
+              onChange={(e) => setRootInput(e.target.value)}
+              onKeyDown={(e) => e.key === 'Enter' && addRoot(rootInput)}
+              placeholder="Folder to search..."
+              className="flex-1"
+            />
+            <Button
+              variant="secondary"
+              onClick={async () => {
+                const path = await browseDirectory('Select Folder to Search')
+                if (path) addRoot(path)
+              }}
+            >
+              Browse
+            </Button>
+            <Button variant="secondary" onClick={() => addRoot(rootInput)} disabled={!rootInput.trim()}>
+              Add
+            </Button>
+          </div>
+          {roots.length > 0 && (
+            <div className="flex flex-wrap gap-1">
+              {roots.map(root => (
+                <Badge key={root} variant="outline" className="cursor-pointer" onClick={() => setRoots(roots.filter(r => r !== root))}>
+                  {root} ×
+                </Badge>
+              ))}
+            </div>
+          )}
+          <div className="flex gap-2">
+            <Input
+              value={trash}
+              onChange={(e) => setTrash(e.target.value)}
+              placeholder="Trash folder (optional)..."
+              className="flex-1"
+            />
+            <Button
+              variant="secondary"
+              onClick={async () => {
+                const path = await browseDirectory('Select Trash Folder')
+                if (path) setTrash(path)
+              }}
+            >
+              Browse
+            </Button>
+            <Button onClick={handleFind} disabled={roots.length === 0 || busy !== null}>
+              {busy ? <Spinner /> : 'Find Orphans'}
+            </Button>
+          </div>
+        </div>
+
+        {busy && <p className="text-sm text-muted-foreground">{busy}</p>}
+
+        {report && (
+          <>
+            <div className="flex items-center justify-between text-sm">
+              <span className="text-muted-foreground">
+                {report.fileCount} orphans in {report.dirs.length} folders • {formatSize(report.totalSize)} • {report.referenced} files belong to torrents
+              </span>
+              <div className="flex gap-2">
+                <Button variant="outline" size="sm" onClick={handleExport} disabled={report.fileCount === 0}>
+                  Export
+                </Button>
+                <Button
+                  variant="destructive"
+                  size="sm"
+                  onClick={handleTrash}
+                  disabled={!trash || report.fileCount === 0 || busy !== null}
+                  title={trash ? undefined : 'Choose a trash folder first'}
+                >
+                  Move to Trash
+                </Button>
+              </div>
+            </div>
+            <ScrollArea className="max-h-[50vh]">
+              <ItemGroup>
+                {report.dirs.length === 0 && (
+                  <p className="text-sm text-muted-foreground text-center py-8">No orphans</p>
+                )}
+                {report.dirs.map((dir) => (
+                  <Item key={dir.dir} variant="outline" size="sm" className="mb-2 flex-wrap">
+                    <ItemContent
+                      className="cursor-pointer"
+                      onClick={() => setExpanded(expanded === dir.dir ? null : dir.dir)}
+                    >
+                      <ItemTitle className="truncate text-sm">{dir.dir}</ItemTitle>
+                      <ItemDescription>{dir.files.length} files</ItemDescription>
+                    </ItemContent>
+                    <ItemActions>
+                      <Badge variant="secondary">{formatSize(dir.size)}</Badge>
+                    </ItemActions>
+                    {expanded === dir.dir && (
+                      <ul className="w-full text-xs text-muted-foreground mt-2 space-y-0.5">
+                        {dir.files.map(f => (
+                          <li key={f.path} className="flex justify-between gap-2">
+                            <span className="truncate">{f.name}</span>
+                            <span className="shrink-0">{formatSize(f.size)}</span>
+                          </li>
+                        ))}
+                      </ul>
+                    )}
+                  </Item>
+                ))}
+              </ItemGroup>
+            </ScrollArea>
+          </>
+        )}
+      </DialogContent>
+    </Dialog>
+  )
+}
@@ -1,4 +1,7 @@
+import { useState } from 'react'
 import { Button } from '@/components/ui/button'
+import { JobsDialog } from './JobsDialog'
+import { OrphansDialog } from './OrphansDialog'
 import { QBitService } from '../../bindings/qbt-file-matcher/backend'
 import type { ConnectionInfo } from '../App'
 
@@ -8,6 +11,9 @@ interface StatusBarProps {
 }
 
 export function StatusBar({ connectionInfo, onDisconnect }: StatusBarProps) {
+  const [jobsOpen, setJobsOpen] = useState(false)
+  const [orphansOpen, setOrphansOpen] = useState(false)
+
   const handleDisconnect = async () => {
     try {
       await QBitService.Disconnect()
@@ -38,14 +44,34 @@ export function StatusBar({ connectionInfo, onDisconnect }: StatusBarProps) {
         <span className="text-muted-foreground/60">•</span>
         <span className="text-muted-foreground/80">qBittorrent {connectionInfo.version}</span>
       </div>
-      <Button 
-        variant="ghost" 
-        size="sm" 
-        onClick={handleDisconnect}
-        className="h-7 text-xs text-muted-foreground hover:text-foreground"
-      >
-        Disconnect
-      </Button>
+      <div className="flex items-center gap-1">
+        <Button
+          variant="ghost"
+          size="sm"
+          onClick={() => setOrphansOpen(true)}
+          className="h-7 text-xs text-muted-foreground hover:text-foreground"
+        >
+          Orphans
+        </Button>
+        <Button
+          variant="ghost"
+          size="sm"
+          onClick={() => setJobsOpen(true)}
+          className="h-7 text-xs text-muted-foreground hover:text-foreground"
+        >
+          Jobs
+        </Button>
+        <Button 
+          variant="ghost" 
+          size="sm" 
+          onClick={handleDisconnect}
+          className="h-7 text-xs text-muted-foreground hover:text-foreground"
+        >
+          Disconnect
+        </Button>
+      </div>
+      <JobsDialog open={jobsOpen} onOpenChange={setJobsOpen} />
+      <OrphansDialog open={orphansOpen} onOpenChange={setOrphansOpen} />
     </div>
   )
 }
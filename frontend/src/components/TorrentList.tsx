@@ -16,11 +16,13 @@ import { Spinner } from '@/components/ui/spinner'
 import { formatSize, getErrorMessage } from '@/lib/utils'
 import { useCallback, useEffect, useState } from 'react'
 import { toast } from 'sonner'
+import { Dialogs } from '@wailsio/runtime'
 import { QBitService } from '../../bindings/qbt-file-matcher/backend'
 import type { TorrentInfo } from '../App'
 
 interface TorrentListProps {
   onSelectTorrent: (torrent: TorrentInfo) => void
+  onAddTorrent: (path: string) => void
 }
 
 function getStateBadge(state: string): { label: string; variant: 'default' | 'secondary' | 'destructive' | 'outline' } {
@@ -43,7 +45,7 @@ function getStateBadge(state: string): { label: string; variant: 'default' | 'se
   return states[state] || { label: state, variant: 'secondary' }
 }
 
-export function TorrentList({ onSelectTorrent }: TorrentListProps) {
+export function TorrentList({ onSelectTorrent, onAddTorrent }: TorrentListProps) {
   const [torrents, setTorrents] = useState<TorrentInfo[]>([])
   const [filteredTorrents, setFilteredTorrents] = useState<TorrentInfo[]>([])
   const [searchQuery, setSearchQuery] = useState('')
@@ -77,19 +79,37 @@ export function TorrentList({ onSelectTorrent }: TorrentListProps) {
     }
   }, [searchQuery, torrents])
 
+  const handleBrowseTorrent = async () => {
+    try {
+      const path = await Dialogs.OpenFile({
+        CanChooseFiles: true,
+        Title: 'Select .torrent File',
+        Filters: [{ DisplayName: 'Torrent files', Pattern: '*.torrent' }],
+      })
+      if (path) onAddTorrent(path as string)
+    } catch {
+      // User cancelled
+    }
+  }
+
   return (
-    <Card className="flex-1 flex flex-col min-h-0">
+    <Card className="flex-1 flex flex-col min-h-0" data-file-drop-target>
       <CardHeader className="shrink-0">
         <div className="flex items-center justify-between">
           <div>
             <CardTitle>Select Torrent</CardTitle>
             <CardDescription>
-              {filteredTorrents.length} of {torrents.length} torrents
+              {filteredTorrents.length} of {torrents.length} torrents • Drop a .torrent here to add it pre-matched
             </CardDescription>
           </div>
-          <Button variant="outline" size="sm" onClick={loadTorrents} disabled={isLoading}>
-            Refresh
-          </Button>
+          <div className="flex gap-2">
+            <Button variant="outline" size="sm" onClick={handleBrowseTorrent}>
+              Add .torrent
+            </Button>
+            <Button variant="outline" size="sm" onClick={loadTorrents} disabled={isLoading}>
+              Refresh
+            </Button>
+          </div>
         </div>
       </CardHeader>
       <CardContent className="flex-1 flex flex-col min-h-0 gap-4">
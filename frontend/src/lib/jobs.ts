@@ -0,0 +1,30 @@
+import { Events } from '@wailsio/runtime'
+import { JobService } from '../../bindings/qbt-file-matcher/backend'
+import type { Job } from '../../bindings/qbt-file-matcher/backend/models'
+
+/** Resolves once a background job is no longer running, reporting updates on the way */
+export function waitForJob(id: string, onUpdate?: (job: Job) => void): Promise<Job> {
+  return new Promise((resolve, reject) => {
+    let done = false
+    const finish = (job: Job) => {
+      if (done || job.status === 'running') return
+      done = true
+      off()
+      resolve(job)
+    }
+
+    const off = Events.On('job-updated', (event) => {
+      if (event.data.id !== id) return
+      onUpdate?.(event.data)
+      finish(event.data)
+    })
+
+    // The job may have finished before the listener was registered
+    JobService.GetJob(id).then(finish).catch((error) => {
+      if (done) return
+      done = true
+      off()
+      reject(error)
+    })
+  })
+}
@@ -12,12 +12,22 @@ import (
 	"qbt-file-matcher/backend"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/events"
 )
 
 //go:embed all:frontend/dist
 var assets embed.FS
 
+func init() {
+	// Paths of files dropped onto a drop target in the window
+	application.RegisterEvent[[]string]("files-dropped")
+	// A background job changed (results are fetched separately)
+	application.RegisterEvent[backend.Job]("job-updated")
+}
+
 func main() {
+	os.Args = setupMetrics(os.Args)
+
 	// Check if running in CLI mode based on recognized commands
 	// If unrecognized arguments are passed, default to GUI mode
 	if len(os.Args) > 1 && isCLICommand(os.Args[1]) {
@@ -31,30 +41,49 @@ func main() {
 
 func runGUI() {
 	// Create service instances
-	qbitService := &backend.QBitService{}
+	qbitService := &backend.QBitService{JournalDir: renameJournalDir()}
 	matcherService := &backend.MatcherService{}
+	jobService := &backend.JobService{Jobs: newJobManager(), QBit: qbitService}
+
+	// Notify on the desktop and through any notifiers in QBT_NOTIFY
+	notifyServices, notifiers := desktopNotifications()
+	if remote, err := backend.ParseNotifiers(notifySpecsFromEnv()); err != nil {
+		log.Printf("Ignoring QBT_NOTIFY: %v", err)
+	} else {
+		notifiers = append(notifiers, remote...)
+	}
+	notifyService := &backend.NotifyService{Notifier: notifiers}
+	jobService.Notifier = notifiers
 
 	// Create the application
 	app := application.New(application.Options{
 		Name:        "qBittorrent File Matcher",
 		Description: "Match torrents with files on disk",
-		Services: []application.Service{
+		Services: append([]application.Service{
 			application.NewService(qbitService),
 			application.NewService(matcherService),
-		},
+			application.NewService(jobService),
+			application.NewService(notifyService),
+		}, notifyServices...),
 		Assets: application.AssetOptions{
-			Handler: application.AssetFileServerFS(assets),
+			Handler:    application.AssetFileServerFS(assets),
+			Middleware: apiMiddleware(qbitService, matcherService, jobService),
 		},
 		Mac: application.MacOptions{
 			ApplicationShouldTerminateAfterLastWindowClosed: true,
 		},
 	})
 
+	jobService.Jobs.OnChange = func(job backend.Job) {
+		app.Event.Emit("job-updated", job)
+	}
+
 	// Create the main window
-	app.Window.NewWithOptions(application.WebviewWindowOptions{
-		Title:  fmt.Sprintf("qBittorrent File Matcher v%s", getAppVersion()),
-		Width:  1200,
-		Height: 800,
+	window := app.Window.NewWithOptions(application.WebviewWindowOptions{
+		Title:          fmt.Sprintf("qBittorrent File Matcher v%s", getAppVersion()),
+		Width:          1200,
+		Height:         800,
+		EnableFileDrop: true,
 		Mac: application.MacWindow{
 			InvisibleTitleBarHeight: 50,
 			Backdrop:                application.MacBackdropTranslucent,
@@ -64,6 +93,11 @@ func runGUI() {
 		URL:              "/",
 	})
 
+	// Forward dropped files (e.g. .torrent files) to the frontend
+	window.OnWindowEvent(events.Common.WindowFilesDropped, func(event *application.WindowEvent) {
+		app.Event.Emit("files-dropped", event.Context().DroppedFiles())
+	})
+
 	// Run the application
 	err := app.Run()
 	if err != nil {
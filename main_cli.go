@@ -7,6 +7,8 @@ import (
 )
 
 func main() {
+	os.Args = setupMetrics(os.Args)
+
 	// CLI-only mode for Windows
 	// This binary doesn't include Wails/WebView, so always run CLI
 	if len(os.Args) < 2 {
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"qbt-file-matcher/backend"
+)
+
+// setupMetrics removes a global --metrics-addr flag from args and, when it or
+// QBT_METRICS_ADDR is set, serves Prometheus metrics on /metrics. It runs
+// before command dispatch so the flag works for the GUI and every command.
+func setupMetrics(args []string) []string {
+	addr, rest := extractMetricsAddr(args)
+	if addr == "" {
+		addr = os.Getenv("QBT_METRICS_ADDR")
+	}
+	if addr != "" {
+		startMetricsServer(addr)
+	}
+	return rest
+}
+
+// extractMetricsAddr returns the --metrics-addr value and args without it
+func extractMetricsAddr(args []string) (string, []string) {
+	var addr string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--metrics-addr" && i+1 < len(args):
+			addr = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--metrics-addr="):
+			addr = strings.TrimPrefix(args[i], "--metrics-addr=")
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return addr, rest
+}
+
+func startMetricsServer(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", backend.MetricsHandler())
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("Metrics server failed: %v", err)
+		}
+	}()
+	log.Printf("Serving metrics on %s/metrics", addr)
+}
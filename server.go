@@ -0,0 +1,48 @@
+//go:build server
+
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"qbt-file-matcher/backend"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// apiMiddleware serves the JSON API under /api/ next to the web UI when built
+// in server mode, and requires the same token for the web UI and its service
+// bindings. The token is read from QBT_API_TOKEN, or generated and logged
+// when unset.
+func apiMiddleware(qbitService *backend.QBitService, matcherService *backend.MatcherService, jobService *backend.JobService) application.Middleware {
+	token := os.Getenv("QBT_API_TOKEN")
+	if token == "" {
+		generated, err := backend.GenerateAPIToken()
+		if err != nil {
+			log.Fatal(err)
+		}
+		token = generated
+		log.Printf("QBT_API_TOKEN not set, generated API token: %s", token)
+		log.Printf("Open the web UI with ?token=%s", token)
+	}
+
+	api := backend.NewAPIServer(qbitService, matcherService, jobService, token).Handler()
+	return func(next http.Handler) http.Handler {
+		web := backend.RequireWebToken(token, next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasPrefix(r.URL.Path, "/api/") {
+				api.ServeHTTP(w, r)
+				return
+			}
+			web.ServeHTTP(w, r)
+		})
+	}
+}
+
+// desktopNotifications returns nothing in server mode, which has no desktop
+func desktopNotifications() ([]application.Service, backend.Notifiers) {
+	return nil, nil
+}
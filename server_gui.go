@@ -0,0 +1,45 @@
+//go:build !server && !cli
+
+package main
+
+import (
+	"context"
+
+	"qbt-file-matcher/backend"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+	"github.com/wailsapp/wails/v3/pkg/services/notifications"
+)
+
+// apiMiddleware is a no-op for the desktop app; the API is only served in
+// server mode
+func apiMiddleware(*backend.QBitService, *backend.MatcherService, *backend.JobService) application.Middleware {
+	return nil
+}
+
+// desktopNotifications returns the service showing desktop notifications and
+// a notifier using it
+func desktopNotifications() ([]application.Service, backend.Notifiers) {
+	service := notifications.New()
+	return []application.Service{application.NewService(service)},
+		backend.Notifiers{desktopNotifier{service: service}}
+}
+
+// desktopNotifier shows notifications through the operating system
+type desktopNotifier struct {
+	service *notifications.NotificationService
+}
+
+func (d desktopNotifier) Notify(_ context.Context, n backend.Notification) error {
+	// Only macOS asks the user; elsewhere this is always granted
+	if authorized, _ := d.service.CheckNotificationAuthorization(); !authorized {
+		if granted, err := d.service.RequestNotificationAuthorization(); err != nil || !granted {
+			return err
+		}
+	}
+	return d.service.SendNotification(notifications.NotificationOptions{
+		ID:    n.Kind + "-" + n.Hash,
+		Title: n.Title(),
+		Body:  n.Message,
+	})
+}